@@ -0,0 +1,10 @@
+// Package api embeds the OpenAPI document describing the HTTP API, so the
+// server can serve it and validate traffic against it.
+package api
+
+import _ "embed"
+
+// OpenAPI is the contents of openapi.yaml.
+//
+//go:embed openapi.yaml
+var OpenAPI []byte
@@ -0,0 +1,41 @@
+// Command errorsgen writes the error codes registered with errorx into the
+// error schemas of the OpenAPI document. Run it with
+// `go generate ./internal/errorx` after registering a code; a bounded
+// context with its own codes must be imported below to be included.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+
+	_ "github.com/FormalYou/clean-architecture-blog/internal/application/usecase/articleerr"
+	_ "github.com/FormalYou/clean-architecture-blog/internal/application/usecase/usererr"
+	"github.com/FormalYou/clean-architecture-blog/internal/errorx"
+)
+
+func main() {
+	spec := flag.String("spec", "api/openapi.yaml", "path of the OpenAPI document to update")
+	flag.Parse()
+
+	if err := run(*spec); err != nil {
+		fmt.Fprintln(os.Stderr, "errorsgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(path string) error {
+	spec, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	synced, err := errorx.SyncOpenAPI(spec)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if bytes.Equal(spec, synced) {
+		return nil
+	}
+	return os.WriteFile(path, synced, 0o644)
+}
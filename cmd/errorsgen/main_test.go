@@ -0,0 +1,30 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FormalYou/clean-architecture-blog/internal/errorx"
+)
+
+func TestOpenAPISpecUpToDate(t *testing.T) {
+	spec, err := os.ReadFile("../../api/openapi.yaml")
+	require.NoError(t, err)
+
+	synced, err := errorx.SyncOpenAPI(spec)
+
+	require.NoError(t, err)
+	assert.Equal(t, string(synced), string(spec), "api/openapi.yaml is out of date, run `go generate ./internal/errorx`")
+	assert.Contains(t, errorx.OpenAPISchemas(), "        - 30001\n", "codes of every bounded context are included")
+}
+
+func TestCatalogue_EveryCodeTranslated(t *testing.T) {
+	for _, entry := range errorx.Catalogue() {
+		for _, locale := range errorx.Locales() {
+			assert.Contains(t, entry.Messages, locale, "code %d has no %s translation", entry.Code, locale)
+		}
+	}
+}
@@ -1,21 +1,78 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
 	"go.uber.org/zap"
 
 	"github.com/FormalYou/clean-architecture-blog/cmd/server/option"
-	zaplog "github.com/FormalYou/clean-architecture-blog/internal/infrastructure/log"
 )
 
 func main() {
-	// Defer logger sync
-	defer zaplog.GetLogger().Sync()
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(os.Args[2:]))
+	}
+
+	app := option.NewApp("./configs")
+	logger := app.Logger
+
+	srv := app.NewHTTPServer()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Start Server
+	serverErr := make(chan error, 1)
+	go func() {
+		logger.Info("Starting server", zap.String("addr", srv.Addr))
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+		close(serverErr)
+	}()
 
-	router := option.SetupRouter("./configs")
+	// Start the gRPC server on its own port; Serve returns nil once stopped.
+	grpcErr := make(chan error, 1)
+	if app.GRPC != nil {
+		lis, err := net.Listen("tcp", app.GRPCAddr())
+		if err != nil {
+			logger.Fatal("could not listen for gRPC", zap.String("addr", app.GRPCAddr()), zap.Error(err))
+		}
+		go func() {
+			logger.Info("Starting gRPC server", zap.String("addr", lis.Addr().String()))
+			if err := app.GRPC.Serve(lis); err != nil {
+				grpcErr <- err
+			}
+		}()
+	}
+
+	select {
+	case err := <-serverErr:
+		if err != nil {
+			logger.Fatal("could not run server", zap.Error(err))
+		}
+	case err := <-grpcErr:
+		logger.Fatal("could not run gRPC server", zap.Error(err))
+	case <-ctx.Done():
+		logger.Info("shutdown signal received")
+	}
+	stop()
+
+	timeout := time.Duration(app.Config.Server.ShutdownTimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
 
-	// 6. Start Server
-	zaplog.GetLogger().Info("Starting server on port 8080")
-	if err := router.Run(":8080"); err != nil {
-		zaplog.GetLogger().Fatal("could not run server", zap.Error(err))
+	if err := app.Shutdown(shutdownCtx, srv); err != nil {
+		logger.Error("graceful shutdown failed", zap.Error(err))
 	}
 }
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/FormalYou/clean-architecture-blog/cmd/server/option"
+	"github.com/FormalYou/clean-architecture-blog/internal/infrastructure/config"
+	gorm_infra "github.com/FormalYou/clean-architecture-blog/internal/infrastructure/persistence/gorm"
+	"github.com/FormalYou/clean-architecture-blog/internal/infrastructure/persistence/migrations"
+)
+
+const migrateUsage = `usage: server migrate [-config dir] [-dir dir] <command>
+
+commands:
+  up             apply all pending migrations
+  down [steps]   roll back the last steps migrations (default 1)
+  status         list migrations and whether they are applied
+  create <name>  write empty up/down files for every driver`
+
+// runMigrate implements the "migrate" subcommand and returns the exit code.
+func runMigrate(args []string) int {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	configPath := fs.String("config", "./configs", "directory containing config.yaml")
+	dir := fs.String("dir", "internal/infrastructure/persistence/migrations", "migrations source directory (create only)")
+	fs.Usage = func() { fmt.Fprintln(os.Stderr, migrateUsage) }
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	if err := migrate(*configPath, *dir, fs.Arg(0), fs.Args()[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "migrate:", err)
+		return 1
+	}
+	return 0
+}
+
+func migrate(configPath, dir, command string, args []string) error {
+	if command == "create" {
+		if len(args) != 1 {
+			return fmt.Errorf("create needs exactly one name")
+		}
+		files, err := migrations.Create(dir, args[0])
+		for _, f := range files {
+			fmt.Println("created", f)
+		}
+		return err
+	}
+
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	db, err := gorm_infra.NewDB(option.DSNConfig(cfg))
+	if err != nil {
+		return fmt.Errorf("connecting to database: %w", err)
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		defer sqlDB.Close()
+	}
+	migrator, err := migrations.New(db, cfg.Database.Driver)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	switch command {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			fmt.Printf("applied  %04d_%s\n", m.Version, m.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Println("database is up to date")
+		}
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 0 {
+			if steps, err = strconv.Atoi(args[0]); err != nil || steps < 1 {
+				return fmt.Errorf("invalid step count %q", args[0])
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		for _, m := range reverted {
+			fmt.Printf("reverted %04d_%s\n", m.Version, m.Name)
+		}
+		return err
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			state := "pending"
+			if s.Applied {
+				state = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%-30s %s\n", s.Version, s.Name, state)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown command %q\n%s", command, migrateUsage)
+	}
+}
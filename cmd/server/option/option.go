@@ -1,22 +1,155 @@
 package option
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"path/filepath"
 	"time"
 
+	"github.com/FormalYou/clean-architecture-blog/api"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/contracts"
 	"github.com/FormalYou/clean-architecture-blog/internal/application/usecase"
 	"github.com/FormalYou/clean-architecture-blog/internal/infrastructure/auth"
 	"github.com/FormalYou/clean-architecture-blog/internal/infrastructure/cache"
 	"github.com/FormalYou/clean-architecture-blog/internal/infrastructure/config"
+	"github.com/FormalYou/clean-architecture-blog/internal/infrastructure/health"
 	zaplog "github.com/FormalYou/clean-architecture-blog/internal/infrastructure/log"
 	gorm_infra "github.com/FormalYou/clean-architecture-blog/internal/infrastructure/persistence/gorm"
+	"github.com/FormalYou/clean-architecture-blog/internal/infrastructure/persistence/migrations"
+	"github.com/FormalYou/clean-architecture-blog/internal/infrastructure/tracing"
+	gql "github.com/FormalYou/clean-architecture-blog/internal/interfaces/graphql"
+	grpcapi "github.com/FormalYou/clean-architecture-blog/internal/interfaces/grpc"
 	"github.com/FormalYou/clean-architecture-blog/internal/interfaces/http/handler"
 	"github.com/FormalYou/clean-architecture-blog/internal/interfaces/http/handler/middleware"
+	"github.com/FormalYou/clean-architecture-blog/internal/interfaces/shared"
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"gorm.io/gorm"
 )
 
+// App holds the assembled router together with the resources that must be
+// released on shutdown.
+type App struct {
+	Config   config.Config
+	Router   *gin.Engine
+	Health   *health.Service
+	DB       *gorm.DB
+	Replicas *gorm_infra.ReplicaSet
+	Redis    *redis.Client
+	// Articles refreshes stale cache entries in the background; its Close
+	// waits for them before the cache and the database go away.
+	Articles *usecase.ArticleUsecase
+	// ArticleCache is the two-tier article cache, nil unless cache.local_size is set.
+	ArticleCache *cache.TieredArticleCache
+	// GRPC serves the gRPC API on GRPCAddr, nil unless grpc.enabled is set.
+	GRPC   *grpc.Server
+	Logger *zap.Logger
+}
+
+// DSNConfig builds the database connection settings from the configuration.
+func DSNConfig(cfg config.Config) gorm_infra.DSNConfig {
+	return gorm_infra.DSNConfig{
+		Driver:   cfg.Database.Driver,
+		User:     cfg.Database.User,
+		Password: cfg.Database.Password,
+		Host:     cfg.Database.Host,
+		Port:     cfg.Database.Port,
+		DBName:   cfg.Database.DBName,
+		SSLMode:  cfg.Database.SSLMode,
+	}
+}
+
+// QueryTimeouts builds the repository query timeouts from the configuration.
+func QueryTimeouts(cfg config.Config) gorm_infra.QueryTimeouts {
+	timeouts := gorm_infra.QueryTimeouts{
+		Default:    time.Duration(cfg.Database.QueryTimeoutMS) * time.Millisecond,
+		Operations: make(map[string]time.Duration, len(cfg.Database.QueryTimeoutsMS)),
+	}
+	for op, ms := range cfg.Database.QueryTimeoutsMS {
+		timeouts.Operations[op] = time.Duration(ms) * time.Millisecond
+	}
+	return timeouts
+}
+
+// ArticleCachePolicy builds the article cache policy from cfg, keeping the
+// default for every unset value.
+func ArticleCachePolicy(cfg config.Config) usecase.ArticleCachePolicy {
+	policy := usecase.DefaultArticleCachePolicy
+	set := func(dst *time.Duration, n int, unit time.Duration) {
+		if n > 0 {
+			*dst = time.Duration(n) * unit
+		}
+	}
+	set(&policy.FreshTTL, cfg.Cache.ArticleTTLSeconds, time.Second)
+	set(&policy.StaleTTL, cfg.Cache.ArticleStaleSeconds, time.Second)
+	set(&policy.NotFoundTTL, cfg.Cache.NotFoundTTLSeconds, time.Second)
+	set(&policy.ListTTL, cfg.Cache.ListTTLSeconds, time.Second)
+	set(&policy.LockTTL, cfg.Cache.LockTTLMS, time.Millisecond)
+	set(&policy.LockWait, cfg.Cache.LockWaitMS, time.Millisecond)
+	return policy
+}
+
+// RateLimitRules builds the rate limit rules from cfg; with rate_limit.enabled
+// off there are no policies and nothing is limited.
+func RateLimitRules(cfg config.Config) shared.RateLimitRules {
+	rules := shared.RateLimitRules{
+		Policies:      make(map[string]shared.RateLimitPolicy, len(cfg.RateLimit.Policies)),
+		ExemptUserIDs: cfg.RateLimit.ExemptUserIDs,
+		TrustedScopes: cfg.RateLimit.TrustedScopes,
+	}
+	if !cfg.RateLimit.Enabled {
+		return rules
+	}
+	for group, p := range cfg.RateLimit.Policies {
+		rules.Policies[group] = shared.RateLimitPolicy{
+			Limit:  p.Limit,
+			Window: time.Duration(p.WindowSeconds) * time.Second,
+			KeyBy:  p.KeyBy,
+		}
+	}
+	return rules
+}
+
+// IdempotencyOptions builds the Idempotency-Key settings from cfg.
+func IdempotencyOptions(cfg config.Config) shared.IdempotencyOptions {
+	opts := shared.IdempotencyOptions{TTL: 24 * time.Hour, LockTTL: time.Minute}
+	if cfg.Idempotency.TTLSeconds > 0 {
+		opts.TTL = time.Duration(cfg.Idempotency.TTLSeconds) * time.Second
+	}
+	if cfg.Idempotency.LockTTLSeconds > 0 {
+		opts.LockTTL = time.Duration(cfg.Idempotency.LockTTLSeconds) * time.Second
+	}
+	return opts
+}
+
+// checkSchema refuses to run against a database with pending migrations.
+func checkSchema(db *gorm.DB, driver string) error {
+	migrator, err := migrations.New(db, driver)
+	if err != nil {
+		return err
+	}
+	pending, err := migrator.Pending(context.Background())
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%d pending migration(s), starting with %04d_%s; run \"server migrate up\"",
+			len(pending), pending[0].Version, pending[0].Name)
+	}
+	return nil
+}
+
+// SetupRouter builds the application and returns only its router.
 func SetupRouter(configPath string) *gin.Engine {
+	return NewApp(configPath).Router
+}
+
+// NewApp loads the configuration, connects the dependencies and wires the router.
+func NewApp(configPath string) *App {
 	// 1. Load Configuration
 	cfg, err := config.LoadConfig(configPath)
 	if err != nil {
@@ -29,69 +162,210 @@ func SetupRouter(configPath string) *gin.Engine {
 	// defer zapLogger.Sync() // Sync will be called in main
 	logger := zaplog.NewZapAdapter(zapLogger)
 
-	// 2. Initialize Database
-	dsnCfg := gorm_infra.DSNConfig{
-		User:     cfg.Database.User,
-		Password: cfg.Database.Password,
-		Host:     cfg.Database.Host,
-		Port:     cfg.Database.Port,
-		DBName:   cfg.Database.DBName,
-	}
-	db, err := gorm_infra.NewDB(dsnCfg)
-	if err != nil {
-		zapLogger.Fatal("could not connect to db", zap.Error(err))
-	}
+	rateLimits := shared.NewRateLimitSettings(RateLimitRules(cfg))
 
-	// 4. Dependency Injection
-	redisClient, err := cache.NewRedisClient()
+	// Reload safe keys when the config file changes
+	config.Watch(cfg, func(reloaded config.Config, restartRequired []string) {
+		rateLimits.Store(RateLimitRules(reloaded))
+		if err := zaplog.SetLevel(reloaded.Logger.Level); err != nil {
+			zapLogger.Warn("could not apply reloaded log level", zap.Error(err))
+		}
+		zapLogger.Info("configuration reloaded", zap.String("log_level", reloaded.Logger.Level))
+		if len(restartRequired) > 0 {
+			zapLogger.Warn("configuration changes require a restart", zap.Strings("sections", restartRequired))
+		}
+	}, func(err error) {
+		zapLogger.Error("ignoring invalid configuration change", zap.Error(err))
+	})
+
+	// Initialize Tracing
+	err = tracing.Init(context.Background(), tracing.Config{
+		Enabled:      cfg.Tracing.Enabled,
+		ServiceName:  cfg.Tracing.ServiceName,
+		Exporter:     cfg.Tracing.Exporter,
+		OTLPEndpoint: cfg.Tracing.OTLPEndpoint,
+		Insecure:     cfg.Tracing.Insecure,
+		SampleRatio:  cfg.Tracing.SampleRatio,
+	})
 	if err != nil {
-		zapLogger.Fatal("could not connect to redis", zap.Error(err))
+		zapLogger.Fatal("could not initialize tracing", zap.Error(err))
 	}
 
+	// 2. Initialize storage (database and Redis, or in-memory)
+	store := newStorage(cfg, zapLogger)
+
 	jwtAuth := auth.NewJWTAuthService(cfg.JWT.Secret)
 	jwtExpires := time.Duration(cfg.JWT.ExpiresInMinutes) * time.Minute
 
-	articleRepo := gorm_infra.NewGormArticleRepository(db)
-	articleCacheRepo := cache.NewArticleCacheRepository(redisClient)
-	articleUsecase := usecase.NewArticleUsecase(articleRepo, articleCacheRepo, jwtAuth, logger)
-	articleHandler := handler.NewArticleHandler(articleUsecase, zapLogger)
+	articles := usecase.NewArticleUsecase(store.articles, store.articleCache, store.tx, jwtAuth, logger,
+		usecase.WithArticleCachePolicy(ArticleCachePolicy(cfg)), usecase.WithCacheLocker(store.cacheLocker)).(*usecase.ArticleUsecase)
+	articleUsecase := tracing.TraceArticleUsecase(articles)
 
-	userRepo := gorm_infra.NewGormUserRepository(db)
 	// auditSvc := usecase.NewAuditService(logger)
-	userUsecase := usecase.NewUserUsecase(userRepo, jwtAuth, jwtExpires, logger)
+	userUsecase := tracing.TraceUserUsecase(usecase.NewUserUsecase(store.users, jwtAuth, jwtExpires, logger))
 	userHandler := handler.NewUserHandler(userUsecase, zapLogger)
+	articleHandler := handler.NewArticleHandler(articleUsecase, userUsecase, zapLogger, handler.WithCachePolicy(handler.CachePolicy{
+		Article:            cfg.HTTPCache.Article,
+		List:               cfg.HTTPCache.List,
+		SurrogateKeyHeader: cfg.HTTPCache.SurrogateKeyHeader,
+	}))
+
+	commentUsecase := tracing.TraceCommentUsecase(usecase.NewCommentUsecase(store.comments, logger))
 
-	commentRepo := gorm_infra.NewGormCommentRepository(db)
-	_ = commentRepo // Placeholder for future use
-	tagRepo := gorm_infra.NewGormTagRepository(db)
-	_ = tagRepo // Placeholder for future use
+	_ = store.tags // Placeholder for future use
+
+	checkers := append(store.checkers,
+		health.NewDiskChecker("log_disk", filepath.Dir(cfg.Logger.File.Filename), uint64(cfg.Health.DiskMinFreeMB)<<20))
+	healthSvc := health.NewService(
+		time.Duration(cfg.Health.CheckTimeoutMS)*time.Millisecond,
+		time.Duration(cfg.Health.CacheTTLMS)*time.Millisecond,
+		checkers...,
+	)
+	healthHandler := handler.NewHealthHandler(healthSvc)
 
 	authMiddleware := middleware.AuthMiddleware(jwtAuth, zapLogger)
 	errorHandler := middleware.ErrorHandler(zapLogger)
+	idempotent := middleware.Idempotency(store.idempotency, IdempotencyOptions(cfg), zapLogger)
+	rateLimit := func(group string) gin.HandlerFunc {
+		return middleware.RateLimit(group, store.rateLimiter, rateLimits, jwtAuth, zapLogger)
+	}
+
+	var graphqlHandler *gql.Handler
+	if cfg.GraphQL.Enabled {
+		graphqlHandler, err = gql.NewHandler(articleUsecase, userUsecase, commentUsecase, gql.Limits{
+			MaxDepth:      cfg.GraphQL.MaxDepth,
+			MaxComplexity: cfg.GraphQL.MaxComplexity,
+		}, zapLogger)
+		if err != nil {
+			zapLogger.Fatal("could not build the GraphQL schema", zap.Error(err))
+		}
+	}
+
+	var grpcServer *grpc.Server
+	if cfg.GRPC.Enabled {
+		var serverOpts []grpc.ServerOption
+		if cfg.GRPC.TLS.CertFile != "" {
+			creds, err := credentials.NewServerTLSFromFile(cfg.GRPC.TLS.CertFile, cfg.GRPC.TLS.KeyFile)
+			if err != nil {
+				zapLogger.Fatal("could not load the gRPC TLS certificate", zap.Error(err))
+			}
+			serverOpts = append(serverOpts, grpc.Creds(creds))
+		} else if !isLoopback(grpcAddr(cfg)) {
+			zapLogger.Warn("gRPC serves plaintext on a non-loopback address, set grpc.tls", zap.String("addr", grpcAddr(cfg)))
+		}
+		grpcServer = grpcapi.NewServer(articleUsecase, userUsecase, jwtAuth, grpcapi.Protections{
+			RateLimiter:        store.rateLimiter,
+			RateLimits:         rateLimits,
+			Idempotency:        store.idempotency,
+			IdempotencyOptions: IdempotencyOptions(cfg),
+		}, zapLogger, serverOpts...)
+	}
+
+	spec, err := middleware.NewOpenAPISpec(api.OpenAPI)
+	if err != nil {
+		zapLogger.Fatal("could not load the OpenAPI spec", zap.Error(err))
+	}
 
 	// 5. Setup Router
-	router := gin.Default()
-	router.Use(errorHandler)
+	// gin.Recovery stays outermost as a last resort; panics in handlers are
+	// caught by middleware.Recovery and rendered by the error handler.
+	router := gin.New()
+	// Client IPs key rate limits and anonymous idempotency keys, so
+	// forwarding headers are only believed from the configured proxies.
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		zapLogger.Fatal("invalid server.trusted_proxies", zap.Error(err))
+	}
+	router.Use(gin.Logger(), gin.Recovery())
+	router.Use(middleware.RequestID())
+	router.Use(tracing.GinMiddleware(cfg.Tracing.ServiceName))
+	if cfg.OpenAPI.ValidateResponses {
+		// Runs before the error handler so error responses are checked too.
+		if gin.Mode() == gin.TestMode {
+			router.Use(middleware.ValidateResponses(spec, zapLogger))
+		} else {
+			zapLogger.Warn("openapi.validate_responses is ignored outside gin test mode")
+		}
+	}
+	router.Use(errorHandler, middleware.Recovery(zapLogger))
+	if cfg.OpenAPI.ValidateRequests {
+		router.Use(middleware.ValidateRequests(spec))
+	}
+	router.HandleMethodNotAllowed = true
+	router.NoRoute(middleware.NoRoute())
+	router.NoMethod(middleware.NoMethod())
+
+	docsHandler := handler.NewDocsHandler(api.OpenAPI)
+	router.GET("/api/openapi.yaml", docsHandler.Spec)
+	router.GET("/api/docs", docsHandler.Page)
+
+	router.GET("/healthz", healthHandler.Liveness)
+	router.GET("/readyz", healthHandler.Readiness)
+	var cacheStats contracts.CacheStatsProvider
+	if store.tiered != nil {
+		cacheStats = store.tiered
+	}
+	registerDebugRoutes(router, cfg, cacheStats)
+
+	// GraphQL lives outside /api/v1 and the OpenAPI spec; it serves
+	// anonymous queries and authenticated mutations from the same route.
+	if graphqlHandler != nil {
+		serveGraphQL := []gin.HandlerFunc{
+			middleware.OptionalAuthMiddleware(jwtAuth, zapLogger),
+			graphqlHandler.Limit(rateLimit("read"), rateLimit("write")),
+			graphqlHandler.Serve,
+		}
+		router.GET("/graphql", serveGraphQL...)
+		router.POST("/graphql", serveGraphQL...)
+	}
 
 	v1 := router.Group("/api/v1")
 	{
 		// User routes
-		v1.POST("/register", userHandler.Register)
-		v1.POST("/login", userHandler.Login)
+		v1.POST("/register", rateLimit("auth"), idempotent, userHandler.Register)
+		v1.POST("/login", rateLimit("auth"), userHandler.Login)
 
 		articles := v1.Group("/articles")
 		{
-			articles.GET("", articleHandler.GetAll)
-			articles.GET("/:id", articleHandler.GetByID)
+			articles.GET("", rateLimit("read"), articleHandler.GetAll)
+			articles.GET("/:id", rateLimit("read"), articleHandler.GetByID)
 
 			authorized := articles.Group("/")
-			authorized.Use(authMiddleware)
+			authorized.Use(authMiddleware, rateLimit("write"))
 			{
-				authorized.POST("", articleHandler.Create)
+				authorized.POST("", idempotent, articleHandler.Create)
 				authorized.PUT("/:id", articleHandler.Update)
 				authorized.DELETE("/:id", articleHandler.Delete)
 			}
 		}
+
+		// Error code catalogue; problem documents link here from their type
+		metaHandler := handler.NewMetaHandler()
+		v1.GET("/meta/errors", rateLimit("read"), metaHandler.Errors)
+		v1.GET("/meta/errors/:code", rateLimit("read"), metaHandler.Error)
+	}
+
+	return &App{
+		Config:       cfg,
+		Router:       router,
+		Health:       healthSvc,
+		DB:           store.db,
+		Replicas:     store.replicas,
+		Redis:        store.redis,
+		Articles:     articles,
+		ArticleCache: store.tiered,
+		GRPC:         grpcServer,
+		Logger:       zapLogger,
+	}
+}
+
+// registerDebugRoutes adds the operator endpoints under /debug/. They are
+// unauthenticated, so nothing is registered unless server.debug_endpoints is
+// set; cacheStats is nil when there is no tiered cache to report on.
+func registerDebugRoutes(router gin.IRouter, cfg config.Config, cacheStats contracts.CacheStatsProvider) {
+	if !cfg.Server.DebugEndpoints {
+		return
+	}
+	if cacheStats != nil {
+		router.GET("/debug/cache", handler.NewCacheHandler(cacheStats).Stats)
 	}
-	return router
 }
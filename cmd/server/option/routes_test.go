@@ -0,0 +1,238 @@
+package option
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FormalYou/clean-architecture-blog/api"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/contracts"
+	"github.com/FormalYou/clean-architecture-blog/internal/infrastructure/config"
+	"github.com/FormalYou/clean-architecture-blog/internal/interfaces/http/handler/middleware"
+)
+
+// TestRoutesMatchOpenAPISpec fails when a route under the API's base path is
+// missing from api/openapi.yaml, or the spec documents a route that is not
+// registered.
+func TestRoutesMatchOpenAPISpec(t *testing.T) {
+	app := newTestApp(t)
+
+	spec, err := middleware.NewOpenAPISpec(api.OpenAPI)
+	require.NoError(t, err)
+
+	var routes []string
+	for _, r := range app.Router.Routes() {
+		if !strings.HasPrefix(r.Path, "/api/v1/") {
+			continue
+		}
+		// Gin joins a group's trailing slash into its root route.
+		routes = append(routes, r.Method+" "+strings.TrimSuffix(r.Path, "/"))
+	}
+	sort.Strings(routes)
+
+	assert.Equal(t, spec.Routes(), routes, "api/openapi.yaml and the registered routes differ")
+}
+
+// TestResponsesMatchOpenAPISpec walks through the API with request and
+// response validation enabled, so any response the spec does not describe
+// turns into a 500.
+func TestResponsesMatchOpenAPISpec(t *testing.T) {
+	t.Setenv("BLOG_OPENAPI_VALIDATE_REQUESTS", "true")
+	t.Setenv("BLOG_OPENAPI_VALIDATE_RESPONSES", "true")
+	router := newTestApp(t).Router
+
+	do := func(method, path, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do(http.MethodPost, "/api/v1/register", "", `{"username":"alice","email":"alice@example.com","password":"password123"}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	w = do(http.MethodPost, "/api/v1/login", "", `{"email":"alice@example.com","password":"password123"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var login struct {
+		Token string `json:"token"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &login))
+
+	testCases := []struct {
+		name           string
+		method, path   string
+		token          bool
+		body           string
+		expectedStatus int
+	}{
+		{"Create Article", http.MethodPost, "/api/v1/articles/", true, `{"title":"Hello","content":"World","tags":["go"]}`, http.StatusCreated},
+		{"Create Article Without Title", http.MethodPost, "/api/v1/articles/", true, `{"content":"World"}`, http.StatusBadRequest},
+		{"Create Article Unauthorized", http.MethodPost, "/api/v1/articles/", false, `{"title":"Hello","content":"World"}`, http.StatusUnauthorized},
+		{"List Articles", http.MethodGet, "/api/v1/articles", false, "", http.StatusOK},
+		{"Get Article", http.MethodGet, "/api/v1/articles/1", false, "", http.StatusOK},
+		{"Get Missing Article", http.MethodGet, "/api/v1/articles/99", false, "", http.StatusNotFound},
+		{"Get Article With Invalid ID", http.MethodGet, "/api/v1/articles/abc", false, "", http.StatusBadRequest},
+		{"Update Article", http.MethodPut, "/api/v1/articles/1", true, `{"title":"Hi","content":"There"}`, http.StatusOK},
+		{"Delete Article", http.MethodDelete, "/api/v1/articles/1", true, "", http.StatusOK},
+		{"Wrong Password", http.MethodPost, "/api/v1/login", false, `{"email":"alice@example.com","password":"wrong"}`, http.StatusUnauthorized},
+		{"Error Catalogue", http.MethodGet, "/api/v1/meta/errors", false, "", http.StatusOK},
+		{"Error Code", http.MethodGet, "/api/v1/meta/errors/30001", false, "", http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			token := ""
+			if tc.token {
+				token = login.Token
+			}
+			w := do(tc.method, tc.path, token, tc.body)
+			assert.Equal(t, tc.expectedStatus, w.Code, w.Body.String())
+		})
+	}
+
+	t.Run("Conditional Get", func(t *testing.T) {
+		w := do(http.MethodPost, "/api/v1/articles/", login.Token, `{"title":"Cached","content":"Body"}`)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var article struct {
+			ID int64 `json:"id"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &article))
+		path := fmt.Sprintf("/api/v1/articles/%d", article.ID)
+
+		for _, path := range []string{path, "/api/v1/articles"} {
+			w = do(http.MethodGet, path, "", "")
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			etag := w.Header().Get("ETag")
+			require.NotEmpty(t, etag)
+			assert.NotEmpty(t, w.Header().Get("Cache-Control"))
+			assert.Contains(t, w.Header().Get("Surrogate-Key"), fmt.Sprintf("article-%d", article.ID))
+
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			req.Header.Set("If-None-Match", etag)
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusNotModified, w.Code, w.Body.String())
+			assert.Empty(t, w.Body.String())
+		}
+	})
+
+	t.Run("GraphQL", func(t *testing.T) {
+		// Outside the spec, so only the auth and transport wiring is checked.
+		const mutation = `{"query":"mutation { createArticle(input: {title: \"Graph\", content: \"Body\"}) { id author { username } } }"}`
+		graphqlCases := []struct {
+			name           string
+			token          string
+			body           string
+			expectedStatus int
+			expectedBody   string
+		}{
+			{"Mutation", login.Token, mutation, http.StatusOK, `"author":{"username":"alice"}`},
+			{"Anonymous Mutation", "", mutation, http.StatusOK, `"code":10003`},
+			{"Anonymous Query", "", `{"query":"{ articles { title } }"}`, http.StatusOK, `"title":"Graph"`},
+			{"Invalid Token", "not-a-token", `{"query":"{ articles { title } }"}`, http.StatusUnauthorized, ""},
+		}
+		for _, tc := range graphqlCases {
+			t.Run(tc.name, func(t *testing.T) {
+				w := do(http.MethodPost, "/graphql", tc.token, tc.body)
+				assert.Equal(t, tc.expectedStatus, w.Code, w.Body.String())
+				assert.Contains(t, w.Body.String(), tc.expectedBody)
+			})
+		}
+	})
+
+	t.Run("Spec", func(t *testing.T) {
+		w := do(http.MethodGet, "/api/openapi.yaml", "", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, api.OpenAPI, w.Body.Bytes())
+	})
+}
+
+// TestRateLimitIgnoresSpoofedForwardedFor checks that callers cannot get a
+// fresh rate limit bucket by sending a different X-Forwarded-For each time,
+// while the address a trusted proxy forwards is still used.
+func TestRateLimitIgnoresSpoofedForwardedFor(t *testing.T) {
+	login := func(router *gin.Engine, forwardedFor string) int {
+		// httptest requests come from 192.0.2.1.
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/login", strings.NewReader(`{"email":"eve@example.com","password":"guess"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	t.Run("Untrusted Peer", func(t *testing.T) {
+		router := newTestApp(t).Router
+		statuses := map[int]int{}
+		for i := 0; i < 11; i++ {
+			statuses[login(router, fmt.Sprintf("203.0.113.%d", i))]++
+		}
+		assert.Equal(t, map[int]int{http.StatusUnauthorized: 10, http.StatusTooManyRequests: 1}, statuses,
+			"the auth policy allows 10 logins per minute from the connecting address")
+	})
+
+	t.Run("Trusted Proxy", func(t *testing.T) {
+		t.Setenv("BLOG_SERVER_TRUSTED_PROXIES", "192.0.2.1")
+		router := newTestApp(t).Router
+		for i := 0; i < 11; i++ {
+			assert.Equal(t, http.StatusUnauthorized, login(router, fmt.Sprintf("203.0.113.%d", i)),
+				"each forwarded client has its own limit")
+		}
+	})
+}
+
+// TestDebugRoutesAreOptIn checks that the unauthenticated cache statistics are
+// only served once server.debug_endpoints is set.
+func TestDebugRoutesAreOptIn(t *testing.T) {
+	stats := fakeCacheStats{Local: contracts.CacheTierStats{Hits: 3, Misses: 1, HitRatio: 0.75}, LocalEntries: 2}
+	testCases := []struct {
+		name           string
+		enabled        bool
+		expectedStatus int
+	}{
+		{"Disabled", false, http.StatusNotFound},
+		{"Enabled", true, http.StatusOK},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			var cfg config.Config
+			cfg.Server.DebugEndpoints = tc.enabled
+			router := gin.New()
+			registerDebugRoutes(router, cfg, stats)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/cache", nil))
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.enabled {
+				var body contracts.CacheStats
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+				assert.Equal(t, contracts.CacheStats(stats), body)
+			}
+		})
+	}
+}
+
+type fakeCacheStats contracts.CacheStats
+
+func (s fakeCacheStats) Stats() contracts.CacheStats { return contracts.CacheStats(s) }
+
+func newTestApp(t *testing.T) *App {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	t.Setenv("BLOG_STORAGE", "memory")
+	t.Setenv("BLOG_LOGGER_FILE_FILENAME", filepath.Join(t.TempDir(), "app.log"))
+	return NewApp("../../../configs")
+}
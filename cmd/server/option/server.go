@@ -0,0 +1,140 @@
+package option
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/FormalYou/clean-architecture-blog/internal/infrastructure/config"
+	"github.com/FormalYou/clean-architecture-blog/internal/infrastructure/tracing"
+)
+
+// NewHTTPServer builds an http.Server from the server configuration.
+func (a *App) NewHTTPServer() *http.Server {
+	cfg := a.Config.Server
+	addr := cfg.Addr
+	if addr == "" {
+		addr = ":8080"
+	}
+	return &http.Server{
+		Addr:              addr,
+		Handler:           a.Router,
+		ReadTimeout:       time.Duration(cfg.ReadTimeoutSeconds) * time.Second,
+		ReadHeaderTimeout: time.Duration(cfg.ReadHeaderTimeoutSeconds) * time.Second,
+		WriteTimeout:      time.Duration(cfg.WriteTimeoutSeconds) * time.Second,
+		IdleTimeout:       time.Duration(cfg.IdleTimeoutSeconds) * time.Second,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+	}
+}
+
+// GRPCAddr is the address the gRPC server listens on.
+func (a *App) GRPCAddr() string {
+	return grpcAddr(a.Config)
+}
+
+// grpcAddr is grpc.addr, by default only reachable from the same host.
+func grpcAddr(cfg config.Config) string {
+	if cfg.GRPC.Addr == "" {
+		return "127.0.0.1:9090"
+	}
+	return cfg.GRPC.Addr
+}
+
+// isLoopback reports whether addr only accepts connections from the same host.
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// Shutdown drains the HTTP and gRPC servers and then releases the application resources.
+// Readiness is flipped first so the orchestrator stops routing new traffic,
+// after which in-flight requests get until ctx's deadline to finish.
+func (a *App) Shutdown(ctx context.Context, srv *http.Server) error {
+	a.Health.SetDraining(true)
+
+	if delay := time.Duration(a.Config.Server.DrainDelaySeconds) * time.Second; delay > 0 {
+		a.Logger.Info("waiting for load balancers to observe not-ready", zap.Duration("delay", delay))
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+		}
+	}
+
+	var errs []error
+	if err := srv.Shutdown(ctx); err != nil {
+		errs = append(errs, err)
+	}
+
+	if a.GRPC != nil {
+		stopped := make(chan struct{})
+		go func() {
+			a.GRPC.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+			// Cancel the calls still running rather than outlive the deadline.
+			a.GRPC.Stop()
+			errs = append(errs, ctx.Err())
+		}
+	}
+
+	// Background cache refreshes still read the database and write the cache.
+	if a.Articles != nil {
+		if err := a.Articles.Close(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("wait for article cache refreshes: %w", err))
+		}
+	}
+
+	if a.Replicas != nil {
+		if err := a.Replicas.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if a.DB != nil {
+		if sqlDB, err := a.DB.DB(); err != nil {
+			errs = append(errs, err)
+		} else if err := sqlDB.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// Stop listening for invalidations before the Redis client goes away.
+	if a.ArticleCache != nil {
+		a.ArticleCache.Close()
+	}
+
+	if a.Redis != nil {
+		if err := a.Redis.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if err := tracing.Shutdown(ctx); err != nil {
+		errs = append(errs, err)
+	}
+
+	err := errors.Join(errs...)
+	if err != nil {
+		a.Logger.Error("shutdown finished with errors", zap.Error(err))
+	} else {
+		a.Logger.Info("shutdown complete")
+	}
+	// Sync errors on stdout are expected on some platforms and are ignored.
+	_ = a.Logger.Sync()
+	return err
+}
@@ -0,0 +1,225 @@
+package option
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/FormalYou/clean-architecture-blog/internal/application/contracts"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/repository"
+	"github.com/FormalYou/clean-architecture-blog/internal/infrastructure/cache"
+	"github.com/FormalYou/clean-architecture-blog/internal/infrastructure/config"
+	"github.com/FormalYou/clean-architecture-blog/internal/infrastructure/health"
+	gorm_infra "github.com/FormalYou/clean-architecture-blog/internal/infrastructure/persistence/gorm"
+	"github.com/FormalYou/clean-architecture-blog/internal/infrastructure/persistence/memory"
+	"github.com/FormalYou/clean-architecture-blog/internal/infrastructure/ratelimit"
+	"github.com/FormalYou/clean-architecture-blog/internal/infrastructure/tracing"
+)
+
+// storage holds the repositories selected by the storage setting, together
+// with the connections to close on shutdown and their readiness checks.
+type storage struct {
+	articles     repository.ArticleRepository
+	users        repository.UserRepository
+	tags         repository.TagRepository
+	comments     repository.CommentRepository
+	articleCache repository.ArticleCacheRepository
+	cacheLocker  repository.CacheLocker // nil in memory mode
+	tx           repository.TxManager
+	rateLimiter  contracts.RateLimiter
+	idempotency  repository.IdempotencyStore
+
+	db       *gorm.DB                  // nil in memory mode
+	replicas *gorm_infra.ReplicaSet    // nil in memory mode
+	redis    *redis.Client             // nil in memory mode
+	tiered   *cache.TieredArticleCache // nil unless cache.local_size is set
+	checkers []health.Checker
+}
+
+// newStorage builds the configured storage and exits if it is unavailable.
+func newStorage(cfg config.Config, zapLogger *zap.Logger) storage {
+	if cfg.Storage == config.StorageMemory {
+		zapLogger.Warn("using in-memory storage; data is lost on restart")
+		store := memory.NewStore()
+		return storage{
+			articles:     memory.NewArticleRepository(store),
+			users:        memory.NewUserRepository(store),
+			tags:         memory.NewTagRepository(store),
+			comments:     memory.NewCommentRepository(store),
+			articleCache: cache.NewMemoryArticleCacheRepository(),
+			tx:           memory.NewTxManager(),
+			rateLimiter:  ratelimit.NewMemoryLimiter(),
+			idempotency:  cache.NewMemoryIdempotencyStore(),
+		}
+	}
+
+	db, err := gorm_infra.NewDB(DSNConfig(cfg))
+	if err != nil {
+		zapLogger.Fatal("could not connect to db", zap.Error(err))
+	}
+	if err := checkSchema(db, cfg.Database.Driver); err != nil {
+		zapLogger.Fatal("database schema is not up to date", zap.Error(err))
+	}
+	if err := tracing.InstrumentGorm(db); err != nil {
+		zapLogger.Fatal("could not instrument db", zap.Error(err))
+	}
+
+	redisClient, breaker := newRedisClient(cfg, zapLogger)
+
+	replicas := newReplicaSet(cfg, db, redisClient, zapLogger)
+	replicas.Start()
+
+	// Redis 只影响命中率：不可用时降级到进程内缓存，恢复后重放期间错过的失效
+	fallbackTTL := 10 * time.Second
+	if cfg.Cache.FallbackTTLSeconds > 0 {
+		fallbackTTL = time.Duration(cfg.Cache.FallbackTTLSeconds) * time.Second
+	}
+	fallback := cache.NewFallbackArticleCache(cache.NewArticleCacheRepository(redisClient), cache.NewMemoryArticleCacheRepository(), fallbackTTL)
+	var articleCache repository.ArticleCacheRepository = fallback
+	tiered := newTieredCache(cfg, articleCache, redisClient, zapLogger)
+	if tiered != nil {
+		articleCache = tiered
+	}
+
+	// 与缓存一样，Redis 不可用时退化为按实例限流
+	rateLimiter := ratelimit.NewFallbackLimiter(ratelimit.NewRedisLimiter(redisClient), ratelimit.NewMemoryLimiter())
+
+	repoOpts := []gorm_infra.RepositoryOption{
+		gorm_infra.WithQueryTimeouts(QueryTimeouts(cfg)),
+		gorm_infra.WithReplicas(replicas),
+	}
+	return storage{
+		articles:     gorm_infra.NewGormArticleRepository(db, repoOpts...),
+		users:        gorm_infra.NewGormUserRepository(db, repoOpts...),
+		tags:         gorm_infra.NewGormTagRepository(db, repoOpts...),
+		comments:     gorm_infra.NewGormCommentRepository(db, repoOpts...),
+		articleCache: articleCache,
+		cacheLocker:  cache.NewRedisLocker(redisClient),
+		tx:           gorm_infra.NewTxManager(db, cfg.Database.TxMaxRetries),
+		rateLimiter:  rateLimiter,
+		idempotency:  cache.NewRedisIdempotencyStore(redisClient),
+		db:           db,
+		replicas:     replicas,
+		redis:        redisClient,
+		tiered:       tiered,
+		checkers: []health.Checker{
+			health.NewDBChecker("database", db),
+			health.NewOptionalChecker(health.NewCheckerFunc("redis", func(ctx context.Context) error {
+				if err := redisClient.Ping(ctx).Err(); err != nil {
+					return fmt.Errorf("circuit %s, serving from in-memory cache: %w", breaker.State(), err)
+				}
+				return nil
+			})),
+		},
+	}
+}
+
+// newRedisClient creates the Redis client behind a circuit breaker. Redis
+// being down at startup is only logged: the client reconnects on its own and
+// callers degrade while the breaker is open.
+func newRedisClient(cfg config.Config, zapLogger *zap.Logger) (*redis.Client, *cache.CircuitBreaker) {
+	redisClient := redis.NewClient(cache.RedisOptions())
+	if err := tracing.InstrumentRedis(redisClient); err != nil {
+		zapLogger.Fatal("could not instrument redis", zap.Error(err))
+	}
+
+	breaker := cache.NewCircuitBreaker(cache.BreakerOptions{
+		FailureThreshold: cfg.Redis.BreakerFailures,
+		OpenTimeout:      time.Duration(cfg.Redis.BreakerOpenMS) * time.Millisecond,
+		OnStateChange: func(from, to cache.BreakerState) {
+			if to == cache.BreakerOpen {
+				zapLogger.Warn("redis circuit breaker opened; serving from in-memory cache", zap.Stringer("from", from))
+			} else {
+				zapLogger.Info("redis circuit breaker state changed", zap.Stringer("from", from), zap.Stringer("to", to))
+			}
+		},
+	})
+	redisClient.AddHook(breaker)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := redisClient.Ping(ctx).Err(); err != nil {
+		zapLogger.Warn("redis is unavailable; starting with the in-memory cache", zap.Error(err))
+	}
+	return redisClient, breaker
+}
+
+// newTieredCache puts an in-process LRU in front of remote when
+// cache.local_size is set, and returns nil otherwise.
+func newTieredCache(cfg config.Config, remote repository.ArticleCacheRepository, redisClient *redis.Client, zapLogger *zap.Logger) *cache.TieredArticleCache {
+	if cfg.Cache.LocalSize <= 0 {
+		return nil
+	}
+	localTTL := 30 * time.Second
+	if cfg.Cache.LocalTTLSeconds > 0 {
+		localTTL = time.Duration(cfg.Cache.LocalTTLSeconds) * time.Second
+	}
+	tiered := cache.NewTieredArticleCache(remote, redisClient, cache.TieredOptions{
+		Size:    cfg.Cache.LocalSize,
+		TTL:     localTTL,
+		Channel: cfg.Cache.InvalidationChannel,
+		OnError: func(err error) { zapLogger.Warn("cache invalidation broadcast failed", zap.Error(err)) },
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := tiered.Start(ctx); err != nil {
+		// Keeps retrying in the background; the local tier is bypassed until then.
+		zapLogger.Warn("could not subscribe to cache invalidations yet", zap.Error(err))
+	}
+	return tiered
+}
+
+// newReplicaSet connects to the configured read replicas. Unset replica
+// fields are taken from the primary's configuration. The read-your-writes
+// window is shared through Redis, since the cache it fills is shared too.
+func newReplicaSet(cfg config.Config, primary *gorm.DB, redisClient *redis.Client, zapLogger *zap.Logger) *gorm_infra.ReplicaSet {
+	var replicas []gorm_infra.Replica
+	for i, rc := range cfg.Database.Replicas {
+		dsn := DSNConfig(cfg)
+		dsn.Host = rc.Host
+		if rc.Port != "" {
+			dsn.Port = rc.Port
+		}
+		if rc.User != "" {
+			dsn.User = rc.User
+		}
+		if rc.Password != "" {
+			dsn.Password = rc.Password
+		}
+		if rc.DBName != "" {
+			dsn.DBName = rc.DBName
+		}
+		name := rc.Name
+		if name == "" {
+			name = fmt.Sprintf("replica-%d", i+1)
+		}
+
+		db, err := gorm_infra.NewDB(dsn)
+		if err != nil {
+			zapLogger.Fatal("could not connect to replica", zap.String("replica", name), zap.Error(err))
+		}
+		if err := tracing.InstrumentGorm(db); err != nil {
+			zapLogger.Fatal("could not instrument replica", zap.String("replica", name), zap.Error(err))
+		}
+		replicas = append(replicas, gorm_infra.Replica{Name: name, DB: db})
+	}
+
+	return gorm_infra.NewReplicaSet(primary, replicas, gorm_infra.ReplicaOptions{
+		CheckInterval:  time.Duration(cfg.Database.ReplicaCheckIntervalMS) * time.Millisecond,
+		MaxFailures:    cfg.Database.ReplicaMaxFailures,
+		ReadYourWrites: time.Duration(cfg.Database.ReadYourWritesMS) * time.Millisecond,
+		Writes:         cache.NewRedisWriteTracker(redisClient),
+		OnStateChange: func(name string, healthy bool, err error) {
+			if healthy {
+				zapLogger.Info("replica readmitted", zap.String("replica", name))
+			} else {
+				zapLogger.Warn("replica ejected", zap.String("replica", name), zap.Error(err))
+			}
+		},
+	})
+}
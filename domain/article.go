@@ -1,8 +1,6 @@
 package domain
 
-import (
-	"errors"
-)
+import "time"
 
 // Article 是文章的领域实体
 type Article struct {
@@ -11,19 +9,17 @@ type Article struct {
 	Content   string
 	AuthorID  int64
 	Tags      []Tag 
+	Version   int64 // 新建时为 1，每次更新加一
+	CreatedAt time.Time
+	UpdatedAt time.Time
 	
 }
 
-// Validate 检查文章实体的业务规则
+// Validate 检查文章实体的业务规则，返回的 *ValidationError 包含所有违规
 func (a *Article) Validate() error {
-	if a.Title == "" {
-		return errors.New("title is required")
-	}
-	if a.Content == "" {
-		return errors.New("content is required")
-	}
-	if a.AuthorID == 0 {
-		return errors.New("author is required")
-	}
-	return nil
-}
\ No newline at end of file
+	var v validation
+	v.check(a.Title != "", "title", ReasonRequired, "title is required")
+	v.check(a.Content != "", "content", ReasonRequired, "content is required")
+	v.check(a.AuthorID != 0, "author_id", ReasonRequired, "author is required")
+	return v.err()
+}
@@ -62,3 +62,17 @@ func TestArticle_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestArticle_ValidateCollectsEveryViolation(t *testing.T) {
+	err := (&Article{}).Validate()
+
+	var verr *ValidationError
+	if assert.ErrorAs(t, err, &verr) {
+		assert.Equal(t, []FieldViolation{
+			{Field: "title", Reason: ReasonRequired, Message: "title is required"},
+			{Field: "content", Reason: ReasonRequired, Message: "content is required"},
+			{Field: "author_id", Reason: ReasonRequired, Message: "author is required"},
+		}, verr.Violations)
+	}
+	assert.Equal(t, "title is required; content is required; author is required", err.Error())
+}
@@ -1,7 +1,6 @@
 package domain
 
 import (
-	"errors"
 	"regexp"
 )
 
@@ -16,15 +15,12 @@ type User struct {
 
 var emailRegex = regexp.MustCompile(`^[a-z0-9._%+\-]+@[a-z0-9.\-]+\.[a-z]{2,4}$`)
 
-// Validate 检查用户实体的业务规则
+// Validate 检查用户实体的业务规则，返回的 *ValidationError 包含所有违规
 func (u *User) Validate() error {
-	if u.Username == "" {
-		return errors.New("username is required")
-	}
-	if !emailRegex.MatchString(u.Email) {
-		return errors.New("invalid email format")
-	}
-	return nil
+	var v validation
+	v.check(u.Username != "", "username", ReasonRequired, "username is required")
+	v.check(emailRegex.MatchString(u.Email), "email", ReasonInvalidFormat, "invalid email format")
+	return v.err()
 }
 
 // UserProfile 存放用户的个人资料信息
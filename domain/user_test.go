@@ -50,3 +50,15 @@ func TestUser_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestUser_ValidateCollectsEveryViolation(t *testing.T) {
+	err := (&User{Email: "invalid-email"}).Validate()
+
+	var verr *ValidationError
+	if assert.ErrorAs(t, err, &verr) {
+		assert.Equal(t, []FieldViolation{
+			{Field: "username", Reason: ReasonRequired, Message: "username is required"},
+			{Field: "email", Reason: ReasonInvalidFormat, Message: "invalid email format"},
+		}, verr.Violations)
+	}
+}
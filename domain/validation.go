@@ -0,0 +1,48 @@
+package domain
+
+import "strings"
+
+// 违规原因，供客户端按字段做机器可读的处理
+const (
+	ReasonRequired      = "required"
+	ReasonInvalidFormat = "invalid_format"
+)
+
+// FieldViolation 描述一个字段违反的业务规则
+type FieldViolation struct {
+	Field   string
+	Reason  string
+	Message string
+}
+
+// ValidationError 汇总实体违反的所有业务规则
+type ValidationError struct {
+	Violations []FieldViolation
+}
+
+// Error 以分号连接所有违规的描述
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		messages[i] = v.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// validation 收集违规，没有违规时 err 返回 nil
+type validation struct {
+	violations []FieldViolation
+}
+
+func (v *validation) check(ok bool, field, reason, message string) {
+	if !ok {
+		v.violations = append(v.violations, FieldViolation{Field: field, Reason: reason, Message: message})
+	}
+}
+
+func (v *validation) err() error {
+	if len(v.violations) == 0 {
+		return nil
+	}
+	return &ValidationError{Violations: v.violations}
+}
@@ -7,4 +7,10 @@ type AuthService interface {
 	GenerateToken(userID int64) (string, error)
 	ValidateToken(tokenString string) (int64, error)
 	GetUserIDFromContext(ctx context.Context) (int64, error)
-}
\ No newline at end of file
+}
+
+// ScopeProvider is implemented by auth services whose tokens carry scopes,
+// e.g. service tokens minted for trusted internal clients.
+type ScopeProvider interface {
+	TokenScopes(tokenString string) ([]string, error)
+}
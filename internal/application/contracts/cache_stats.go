@@ -0,0 +1,21 @@
+package contracts
+
+// CacheTierStats counts lookups answered by one cache tier.
+type CacheTierStats struct {
+	Hits     uint64  `json:"hits"`
+	Misses   uint64  `json:"misses"`
+	HitRatio float64 `json:"hit_ratio"`
+}
+
+// CacheStats reports both tiers of the article cache. Remote lookups happen
+// only on local misses.
+type CacheStats struct {
+	Local        CacheTierStats `json:"local"`
+	Remote       CacheTierStats `json:"remote"`
+	LocalEntries int            `json:"local_entries"`
+}
+
+// CacheStatsProvider reports per-tier statistics of the article cache.
+type CacheStatsProvider interface {
+	Stats() CacheStats
+}
@@ -84,3 +84,42 @@ func (mr *MockAuthServiceMockRecorder) ValidateToken(tokenString any) *gomock.Ca
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateToken", reflect.TypeOf((*MockAuthService)(nil).ValidateToken), tokenString)
 }
+
+// MockScopeProvider is a mock of ScopeProvider interface.
+type MockScopeProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockScopeProviderMockRecorder
+	isgomock struct{}
+}
+
+// MockScopeProviderMockRecorder is the mock recorder for MockScopeProvider.
+type MockScopeProviderMockRecorder struct {
+	mock *MockScopeProvider
+}
+
+// NewMockScopeProvider creates a new mock instance.
+func NewMockScopeProvider(ctrl *gomock.Controller) *MockScopeProvider {
+	mock := &MockScopeProvider{ctrl: ctrl}
+	mock.recorder = &MockScopeProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScopeProvider) EXPECT() *MockScopeProviderMockRecorder {
+	return m.recorder
+}
+
+// TokenScopes mocks base method.
+func (m *MockScopeProvider) TokenScopes(tokenString string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TokenScopes", tokenString)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TokenScopes indicates an expected call of TokenScopes.
+func (mr *MockScopeProviderMockRecorder) TokenScopes(tokenString any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TokenScopes", reflect.TypeOf((*MockScopeProvider)(nil).TokenScopes), tokenString)
+}
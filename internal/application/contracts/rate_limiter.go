@@ -0,0 +1,22 @@
+package contracts
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimit is the outcome of a single rate limiter decision.
+type RateLimit struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is how long until the caller's full quota is available again.
+	Reset time.Duration
+	// RetryAfter is how long a rejected caller has to wait; zero when allowed.
+	RetryAfter time.Duration
+}
+
+// RateLimiter admits at most limit requests per window for each key.
+type RateLimiter interface {
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (RateLimit, error)
+}
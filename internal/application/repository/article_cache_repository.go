@@ -2,15 +2,57 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/FormalYou/clean-architecture-blog/domain"
 )
 
+// ErrStaleCacheEntry is returned by SetArticle and SetArticles for a value
+// loaded before the article or the lists were last invalidated. Nothing is
+// stored; it is not a cache failure.
+var ErrStaleCacheEntry = errors.New("cache entry predates the last invalidation")
+
+// ArticleCacheEntry 是缓存中的单篇文章。Article 为 nil 表示该 ID 不存在（负缓存）。
+// 超过 FreshUntil 后条目变为陈旧：仍可返回给调用方，但应在后台刷新。
+// Generation 是读取数据库之前由 ArticleGeneration 取得的失效代数。
+type ArticleCacheEntry struct {
+	Article    *domain.Article `json:"article"`
+	FreshUntil time.Time       `json:"fresh_until"`
+	Generation int64           `json:"generation"`
+}
+
+// Stale reports whether the entry is past its fresh period at now.
+func (e *ArticleCacheEntry) Stale(now time.Time) bool {
+	return !now.Before(e.FreshUntil)
+}
+
 type ArticleCacheRepository interface {
-	GetArticle(ctx context.Context, id uint) (*domain.Article, error)
-	SetArticle(ctx context.Context, article *domain.Article, expiration time.Duration) error
+	// GetArticle returns (nil, nil) on a miss.
+	GetArticle(ctx context.Context, id uint) (*ArticleCacheEntry, error)
+	// SetArticle stores entry under id until expiration, which bounds how long a
+	// stale entry can still be served. An entry whose Generation is no longer
+	// the article's current generation is refused with ErrStaleCacheEntry, so
+	// a load that read the database before a write cannot overwrite the
+	// write's invalidation.
+	SetArticle(ctx context.Context, id uint, entry *ArticleCacheEntry, expiration time.Duration) error
+	// ArticleGeneration returns the invalidation generation of id, to be read
+	// before loading the article and stored in the entry.
+	ArticleGeneration(ctx context.Context, id uint) (int64, error)
+	// GetArticles and SetArticles cache list and query results under key.
+	// Every such key is dropped together by InvalidateArticleLists.
 	GetArticles(ctx context.Context, key string) ([]*domain.Article, error)
-	SetArticles(ctx context.Context, key string, articles []*domain.Article, expiration time.Duration) error
+	// SetArticles refuses with ErrStaleCacheEntry a list whose generation is
+	// no longer the current list generation, so a list read from the
+	// database before a write cannot outlive the write's invalidation.
+	SetArticles(ctx context.Context, key string, articles []*domain.Article, generation int64, expiration time.Duration) error
+	// ListGeneration returns the invalidation generation of every list, to be
+	// read before loading a list and passed to SetArticles.
+	ListGeneration(ctx context.Context) (int64, error)
+	// DeleteArticle drops the cached article and advances its generation.
 	DeleteArticle(ctx context.Context, id uint) error
+	// InvalidateArticleLists drops every cached list and query result. Any
+	// article mutation calls it, since a create or an edit can change which
+	// lists an article belongs to, not just the lists already containing it.
+	InvalidateArticleLists(ctx context.Context) error
 }
@@ -11,6 +11,8 @@ type ArticleRepository interface {
 	Create(ctx context.Context, article *domain.Article) error
 	GetByID(ctx context.Context, id int64) (*domain.Article, error)
 	GetAll(ctx context.Context) ([]*domain.Article, error)
+	// List 按 ID 顺序跳过 offset 篇，返回至多 limit 篇文章
+	List(ctx context.Context, limit, offset int) ([]*domain.Article, error)
 	Update(ctx context.Context, article *domain.Article) error
 	Delete(ctx context.Context, id int64) error
 }
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrCacheUnavailable 表示缓存后端暂时不可用（例如熔断器打开），调用方应当按预期的
+// 降级处理，无需逐次记录错误；状态变化由后端自行记录。
+var ErrCacheUnavailable = errors.New("cache temporarily unavailable")
+
+// CacheLocker 提供跨实例的短时互斥锁，让缓存重建时只有一个实例回源数据库。
+type CacheLocker interface {
+	// TryLock 尝试获取 key 上的锁，锁在 ttl 后自动失效。acquired 为 false 表示锁
+	// 被其他持有者占用；成功时返回的 unlock 只会释放本次获取的锁。
+	TryLock(ctx context.Context, key string, ttl time.Duration) (unlock func(context.Context) error, acquired bool, err error)
+}
@@ -1,9 +1,17 @@
 package repository
 
-import "github.com/FormalYou/clean-architecture-blog/domain"
+import (
+	"context"
+
+	"github.com/FormalYou/clean-architecture-blog/domain"
+)
 
 // CommentRepository defines the interface for comment persistence.
 type CommentRepository interface {
-	FindByArticleID(articleID uint) ([]*domain.Comment, error)
-	Save(comment *domain.Comment) error
+	FindByArticleID(ctx context.Context, articleID uint) ([]*domain.Comment, error)
+	// FindByArticleIDs returns the comments of several articles in one query,
+	// ordered by article and then by ID. A positive limit keeps only the
+	// first limit comments of each article.
+	FindByArticleIDs(ctx context.Context, articleIDs []int64, limit int) ([]*domain.Comment, error)
+	Save(ctx context.Context, comment *domain.Comment) error
 }
@@ -4,3 +4,6 @@ import "errors"
 
 // ErrNotFound is a common error for when a record is not found in the repository.
 var ErrNotFound = errors.New("not found")
+
+// ErrTimeout is returned when a repository operation exceeds its deadline.
+var ErrTimeout = errors.New("query timeout")
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrIdempotencyClaimLost is returned by Complete when the key is no longer
+// held by the request's claim: its lock expired and a retry claimed the key.
+var ErrIdempotencyClaimLost = errors.New("idempotency key is no longer held by this request")
+
+// IdempotentResponse 是首次请求的响应，重试时原样返回。
+type IdempotentResponse struct {
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type"`
+	Body        []byte `json:"body"`
+}
+
+// IdempotencyRecord 记录一个幂等键对应的请求。Fingerprint 标识请求内容，
+// Response 为 nil 表示首次请求仍在处理中，此时 Claim 标识占用 key 的请求。
+type IdempotencyRecord struct {
+	Fingerprint string              `json:"fingerprint"`
+	Response    *IdempotentResponse `json:"response,omitempty"`
+	Claim       string              `json:"claim,omitempty"`
+}
+
+// IdempotencyStore 保存幂等键与首次请求的响应，供重试时重放。
+type IdempotencyStore interface {
+	// Begin 为 fingerprint 所描述的请求占用 key，占用在 lockTTL 后自动失效。
+	// 占用成功时返回非空的 claim，否则返回 key 已有的记录（不含 Claim）。
+	Begin(ctx context.Context, key, fingerprint string, lockTTL time.Duration) (existing *IdempotencyRecord, claim string, err error)
+	// Complete 保存 key 的响应，保留 ttl。key 已不由 claim 占用（占用过期后被重试的
+	// 请求占用或已完成）时不做修改并返回 ErrIdempotencyClaimLost。
+	Complete(ctx context.Context, key, claim, fingerprint string, resp IdempotentResponse, ttl time.Duration) error
+	// Release 放弃 claim 对 key 的占用，之后可以用同一个 key 重试；key 已不由
+	// claim 占用时不做任何事。
+	Release(ctx context.Context, key, claim string) error
+}
@@ -15,6 +15,7 @@ import (
 	time "time"
 
 	domain "github.com/FormalYou/clean-architecture-blog/domain"
+	repository "github.com/FormalYou/clean-architecture-blog/internal/application/repository"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -42,6 +43,21 @@ func (m *MockArticleCacheRepository) EXPECT() *MockArticleCacheRepositoryMockRec
 	return m.recorder
 }
 
+// ArticleGeneration mocks base method.
+func (m *MockArticleCacheRepository) ArticleGeneration(ctx context.Context, id uint) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ArticleGeneration", ctx, id)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ArticleGeneration indicates an expected call of ArticleGeneration.
+func (mr *MockArticleCacheRepositoryMockRecorder) ArticleGeneration(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArticleGeneration", reflect.TypeOf((*MockArticleCacheRepository)(nil).ArticleGeneration), ctx, id)
+}
+
 // DeleteArticle mocks base method.
 func (m *MockArticleCacheRepository) DeleteArticle(ctx context.Context, id uint) error {
 	m.ctrl.T.Helper()
@@ -57,10 +73,10 @@ func (mr *MockArticleCacheRepositoryMockRecorder) DeleteArticle(ctx, id any) *go
 }
 
 // GetArticle mocks base method.
-func (m *MockArticleCacheRepository) GetArticle(ctx context.Context, id uint) (*domain.Article, error) {
+func (m *MockArticleCacheRepository) GetArticle(ctx context.Context, id uint) (*repository.ArticleCacheEntry, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetArticle", ctx, id)
-	ret0, _ := ret[0].(*domain.Article)
+	ret0, _ := ret[0].(*repository.ArticleCacheEntry)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -86,30 +102,59 @@ func (mr *MockArticleCacheRepositoryMockRecorder) GetArticles(ctx, key any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetArticles", reflect.TypeOf((*MockArticleCacheRepository)(nil).GetArticles), ctx, key)
 }
 
+// InvalidateArticleLists mocks base method.
+func (m *MockArticleCacheRepository) InvalidateArticleLists(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InvalidateArticleLists", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InvalidateArticleLists indicates an expected call of InvalidateArticleLists.
+func (mr *MockArticleCacheRepositoryMockRecorder) InvalidateArticleLists(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateArticleLists", reflect.TypeOf((*MockArticleCacheRepository)(nil).InvalidateArticleLists), ctx)
+}
+
+// ListGeneration mocks base method.
+func (m *MockArticleCacheRepository) ListGeneration(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListGeneration", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListGeneration indicates an expected call of ListGeneration.
+func (mr *MockArticleCacheRepositoryMockRecorder) ListGeneration(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListGeneration", reflect.TypeOf((*MockArticleCacheRepository)(nil).ListGeneration), ctx)
+}
+
 // SetArticle mocks base method.
-func (m *MockArticleCacheRepository) SetArticle(ctx context.Context, article *domain.Article, expiration time.Duration) error {
+func (m *MockArticleCacheRepository) SetArticle(ctx context.Context, id uint, entry *repository.ArticleCacheEntry, expiration time.Duration) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetArticle", ctx, article, expiration)
+	ret := m.ctrl.Call(m, "SetArticle", ctx, id, entry, expiration)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SetArticle indicates an expected call of SetArticle.
-func (mr *MockArticleCacheRepositoryMockRecorder) SetArticle(ctx, article, expiration any) *gomock.Call {
+func (mr *MockArticleCacheRepositoryMockRecorder) SetArticle(ctx, id, entry, expiration any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetArticle", reflect.TypeOf((*MockArticleCacheRepository)(nil).SetArticle), ctx, article, expiration)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetArticle", reflect.TypeOf((*MockArticleCacheRepository)(nil).SetArticle), ctx, id, entry, expiration)
 }
 
 // SetArticles mocks base method.
-func (m *MockArticleCacheRepository) SetArticles(ctx context.Context, key string, articles []*domain.Article, expiration time.Duration) error {
+func (m *MockArticleCacheRepository) SetArticles(ctx context.Context, key string, articles []*domain.Article, generation int64, expiration time.Duration) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetArticles", ctx, key, articles, expiration)
+	ret := m.ctrl.Call(m, "SetArticles", ctx, key, articles, generation, expiration)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SetArticles indicates an expected call of SetArticles.
-func (mr *MockArticleCacheRepositoryMockRecorder) SetArticles(ctx, key, articles, expiration any) *gomock.Call {
+func (mr *MockArticleCacheRepositoryMockRecorder) SetArticles(ctx, key, articles, generation, expiration any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetArticles", reflect.TypeOf((*MockArticleCacheRepository)(nil).SetArticles), ctx, key, articles, expiration)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetArticles", reflect.TypeOf((*MockArticleCacheRepository)(nil).SetArticles), ctx, key, articles, generation, expiration)
 }
@@ -0,0 +1,58 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/application/repository/cache_locker.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/application/repository/cache_locker.go -destination=internal/application/repository/mocks/mock_cache_locker.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCacheLocker is a mock of CacheLocker interface.
+type MockCacheLocker struct {
+	ctrl     *gomock.Controller
+	recorder *MockCacheLockerMockRecorder
+	isgomock struct{}
+}
+
+// MockCacheLockerMockRecorder is the mock recorder for MockCacheLocker.
+type MockCacheLockerMockRecorder struct {
+	mock *MockCacheLocker
+}
+
+// NewMockCacheLocker creates a new mock instance.
+func NewMockCacheLocker(ctrl *gomock.Controller) *MockCacheLocker {
+	mock := &MockCacheLocker{ctrl: ctrl}
+	mock.recorder = &MockCacheLockerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCacheLocker) EXPECT() *MockCacheLockerMockRecorder {
+	return m.recorder
+}
+
+// TryLock mocks base method.
+func (m *MockCacheLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (func(context.Context) error, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TryLock", ctx, key, ttl)
+	ret0, _ := ret[0].(func(context.Context) error)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// TryLock indicates an expected call of TryLock.
+func (mr *MockCacheLockerMockRecorder) TryLock(ctx, key, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TryLock", reflect.TypeOf((*MockCacheLocker)(nil).TryLock), ctx, key, ttl)
+}
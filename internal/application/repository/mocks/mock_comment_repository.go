@@ -0,0 +1,86 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/application/repository/comment_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/application/repository/comment_repository.go -destination=internal/application/repository/mocks/mock_comment_repository.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/FormalYou/clean-architecture-blog/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCommentRepository is a mock of CommentRepository interface.
+type MockCommentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCommentRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockCommentRepositoryMockRecorder is the mock recorder for MockCommentRepository.
+type MockCommentRepositoryMockRecorder struct {
+	mock *MockCommentRepository
+}
+
+// NewMockCommentRepository creates a new mock instance.
+func NewMockCommentRepository(ctrl *gomock.Controller) *MockCommentRepository {
+	mock := &MockCommentRepository{ctrl: ctrl}
+	mock.recorder = &MockCommentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCommentRepository) EXPECT() *MockCommentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindByArticleID mocks base method.
+func (m *MockCommentRepository) FindByArticleID(ctx context.Context, articleID uint) ([]*domain.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByArticleID", ctx, articleID)
+	ret0, _ := ret[0].([]*domain.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByArticleID indicates an expected call of FindByArticleID.
+func (mr *MockCommentRepositoryMockRecorder) FindByArticleID(ctx, articleID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByArticleID", reflect.TypeOf((*MockCommentRepository)(nil).FindByArticleID), ctx, articleID)
+}
+
+// FindByArticleIDs mocks base method.
+func (m *MockCommentRepository) FindByArticleIDs(ctx context.Context, articleIDs []int64, limit int) ([]*domain.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByArticleIDs", ctx, articleIDs, limit)
+	ret0, _ := ret[0].([]*domain.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByArticleIDs indicates an expected call of FindByArticleIDs.
+func (mr *MockCommentRepositoryMockRecorder) FindByArticleIDs(ctx, articleIDs, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByArticleIDs", reflect.TypeOf((*MockCommentRepository)(nil).FindByArticleIDs), ctx, articleIDs, limit)
+}
+
+// Save mocks base method.
+func (m *MockCommentRepository) Save(ctx context.Context, comment *domain.Comment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, comment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockCommentRepositoryMockRecorder) Save(ctx, comment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockCommentRepository)(nil).Save), ctx, comment)
+}
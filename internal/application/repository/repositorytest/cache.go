@@ -0,0 +1,174 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FormalYou/clean-architecture-blog/domain"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/repository"
+)
+
+// CacheFactory returns an empty cache and a function that moves the cache's
+// clock forward, used to check expiry without sleeping.
+type CacheFactory func(t *testing.T) (cache repository.ArticleCacheRepository, advance func(time.Duration))
+
+// RunArticleCache runs the ArticleCacheRepository conformance tests.
+func RunArticleCache(t *testing.T, newCache CacheFactory) {
+	ctx := context.Background()
+	article := &domain.Article{ID: 1, Title: "title", Content: "content", AuthorID: 2, Tags: []domain.Tag{{ID: 3, Name: "go"}}}
+	freshUntil := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	entry := &repository.ArticleCacheEntry{Article: article, FreshUntil: freshUntil}
+
+	t.Run("Article round trip and miss", func(t *testing.T) {
+		cache, _ := newCache(t)
+		got, err := cache.GetArticle(ctx, 1)
+		require.NoError(t, err)
+		assert.Nil(t, got, "a miss is (nil, nil)")
+
+		require.NoError(t, cache.SetArticle(ctx, 1, entry, time.Minute))
+		got, err = cache.GetArticle(ctx, 1)
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		assert.Equal(t, article, got.Article)
+		assert.NotSame(t, article, got.Article)
+		assert.True(t, freshUntil.Equal(got.FreshUntil))
+	})
+
+	t.Run("Entries loaded before a delete are refused", func(t *testing.T) {
+		cache, _ := newCache(t)
+		gen, err := cache.ArticleGeneration(ctx, 1)
+		require.NoError(t, err)
+		loaded := &repository.ArticleCacheEntry{Article: article, FreshUntil: freshUntil, Generation: gen}
+
+		// A write commits and invalidates while the load is in flight.
+		require.NoError(t, cache.DeleteArticle(ctx, 1))
+		assert.ErrorIs(t, cache.SetArticle(ctx, 1, loaded, time.Minute), repository.ErrStaleCacheEntry)
+		got, err := cache.GetArticle(ctx, 1)
+		require.NoError(t, err)
+		assert.Nil(t, got)
+
+		next, err := cache.ArticleGeneration(ctx, 1)
+		require.NoError(t, err)
+		assert.Greater(t, next, gen)
+		loaded.Generation = next
+		require.NoError(t, cache.SetArticle(ctx, 1, loaded, time.Minute))
+		got, err = cache.GetArticle(ctx, 1)
+		require.NoError(t, err)
+		assert.NotNil(t, got, "a load after the delete is stored")
+
+		other, err := cache.ArticleGeneration(ctx, 2)
+		require.NoError(t, err)
+		assert.Equal(t, gen, other, "generations are per article")
+	})
+
+	t.Run("Negative entry round trip", func(t *testing.T) {
+		cache, _ := newCache(t)
+		require.NoError(t, cache.SetArticle(ctx, 9, &repository.ArticleCacheEntry{FreshUntil: freshUntil}, time.Minute))
+		got, err := cache.GetArticle(ctx, 9)
+		require.NoError(t, err)
+		require.NotNil(t, got, "a negative entry is a hit")
+		assert.Nil(t, got.Article)
+	})
+
+	t.Run("Article list round trip", func(t *testing.T) {
+		cache, _ := newCache(t)
+		got, err := cache.GetArticles(ctx, "articles:all")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+
+		list := []*domain.Article{article, {ID: 2, Title: "second"}}
+		require.NoError(t, cache.SetArticles(ctx, "articles:all", list, 0, time.Minute))
+		got, err = cache.GetArticles(ctx, "articles:all")
+		require.NoError(t, err)
+		assert.Equal(t, list, got)
+	})
+
+	t.Run("Invalidate lists", func(t *testing.T) {
+		cache, _ := newCache(t)
+		require.NoError(t, cache.SetArticle(ctx, 1, entry, time.Minute))
+		require.NoError(t, cache.SetArticles(ctx, "articles:all", []*domain.Article{article}, 0, time.Minute))
+		require.NoError(t, cache.SetArticles(ctx, "articles:author:2", []*domain.Article{article}, 0, time.Minute))
+
+		require.NoError(t, cache.InvalidateArticleLists(ctx))
+		gen, err := cache.ListGeneration(ctx)
+		require.NoError(t, err)
+		for _, key := range []string{"articles:all", "articles:author:2"} {
+			got, err := cache.GetArticles(ctx, key)
+			require.NoError(t, err)
+			assert.Nil(t, got, key)
+		}
+		got, err := cache.GetArticle(ctx, 1)
+		require.NoError(t, err)
+		assert.NotNil(t, got, "single articles are untouched")
+
+		require.NoError(t, cache.SetArticles(ctx, "articles:all", []*domain.Article{article}, gen, time.Minute))
+		list, err := cache.GetArticles(ctx, "articles:all")
+		require.NoError(t, err)
+		assert.Len(t, list, 1, "lists can be cached again afterwards")
+	})
+
+	t.Run("Lists loaded before an invalidation are refused", func(t *testing.T) {
+		cache, _ := newCache(t)
+		gen, err := cache.ListGeneration(ctx)
+		require.NoError(t, err)
+
+		// A write commits and invalidates the lists while the reader is
+		// still loading the old list from the database.
+		require.NoError(t, cache.InvalidateArticleLists(ctx))
+		assert.ErrorIs(t, cache.SetArticles(ctx, "articles:all", []*domain.Article{article}, gen, time.Minute), repository.ErrStaleCacheEntry)
+		got, err := cache.GetArticles(ctx, "articles:all")
+		require.NoError(t, err)
+		assert.Nil(t, got, "the old list is not served")
+
+		next, err := cache.ListGeneration(ctx)
+		require.NoError(t, err)
+		assert.Greater(t, next, gen)
+		require.NoError(t, cache.SetArticles(ctx, "articles:all", []*domain.Article{article}, next, time.Minute))
+		got, err = cache.GetArticles(ctx, "articles:all")
+		require.NoError(t, err)
+		assert.Len(t, got, 1, "a list loaded after the invalidation is stored")
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		cache, _ := newCache(t)
+		require.NoError(t, cache.SetArticle(ctx, 1, entry, time.Minute))
+		require.NoError(t, cache.DeleteArticle(ctx, 1))
+		got, err := cache.GetArticle(ctx, 1)
+		require.NoError(t, err)
+		assert.Nil(t, got)
+
+		assert.NoError(t, cache.DeleteArticle(ctx, 42), "deleting a missing entry is not an error")
+	})
+
+	t.Run("Entries expire", func(t *testing.T) {
+		cache, advance := newCache(t)
+		require.NoError(t, cache.SetArticle(ctx, 1, entry, time.Minute))
+		require.NoError(t, cache.SetArticles(ctx, "articles:all", []*domain.Article{article}, 0, time.Minute))
+
+		advance(30 * time.Second)
+		got, err := cache.GetArticle(ctx, 1)
+		require.NoError(t, err)
+		assert.NotNil(t, got)
+
+		advance(31 * time.Second)
+		got, err = cache.GetArticle(ctx, 1)
+		require.NoError(t, err)
+		assert.Nil(t, got)
+		list, err := cache.GetArticles(ctx, "articles:all")
+		require.NoError(t, err)
+		assert.Nil(t, list)
+	})
+
+	t.Run("Zero expiration never expires", func(t *testing.T) {
+		cache, advance := newCache(t)
+		require.NoError(t, cache.SetArticle(ctx, 1, entry, 0))
+		advance(24 * time.Hour)
+		got, err := cache.GetArticle(ctx, 1)
+		require.NoError(t, err)
+		assert.NotNil(t, got)
+	})
+}
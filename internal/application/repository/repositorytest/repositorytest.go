@@ -0,0 +1,416 @@
+// Package repositorytest is a conformance suite for the repository
+// contracts. Every implementation runs the same tests, so the in-memory and
+// database-backed repositories behave identically from a usecase's point of
+// view.
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FormalYou/clean-architecture-blog/domain"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/repository"
+)
+
+// Repositories is one set of implementations sharing the same storage.
+type Repositories struct {
+	Articles repository.ArticleRepository
+	Users    repository.UserRepository
+	Tags     repository.TagRepository
+	Comments repository.CommentRepository
+}
+
+// Factory returns repositories backed by fresh, empty storage.
+type Factory func(t *testing.T) Repositories
+
+// Run runs every repository conformance test.
+func Run(t *testing.T, newRepos Factory) {
+	t.Run("Article", func(t *testing.T) { testArticleRepository(t, newRepos) })
+	t.Run("User", func(t *testing.T) { testUserRepository(t, newRepos) })
+	t.Run("Tag", func(t *testing.T) { testTagRepository(t, newRepos) })
+	t.Run("Comment", func(t *testing.T) { testCommentRepository(t, newRepos) })
+}
+
+func testArticleRepository(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+
+	t.Run("Create assigns IDs and reuses tags by name", func(t *testing.T) {
+		repos := newRepos(t)
+		first := &domain.Article{Title: "first", Content: "c", AuthorID: 1, Tags: []domain.Tag{{Name: "go"}, {Name: "sql"}}}
+		require.NoError(t, repos.Articles.Create(ctx, first))
+		assert.NotZero(t, first.ID)
+		assert.NotZero(t, first.Tags[0].ID)
+
+		second := &domain.Article{Title: "second", Content: "c", AuthorID: 1, Tags: []domain.Tag{{Name: "go"}}}
+		require.NoError(t, repos.Articles.Create(ctx, second))
+		assert.NotEqual(t, first.ID, second.ID)
+		assert.Equal(t, first.Tags[0].ID, second.Tags[0].ID)
+
+		tag, err := repos.Tags.FindByName(ctx, "sql")
+		require.NoError(t, err)
+		assert.Equal(t, first.Tags[1].ID, tag.ID)
+	})
+
+	t.Run("GetByID", func(t *testing.T) {
+		repos := newRepos(t)
+		article := &domain.Article{Title: "title", Content: "content", AuthorID: 7, Tags: []domain.Tag{{Name: "go"}}}
+		require.NoError(t, repos.Articles.Create(ctx, article))
+
+		got, err := repos.Articles.GetByID(ctx, article.ID)
+		require.NoError(t, err)
+		assert.Equal(t, article.ID, got.ID)
+		assert.Equal(t, "title", got.Title)
+		assert.Equal(t, "content", got.Content)
+		assert.Equal(t, int64(7), got.AuthorID)
+		assert.Equal(t, int64(1), article.Version)
+		assert.Equal(t, int64(1), got.Version)
+		assert.False(t, got.CreatedAt.IsZero(), "created_at is set")
+		assert.WithinDuration(t, article.CreatedAt, got.CreatedAt, time.Second)
+		require.Len(t, got.Tags, 1)
+		assert.Equal(t, "go", got.Tags[0].Name)
+
+		_, err = repos.Articles.GetByID(ctx, article.ID+1000)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+	})
+
+	t.Run("GetAll returns articles in ID order", func(t *testing.T) {
+		repos := newRepos(t)
+		all, err := repos.Articles.GetAll(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, all)
+
+		for _, title := range []string{"a", "b", "c"} {
+			require.NoError(t, repos.Articles.Create(ctx, &domain.Article{Title: title, Content: "c", AuthorID: 1}))
+		}
+		all, err = repos.Articles.GetAll(ctx)
+		require.NoError(t, err)
+		require.Len(t, all, 3)
+		for i, title := range []string{"a", "b", "c"} {
+			assert.Equal(t, title, all[i].Title)
+		}
+	})
+
+	t.Run("List pages through articles in ID order", func(t *testing.T) {
+		repos := newRepos(t)
+		for _, title := range []string{"a", "b", "c"} {
+			require.NoError(t, repos.Articles.Create(ctx, &domain.Article{Title: title, Content: "c", AuthorID: 1, Tags: []domain.Tag{{Name: "go"}}}))
+		}
+		for _, tc := range []struct {
+			limit, offset int
+			titles        []string
+		}{
+			{2, 0, []string{"a", "b"}},
+			{2, 2, []string{"c"}},
+			{2, 3, nil},
+			{0, 0, nil},
+		} {
+			page, err := repos.Articles.List(ctx, tc.limit, tc.offset)
+			require.NoError(t, err)
+			var titles []string
+			for _, a := range page {
+				titles = append(titles, a.Title)
+				assert.Len(t, a.Tags, 1, "tags are loaded")
+			}
+			assert.Equal(t, tc.titles, titles, "limit %d offset %d", tc.limit, tc.offset)
+		}
+	})
+
+	t.Run("Update changes non-zero fields and keeps tags", func(t *testing.T) {
+		repos := newRepos(t)
+		article := &domain.Article{Title: "old", Content: "old content", AuthorID: 1, Tags: []domain.Tag{{Name: "go"}}}
+		require.NoError(t, repos.Articles.Create(ctx, article))
+
+		require.NoError(t, repos.Articles.Update(ctx, &domain.Article{ID: article.ID, Title: "new"}))
+		got, err := repos.Articles.GetByID(ctx, article.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "new", got.Title)
+		assert.Equal(t, "old content", got.Content)
+		assert.Equal(t, int64(1), got.AuthorID)
+		assert.Len(t, got.Tags, 1)
+	})
+
+	t.Run("Update bumps the version and update time", func(t *testing.T) {
+		repos := newRepos(t)
+		article := &domain.Article{Title: "old", Content: "c", AuthorID: 1}
+		require.NoError(t, repos.Articles.Create(ctx, article))
+		created, err := repos.Articles.GetByID(ctx, article.ID)
+		require.NoError(t, err)
+
+		for i := 0; i < 2; i++ {
+			require.NoError(t, repos.Articles.Update(ctx, &domain.Article{ID: article.ID, Content: "edited"}))
+		}
+		got, err := repos.Articles.GetByID(ctx, article.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), got.Version)
+		assert.False(t, got.UpdatedAt.Before(created.UpdatedAt), "updated_at does not move backwards")
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		repos := newRepos(t)
+		article := &domain.Article{Title: "doomed", Content: "c", AuthorID: 1, Tags: []domain.Tag{{Name: "go"}}}
+		require.NoError(t, repos.Articles.Create(ctx, article))
+
+		require.NoError(t, repos.Articles.Delete(ctx, article.ID))
+		_, err := repos.Articles.GetByID(ctx, article.ID)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+
+		// Tags outlive the articles that used them.
+		_, err = repos.Tags.FindByName(ctx, "go")
+		assert.NoError(t, err)
+	})
+
+	t.Run("Returned values are copies", func(t *testing.T) {
+		repos := newRepos(t)
+		article := &domain.Article{Title: "title", Content: "c", AuthorID: 1, Tags: []domain.Tag{{Name: "go"}}}
+		require.NoError(t, repos.Articles.Create(ctx, article))
+		article.Title = "changed after create"
+		article.Tags[0].Name = "changed"
+
+		got, err := repos.Articles.GetByID(ctx, article.ID)
+		require.NoError(t, err)
+		got.Title = "changed after get"
+
+		again, err := repos.Articles.GetByID(ctx, article.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "title", again.Title)
+		assert.Equal(t, "go", again.Tags[0].Name)
+	})
+}
+
+func testUserRepository(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+
+	newUser := func(name string) *domain.User {
+		return &domain.User{
+			Username:     name,
+			Email:        name + "@example.com",
+			PasswordHash: "hash",
+			Profile:      domain.UserProfile{Nickname: "nick " + name},
+		}
+	}
+
+	t.Run("Create and find", func(t *testing.T) {
+		repos := newRepos(t)
+		user := newUser("alice")
+		require.NoError(t, repos.Users.Create(ctx, user))
+		assert.NotZero(t, user.ID)
+
+		byID, err := repos.Users.FindByID(ctx, uint(user.ID))
+		require.NoError(t, err)
+		byEmail, err := repos.Users.FindByEmail(ctx, "alice@example.com")
+		require.NoError(t, err)
+		byName, err := repos.Users.GetByUsername(ctx, "alice")
+		require.NoError(t, err)
+		for _, got := range []*domain.User{byID, byEmail, byName} {
+			assert.Equal(t, user.ID, got.ID)
+			assert.Equal(t, "alice", got.Username)
+			assert.Equal(t, "hash", got.PasswordHash)
+			assert.Equal(t, "nick alice", got.Profile.Nickname)
+		}
+	})
+
+	t.Run("FindByIDs skips missing users", func(t *testing.T) {
+		repos := newRepos(t)
+		alice, bob := newUser("alice"), newUser("bob")
+		require.NoError(t, repos.Users.Create(ctx, alice))
+		require.NoError(t, repos.Users.Create(ctx, bob))
+
+		users, err := repos.Users.FindByIDs(ctx, []int64{bob.ID, alice.ID + bob.ID + 1000, alice.ID})
+		require.NoError(t, err)
+		byID := map[int64]string{}
+		for _, u := range users {
+			byID[u.ID] = u.Profile.Nickname
+		}
+		assert.Equal(t, map[int64]string{alice.ID: "nick alice", bob.ID: "nick bob"}, byID)
+
+		users, err = repos.Users.FindByIDs(ctx, nil)
+		require.NoError(t, err)
+		assert.Empty(t, users)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		repos := newRepos(t)
+		_, err := repos.Users.FindByID(ctx, 99)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+		_, err = repos.Users.FindByEmail(ctx, "nobody@example.com")
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+		_, err = repos.Users.GetByUsername(ctx, "nobody")
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+	})
+
+	t.Run("Create rejects duplicate username and email", func(t *testing.T) {
+		repos := newRepos(t)
+		require.NoError(t, repos.Users.Create(ctx, newUser("bob")))
+
+		sameName := newUser("bob")
+		sameName.Email = "other@example.com"
+		assert.Error(t, repos.Users.Create(ctx, sameName))
+
+		sameEmail := newUser("carol")
+		sameEmail.Email = "bob@example.com"
+		assert.Error(t, repos.Users.Create(ctx, sameEmail))
+	})
+
+	t.Run("Save updates an existing user", func(t *testing.T) {
+		repos := newRepos(t)
+		user := newUser("dave")
+		require.NoError(t, repos.Users.Create(ctx, user))
+
+		user.Profile.Nickname = "David"
+		require.NoError(t, repos.Users.Save(ctx, user))
+		got, err := repos.Users.FindByID(ctx, uint(user.ID))
+		require.NoError(t, err)
+		assert.Equal(t, "David", got.Profile.Nickname)
+	})
+}
+
+func testTagRepository(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+
+	t.Run("Save and find", func(t *testing.T) {
+		repos := newRepos(t)
+		tags, err := repos.Tags.FindAll(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, tags)
+
+		for _, name := range []string{"go", "sql"} {
+			tag := &domain.Tag{Name: name}
+			require.NoError(t, repos.Tags.Save(ctx, tag))
+			assert.NotZero(t, tag.ID)
+		}
+
+		tags, err = repos.Tags.FindAll(ctx)
+		require.NoError(t, err)
+		require.Len(t, tags, 2)
+		assert.Equal(t, "go", tags[0].Name)
+		assert.Equal(t, "sql", tags[1].Name)
+
+		tag, err := repos.Tags.FindByName(ctx, "sql")
+		require.NoError(t, err)
+		assert.Equal(t, tags[1].ID, tag.ID)
+
+		_, err = repos.Tags.FindByName(ctx, "missing")
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+	})
+
+	t.Run("Save rejects a duplicate name", func(t *testing.T) {
+		repos := newRepos(t)
+		require.NoError(t, repos.Tags.Save(ctx, &domain.Tag{Name: "go"}))
+		assert.Error(t, repos.Tags.Save(ctx, &domain.Tag{Name: "go"}))
+	})
+
+	t.Run("Save renames an existing tag", func(t *testing.T) {
+		repos := newRepos(t)
+		tag := &domain.Tag{Name: "golang"}
+		require.NoError(t, repos.Tags.Save(ctx, tag))
+		tag.Name = "go"
+		require.NoError(t, repos.Tags.Save(ctx, tag))
+
+		got, err := repos.Tags.FindByName(ctx, "go")
+		require.NoError(t, err)
+		assert.Equal(t, tag.ID, got.ID)
+		_, err = repos.Tags.FindByName(ctx, "golang")
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+	})
+}
+
+func testCommentRepository(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+
+	t.Run("Save and find by article", func(t *testing.T) {
+		repos := newRepos(t)
+		before := time.Now().Add(-time.Second)
+		for _, c := range []*domain.Comment{
+			{ArticleID: 1, UserID: 1, Content: "first"},
+			{ArticleID: 2, UserID: 1, Content: "other article"},
+			{ArticleID: 1, UserID: 2, Content: "second"},
+		} {
+			require.NoError(t, repos.Comments.Save(ctx, c))
+			assert.NotZero(t, c.ID)
+		}
+
+		comments, err := repos.Comments.FindByArticleID(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, comments, 2)
+		assert.Equal(t, "first", comments[0].Content)
+		assert.Equal(t, "second", comments[1].Content)
+		assert.Equal(t, int64(2), comments[1].UserID)
+		assert.True(t, comments[0].CreatedAt.After(before), "CreatedAt is set on save")
+
+		comments, err = repos.Comments.FindByArticleID(ctx, 3)
+		require.NoError(t, err)
+		assert.Empty(t, comments)
+	})
+
+	t.Run("FindByArticleIDs orders by article and ID", func(t *testing.T) {
+		repos := newRepos(t)
+		for _, c := range []*domain.Comment{
+			{ArticleID: 2, UserID: 1, Content: "2a"},
+			{ArticleID: 1, UserID: 1, Content: "1a"},
+			{ArticleID: 3, UserID: 1, Content: "3a"},
+			{ArticleID: 2, UserID: 2, Content: "2b"},
+		} {
+			require.NoError(t, repos.Comments.Save(ctx, c))
+		}
+
+		contents := func(comments []*domain.Comment) []string {
+			var contents []string
+			for _, c := range comments {
+				contents = append(contents, c.Content)
+			}
+			return contents
+		}
+		comments, err := repos.Comments.FindByArticleIDs(ctx, []int64{2, 1, 4}, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1a", "2a", "2b"}, contents(comments))
+
+		comments, err = repos.Comments.FindByArticleIDs(ctx, nil, 0)
+		require.NoError(t, err)
+		assert.Empty(t, comments)
+	})
+
+	t.Run("FindByArticleIDs limits each article", func(t *testing.T) {
+		repos := newRepos(t)
+		for _, c := range []*domain.Comment{
+			{ArticleID: 1, UserID: 1, Content: "1a"},
+			{ArticleID: 2, UserID: 1, Content: "2a"},
+			{ArticleID: 1, UserID: 1, Content: "1b"},
+			{ArticleID: 2, UserID: 1, Content: "2b"},
+			{ArticleID: 1, UserID: 1, Content: "1c"},
+			{ArticleID: 3, UserID: 1, Content: "3a"},
+		} {
+			require.NoError(t, repos.Comments.Save(ctx, c))
+		}
+
+		comments, err := repos.Comments.FindByArticleIDs(ctx, []int64{1, 2, 3}, 2)
+		require.NoError(t, err)
+		var got []string
+		for _, c := range comments {
+			got = append(got, c.Content)
+			assert.NotZero(t, c.ID)
+		}
+		assert.Equal(t, []string{"1a", "1b", "2a", "2b", "3a"}, got)
+
+		comments, err = repos.Comments.FindByArticleIDs(ctx, []int64{1}, 1)
+		require.NoError(t, err)
+		require.Len(t, comments, 1)
+		assert.Equal(t, "1a", comments[0].Content)
+	})
+
+	t.Run("Save updates an existing comment", func(t *testing.T) {
+		repos := newRepos(t)
+		comment := &domain.Comment{ArticleID: 1, UserID: 1, Content: "draft"}
+		require.NoError(t, repos.Comments.Save(ctx, comment))
+		comment.Content = "final"
+		require.NoError(t, repos.Comments.Save(ctx, comment))
+
+		comments, err := repos.Comments.FindByArticleID(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, comments, 1)
+		assert.Equal(t, "final", comments[0].Content)
+	})
+}
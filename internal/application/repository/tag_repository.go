@@ -1,10 +1,14 @@
 package repository
 
-import "github.com/FormalYou/clean-architecture-blog/domain"
+import (
+	"context"
+
+	"github.com/FormalYou/clean-architecture-blog/domain"
+)
 
 // TagRepository defines the interface for tag persistence.
 type TagRepository interface {
-	FindAll() ([]*domain.Tag, error)
-	FindByName(name string) (*domain.Tag, error)
-	Save(tag *domain.Tag) error
+	FindAll(ctx context.Context) ([]*domain.Tag, error)
+	FindByName(ctx context.Context, name string) (*domain.Tag, error)
+	Save(ctx context.Context, tag *domain.Tag) error
 }
@@ -0,0 +1,14 @@
+package repository
+
+import "context"
+
+// TxManager 定义了跨多个仓库的事务边界（unit of work）。
+type TxManager interface {
+	// WithinTx runs fn inside a transaction that is committed when fn returns
+	// nil and rolled back otherwise. Repositories called with the context
+	// passed to fn take part in the transaction. Calls nested inside fn join
+	// the outer transaction. fn may be run again when the transaction hits a
+	// deadlock or serialization failure, so it must not have side effects
+	// outside the database.
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -1,12 +1,19 @@
 package repository
 
-import "github.com/FormalYou/clean-architecture-blog/domain"
+import (
+	"context"
+
+	"github.com/FormalYou/clean-architecture-blog/domain"
+)
 
 // UserRepository defines the interface for user persistence.
 type UserRepository interface {
-	FindByID(id uint) (*domain.User, error)
-	FindByEmail(email string) (*domain.User, error)
-	Save(user *domain.User) error
-	Create(user *domain.User) error
-	GetByUsername(username string) (*domain.User, error)
+	FindByID(ctx context.Context, id uint) (*domain.User, error)
+	// FindByIDs returns the users that exist among ids, in no particular
+	// order; missing IDs are skipped rather than reported as ErrNotFound.
+	FindByIDs(ctx context.Context, ids []int64) ([]*domain.User, error)
+	FindByEmail(ctx context.Context, email string) (*domain.User, error)
+	Save(ctx context.Context, user *domain.User) error
+	Create(ctx context.Context, user *domain.User) error
+	GetByUsername(ctx context.Context, username string) (*domain.User, error)
 }
@@ -0,0 +1,190 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/FormalYou/clean-architecture-blog/internal/application/repository"
+)
+
+// ArticleCachePolicy 控制单篇文章缓存的过期与回源行为。
+type ArticleCachePolicy struct {
+	// FreshTTL 是条目被视为新鲜的时长。
+	FreshTTL time.Duration
+	// StaleTTL 是新鲜期过后仍可返回陈旧数据的时长，期间由一个 worker 在后台刷新。
+	StaleTTL time.Duration
+	// NotFoundTTL 是不存在的 ID 的负缓存时长，0 表示不缓存。
+	NotFoundTTL time.Duration
+	// ListTTL 是文章列表缓存的时长；任何文章写操作都会提前使列表失效。
+	ListTTL time.Duration
+	// LockTTL 是跨实例回源锁的最长持有时间。
+	LockTTL time.Duration
+	// LockWait 是未抢到锁时等待其他实例填充缓存的时长，超时后自行回源。
+	LockWait time.Duration
+}
+
+// DefaultArticleCachePolicy 是未配置时使用的缓存策略
+var DefaultArticleCachePolicy = ArticleCachePolicy{
+	FreshTTL:    5 * time.Minute,
+	StaleTTL:    time.Minute,
+	NotFoundTTL: 30 * time.Second,
+	ListTTL:     5 * time.Minute,
+	LockTTL:     5 * time.Second,
+	LockWait:    200 * time.Millisecond,
+}
+
+// ArticleUsecaseOption 配置 ArticleUsecase 的可选依赖
+type ArticleUsecaseOption func(*ArticleUsecase)
+
+// WithArticleCachePolicy 替换默认的缓存策略
+func WithArticleCachePolicy(policy ArticleCachePolicy) ArticleUsecaseOption {
+	return func(uc *ArticleUsecase) { uc.policy = policy }
+}
+
+// WithCacheLocker 让多个实例在重建同一缓存条目时只有一个回源数据库。
+// 未设置时只在进程内合并请求。
+func WithCacheLocker(locker repository.CacheLocker) ArticleUsecaseOption {
+	return func(uc *ArticleUsecase) { uc.locker = locker }
+}
+
+func articleCacheKey(id int64) string {
+	return fmt.Sprintf("article:%d", id)
+}
+
+// invalidateArticle 在文章写入后删除该文章的缓存及所有列表缓存。
+func (uc *ArticleUsecase) invalidateArticle(ctx context.Context, id int64) error {
+	return errors.Join(
+		uc.cache.DeleteArticle(ctx, uint(id)),
+		uc.cache.InvalidateArticleLists(ctx),
+	)
+}
+
+// loadArticle 从数据库读取文章并写入缓存，不存在的 ID 写入负缓存。
+// 其他实例持有回源锁时，waitForPeer 为 true 则等待对方填充缓存后再决定是否回源，
+// 否则放弃并返回 (nil, nil)。
+func (uc *ArticleUsecase) loadArticle(ctx context.Context, id int64, waitForPeer bool) (*repository.ArticleCacheEntry, error) {
+	if uc.locker != nil {
+		unlock, acquired, err := uc.locker.TryLock(ctx, articleCacheKey(id), uc.policy.LockTTL)
+		switch {
+		case errors.Is(err, repository.ErrCacheUnavailable):
+			// 熔断期间每次未命中都会走到这里，属预期的降级，熔断器状态变化时已记录
+		case err != nil:
+			// 锁不可用时退化为仅进程内合并
+			uc.logger.Error("failed to acquire article cache lock", "error", err)
+		case acquired:
+			defer func() {
+				if err := unlock(ctx); err != nil {
+					uc.logger.Error("failed to release article cache lock", "error", err)
+				}
+			}()
+		case !waitForPeer:
+			return nil, nil
+		default:
+			if entry := uc.waitForArticle(ctx, id); entry != nil {
+				return entry, nil
+			}
+		}
+	}
+
+	// The generation is read before the database, so that a write committing
+	// after the read advances it and SetArticle refuses the older article.
+	gen, err := uc.cache.ArticleGeneration(ctx, uint(id))
+	cacheable := err == nil
+	if err != nil {
+		uc.logger.Error("failed to get article cache generation", "error", err)
+	}
+
+	entry := &repository.ArticleCacheEntry{Generation: gen}
+	expiration := uc.policy.NotFoundTTL
+	article, err := uc.repo.GetByID(ctx, id)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		entry.FreshUntil = uc.now().Add(uc.policy.NotFoundTTL)
+	case err != nil:
+		return nil, err
+	default:
+		entry.Article = article
+		entry.FreshUntil = uc.now().Add(uc.policy.FreshTTL)
+		expiration = uc.policy.FreshTTL + uc.policy.StaleTTL
+	}
+
+	if cacheable && expiration > 0 {
+		err := uc.cache.SetArticle(ctx, uint(id), entry, expiration)
+		switch {
+		case errors.Is(err, repository.ErrStaleCacheEntry):
+			uc.logger.Info("article changed while loading, not cached", "article_id", id)
+		case err != nil:
+			uc.logger.Error("failed to set article to cache", "error", err)
+			// 即使缓存设置失败，我们仍然返回从数据库中获取到的数据
+		}
+	}
+	return entry, nil
+}
+
+// waitForArticle 在其他实例重建条目期间轮询缓存，超过 LockWait 后放弃。
+func (uc *ArticleUsecase) waitForArticle(ctx context.Context, id int64) *repository.ArticleCacheEntry {
+	interval := max(uc.policy.LockWait/8, 5*time.Millisecond)
+	deadline := time.NewTimer(uc.policy.LockWait)
+	defer deadline.Stop()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-deadline.C:
+			return nil
+		case <-ticker.C:
+			entry, err := uc.cache.GetArticle(ctx, uint(id))
+			if err == nil && entry != nil {
+				return entry
+			}
+		}
+	}
+}
+
+// refreshArticle 在后台重新加载陈旧条目。同一文章在本进程内只有一个刷新，
+// 配置了 locker 时在所有实例间也只有一个。Close 之后不再刷新，陈旧条目照常返回。
+func (uc *ArticleUsecase) refreshArticle(ctx context.Context, id int64) {
+	uc.refreshMu.Lock()
+	defer uc.refreshMu.Unlock()
+	if uc.closed {
+		return
+	}
+	if _, running := uc.refreshing.LoadOrStore(id, struct{}{}); running {
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+
+	uc.refreshes.Add(1)
+	go func() {
+		defer uc.refreshes.Done()
+		defer uc.refreshing.Delete(id)
+		if _, err := uc.loadArticle(ctx, id, false); err != nil {
+			uc.logger.Error("failed to refresh article cache", "article_id", id, "error", err)
+		}
+	}()
+}
+
+// Close 停止启动新的后台刷新并等待进行中的刷新完成，应在关闭缓存与数据库之前调用。
+// ctx 结束时不再等待并返回 ctx.Err()。
+func (uc *ArticleUsecase) Close(ctx context.Context) error {
+	uc.refreshMu.Lock()
+	uc.closed = true
+	uc.refreshMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		uc.refreshes.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
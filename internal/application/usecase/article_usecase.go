@@ -3,11 +3,15 @@ package usecase
 import (
 	"context"
 	"errors"
+	"sync"
 	"time"
 
+	"golang.org/x/sync/singleflight"
+
 	"github.com/FormalYou/clean-architecture-blog/domain"
 	"github.com/FormalYou/clean-architecture-blog/internal/application/contracts"
 	"github.com/FormalYou/clean-architecture-blog/internal/application/repository"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/usecase/articleerr"
 	"github.com/FormalYou/clean-architecture-blog/internal/errorx"
 )
 
@@ -16,6 +20,7 @@ type ArticleUsecaseInterface interface {
 	CreateArticle(ctx context.Context, article *domain.Article) error
 	GetArticleByID(ctx context.Context, id int64) (*domain.Article, error)
 	GetAllArticles(ctx context.Context) ([]*domain.Article, error)
+	ListArticles(ctx context.Context, limit, offset int) ([]*domain.Article, error)
 	UpdateArticle(ctx context.Context, article *domain.Article) error
 	DeleteArticle(ctx context.Context, id int64) error
 }
@@ -24,18 +29,37 @@ type ArticleUsecaseInterface interface {
 type ArticleUsecase struct {
 	repo        repository.ArticleRepository
 	cache       repository.ArticleCacheRepository
+	txManager   repository.TxManager
 	authService contracts.AuthService
 	logger      contracts.Logger
+	locker      repository.CacheLocker
+	policy      ArticleCachePolicy
+	now         func() time.Time
+
+	// loads 合并同一文章的并发缓存未命中；refreshing 记录正在后台刷新的文章；
+	// refreshes 跟踪后台刷新，closed 置位后（受 refreshMu 保护）不再启动新的刷新
+	loads      singleflight.Group
+	refreshing sync.Map
+	refreshes  sync.WaitGroup
+	refreshMu  sync.Mutex
+	closed     bool
 }
 
 // NewArticleUsecase 创建一个新的 ArticleUsecase
-func NewArticleUsecase(repo repository.ArticleRepository, cache repository.ArticleCacheRepository, authService contracts.AuthService, logger contracts.Logger) ArticleUsecaseInterface {
-	return &ArticleUsecase{
+func NewArticleUsecase(repo repository.ArticleRepository, cache repository.ArticleCacheRepository, txManager repository.TxManager, authService contracts.AuthService, logger contracts.Logger, opts ...ArticleUsecaseOption) ArticleUsecaseInterface {
+	uc := &ArticleUsecase{
 		repo:        repo,
 		cache:       cache,
+		txManager:   txManager,
 		authService: authService,
 		logger:      logger,
+		policy:      DefaultArticleCachePolicy,
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(uc)
 	}
+	return uc
 }
 
 // CreateArticle 创建一篇新文章
@@ -54,11 +78,18 @@ func (uc *ArticleUsecase) CreateArticle(ctx context.Context, article *domain.Art
 		return errorx.New(errorx.CodeInvalidParams, err)
 	}
 
-	// 2. 调用仓库进行持久化
-	err = uc.repo.Create(ctx, article)
+	// 2. 在事务中持久化文章及其标签
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		return uc.repo.Create(ctx, article)
+	})
 	if err != nil {
 		uc.logger.Error("failed to create article", "error", err)
-		return errorx.New(errorx.CodeInternalServerError, err)
+		return repoError(err)
+	}
+
+	// 新文章会出现在列表中，同时清除该 ID 可能存在的负缓存
+	if err := uc.invalidateArticle(ctx, article.ID); err != nil {
+		uc.logger.Error("failed to invalidate article cache", "error", err)
 	}
 
 	uc.logger.Info("article created successfully", "article_id", article.ID)
@@ -67,35 +98,41 @@ func (uc *ArticleUsecase) CreateArticle(ctx context.Context, article *domain.Art
 
 // GetArticleByID 获取单篇文章
 func (uc *ArticleUsecase) GetArticleByID(ctx context.Context, id int64) (*domain.Article, error) {
-	// 1. 尝试从缓存获取
-	cachedArticle, err := uc.cache.GetArticle(ctx, uint(id))
+	// 1. 尝试从缓存获取，陈旧条目照常返回并在后台刷新
+	entry, err := uc.cache.GetArticle(ctx, uint(id))
 	if err != nil {
 		uc.logger.Error("failed to get article from cache", "error", err)
 		// 如果缓存出错，我们选择忽略并继续从数据库中获取，而不是直接返回错误
+		entry = nil
 	}
-	if cachedArticle != nil {
-		uc.logger.Info("article cache hit", "article_id", id)
-		return cachedArticle, nil
+	if entry != nil {
+		if entry.Stale(uc.now()) {
+			uc.logger.Info("article cache stale", "article_id", id)
+			uc.refreshArticle(ctx, id)
+		} else {
+			uc.logger.Info("article cache hit", "article_id", id)
+		}
+		return articleFromEntry(entry)
 	}
 
 	uc.logger.Info("article cache miss", "article_id", id)
-	// 2. 缓存未命中，从数据库获取
-	article, err := uc.repo.GetByID(ctx, id)
+	// 2. 缓存未命中，合并并发请求后从数据库获取并写回缓存。加载不随首个调用方取消，
+	// 以免拖累共享结果的其他请求。
+	loaded, err, _ := uc.loads.Do(articleCacheKey(id), func() (interface{}, error) {
+		return uc.loadArticle(context.WithoutCancel(ctx), id, true)
+	})
 	if err != nil {
-		if errors.Is(err, repository.ErrNotFound) {
-			return nil, errorx.New(errorx.CodeArticleNotFound, err)
-		}
-		return nil, errorx.New(errorx.CodeInternalServerError, err)
+		return nil, repoError(err)
 	}
+	return articleFromEntry(loaded.(*repository.ArticleCacheEntry))
+}
 
-	// 3. 将结果存入缓存
-	err = uc.cache.SetArticle(ctx, article, 5*time.Minute)
-	if err != nil {
-		uc.logger.Error("failed to set article to cache", "error", err)
-		// 即使缓存设置失败，我们仍然返回从数据库中获取到的数据
+// articleFromEntry 将缓存条目转换为返回值，负缓存条目对应文章不存在。
+func articleFromEntry(entry *repository.ArticleCacheEntry) (*domain.Article, error) {
+	if entry.Article == nil {
+		return nil, errorx.New(articleerr.CodeArticleNotFound, repository.ErrNotFound)
 	}
-
-	return article, nil
+	return entry.Article, nil
 }
 
 // GetAllArticles 获取所有文章
@@ -112,21 +149,41 @@ func (uc *ArticleUsecase) GetAllArticles(ctx context.Context) ([]*domain.Article
 	}
 
 	uc.logger.Info("articles cache miss")
-	// 2. 缓存未命中，从数据库获取
+	// 2. 缓存未命中，从数据库获取。列表代数须在读库之前取得，
+	// 这样读库后提交的写操作会使其失效，SetArticles 拒绝写入旧列表
+	gen, err := uc.cache.ListGeneration(ctx)
+	cacheable := err == nil
+	if err != nil {
+		uc.logger.Error("failed to get article list cache generation", "error", err)
+	}
 	articles, err := uc.repo.GetAll(ctx)
 	if err != nil {
-		return nil, errorx.New(errorx.CodeInternalServerError, err)
+		return nil, repoError(err)
 	}
 
 	// 3. 将结果存入缓存
-	err = uc.cache.SetArticles(ctx, articlesCacheKey, articles, 5*time.Minute)
-	if err != nil {
-		uc.logger.Error("failed to set articles to cache", "error", err)
+	if cacheable {
+		err = uc.cache.SetArticles(ctx, articlesCacheKey, articles, gen, uc.policy.ListTTL)
+		switch {
+		case errors.Is(err, repository.ErrStaleCacheEntry):
+			uc.logger.Info("articles changed while loading, not cached")
+		case err != nil:
+			uc.logger.Error("failed to set articles to cache", "error", err)
+		}
 	}
 
 	return articles, nil
 }
 
+// ListArticles 按 ID 顺序获取一页文章。分页组合过多，不经过列表缓存，直接查询数据库
+func (uc *ArticleUsecase) ListArticles(ctx context.Context, limit, offset int) ([]*domain.Article, error) {
+	articles, err := uc.repo.List(ctx, limit, offset)
+	if err != nil {
+		return nil, repoError(err)
+	}
+	return articles, nil
+}
+
 // UpdateArticle 更新文章
 func (uc *ArticleUsecase) UpdateArticle(ctx context.Context, article *domain.Article) error {
 	// 从 context 中获取 userID
@@ -135,25 +192,30 @@ func (uc *ArticleUsecase) UpdateArticle(ctx context.Context, article *domain.Art
 		return errorx.New(errorx.CodeUnauthorized, err)
 	}
 
-	// 检查用户是否有权限修改文章
-	existingArticle, err := uc.repo.GetByID(ctx, article.ID)
-	if err != nil {
-		if errors.Is(err, repository.ErrNotFound) {
-			return errorx.New(errorx.CodeArticleNotFound, err)
+	// 权限检查与更新放在同一事务中，避免检查后文章被并发修改
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		existingArticle, err := uc.repo.GetByID(ctx, article.ID)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return errorx.New(articleerr.CodeArticleNotFound, err)
+			}
+			return repoError(err)
+		}
+		if existingArticle.AuthorID != userID {
+			return errorx.New(errorx.CodeUnauthorized, errors.New("user not authorized to update this article"))
 		}
-		return errorx.New(errorx.CodeInternalServerError, err)
-	}
-	if existingArticle.AuthorID != userID {
-		return errorx.New(errorx.CodeUnauthorized, errors.New("user not authorized to update this article"))
-	}
 
-	err = uc.repo.Update(ctx, article)
+		if err := uc.repo.Update(ctx, article); err != nil {
+			return repoError(err)
+		}
+		return nil
+	})
 	if err != nil {
-		return errorx.New(errorx.CodeInternalServerError, err)
+		return asDetailError(err)
 	}
 
-	// 更新成功后，删除缓存
-	return uc.cache.DeleteArticle(ctx, uint(article.ID))
+	// 更新成功后，删除文章缓存并使所有列表失效
+	return uc.invalidateArticle(ctx, article.ID)
 }
 
 // DeleteArticle 删除文章
@@ -164,22 +226,44 @@ func (uc *ArticleUsecase) DeleteArticle(ctx context.Context, id int64) error {
 		return errorx.New(errorx.CodeUnauthorized, err)
 	}
 
-	// 检查用户是否有权限删除文章
-	existingArticle, err := uc.repo.GetByID(ctx, id)
-	if err != nil {
-		if errors.Is(err, repository.ErrNotFound) {
-			return errorx.New(errorx.CodeArticleNotFound, err)
+	// 权限检查与删除放在同一事务中
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		existingArticle, err := uc.repo.GetByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return errorx.New(articleerr.CodeArticleNotFound, err)
+			}
+			return repoError(err)
 		}
-		return errorx.New(errorx.CodeInternalServerError, err)
+		if existingArticle.AuthorID != userID {
+			return errorx.New(errorx.CodeUnauthorized, errors.New("user not authorized to delete this article"))
+		}
+
+		if err := uc.repo.Delete(ctx, id); err != nil {
+			return repoError(err)
+		}
+		return nil
+	})
+	if err != nil {
+		return asDetailError(err)
 	}
-	if existingArticle.AuthorID != userID {
-		return errorx.New(errorx.CodeUnauthorized, errors.New("user not authorized to delete this article"))
+	// 删除成功后，删除文章缓存并使所有列表失效
+	return uc.invalidateArticle(ctx, id)
+}
+
+// asDetailError 保留事务回调中返回的业务错误，其余错误（如提交失败）交给 repoError 转换
+func asDetailError(err error) error {
+	var detailErr *errorx.DetailError
+	if errors.As(err, &detailErr) {
+		return detailErr
 	}
+	return repoError(err)
+}
 
-	err = uc.repo.Delete(ctx, id)
-	if err != nil {
-		return errorx.New(errorx.CodeInternalServerError, err)
+// repoError 将仓库错误转换为业务错误：查询超时映射为 CodeTimeout，其余为内部错误
+func repoError(err error) *errorx.DetailError {
+	if errors.Is(err, repository.ErrTimeout) {
+		return errorx.New(errorx.CodeTimeout, err)
 	}
-	// 删除成功后，删除缓存
-	return uc.cache.DeleteArticle(ctx, uint(id))
+	return errorx.New(errorx.CodeInternalServerError, err)
 }
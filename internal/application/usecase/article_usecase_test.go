@@ -3,18 +3,30 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
 	"github.com/FormalYou/clean-architecture-blog/domain"
 	mock_contracts "github.com/FormalYou/clean-architecture-blog/internal/application/contracts/mocks"
 	"github.com/FormalYou/clean-architecture-blog/internal/application/repository"
 	mock_repo "github.com/FormalYou/clean-architecture-blog/internal/application/repository/mocks"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/usecase/articleerr"
 	"github.com/FormalYou/clean-architecture-blog/internal/errorx"
 )
 
+// expectTx expects one WithinTx call that runs its callback directly.
+func expectTx(m *mock_repo.MockTxManager) {
+	m.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	})
+}
+
 func TestArticleUsecase_CreateArticle(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -23,8 +35,9 @@ func TestArticleUsecase_CreateArticle(t *testing.T) {
 	mockArticleCacheRepo := mock_repo.NewMockArticleCacheRepository(ctrl)
 	mockAuthSvc := mock_contracts.NewMockAuthService(ctrl)
 	mockLogger := mock_contracts.NewMockLogger(ctrl)
+	mockTxManager := mock_repo.NewMockTxManager(ctrl)
 
-	usecase := NewArticleUsecase(mockArticleRepo, mockArticleCacheRepo, mockAuthSvc, mockLogger)
+	usecase := NewArticleUsecase(mockArticleRepo, mockArticleCacheRepo, mockTxManager, mockAuthSvc, mockLogger)
 
 	testCases := []struct {
 		name          string
@@ -40,7 +53,10 @@ func TestArticleUsecase_CreateArticle(t *testing.T) {
 			},
 			setupMocks: func() {
 				mockAuthSvc.EXPECT().GetUserIDFromContext(gomock.Any()).Return(int64(1), nil)
+				expectTx(mockTxManager)
 				mockArticleRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				mockArticleCacheRepo.EXPECT().DeleteArticle(gomock.Any(), gomock.Any()).Return(nil)
+				mockArticleCacheRepo.EXPECT().InvalidateArticleLists(gomock.Any()).Return(nil)
 				mockLogger.EXPECT().Info(gomock.Any(), gomock.Any(), gomock.Any())
 			},
 			expectedError: nil,
@@ -96,10 +112,13 @@ func TestArticleUsecase_GetArticleByID(t *testing.T) {
 	mockArticleCacheRepo := mock_repo.NewMockArticleCacheRepository(ctrl)
 	mockAuthSvc := mock_contracts.NewMockAuthService(ctrl)
 	mockLogger := mock_contracts.NewMockLogger(ctrl)
+	mockTxManager := mock_repo.NewMockTxManager(ctrl)
 
-	usecase := NewArticleUsecase(mockArticleRepo, mockArticleCacheRepo, mockAuthSvc, mockLogger)
+	usecase := NewArticleUsecase(mockArticleRepo, mockArticleCacheRepo, mockTxManager, mockAuthSvc, mockLogger)
 
 	expectedArticle := &domain.Article{ID: 1, Title: "Cached Article"}
+	policy := DefaultArticleCachePolicy
+	fresh := &repository.ArticleCacheEntry{Article: expectedArticle, FreshUntil: time.Now().Add(time.Minute)}
 
 	testCases := []struct {
 		name            string
@@ -112,21 +131,36 @@ func TestArticleUsecase_GetArticleByID(t *testing.T) {
 			name:      "Cache Hit",
 			articleID: 1,
 			setupMocks: func() {
-				mockArticleCacheRepo.EXPECT().GetArticle(gomock.Any(), uint(1)).Return(expectedArticle, nil)
+				mockArticleCacheRepo.EXPECT().GetArticle(gomock.Any(), uint(1)).Return(fresh, nil)
 				mockLogger.EXPECT().Info(gomock.Any(), gomock.Any())
 			},
 			expectedArticle: expectedArticle,
 			expectedError:   nil,
 		},
 		{
-			name:      "Cache Miss, DB Success",
+			name:      "Negative Cache Hit",
+			articleID: 5,
+			setupMocks: func() {
+				mockArticleCacheRepo.EXPECT().GetArticle(gomock.Any(), uint(5)).Return(&repository.ArticleCacheEntry{FreshUntil: time.Now().Add(time.Minute)}, nil)
+				mockLogger.EXPECT().Info(gomock.Any(), gomock.Any())
+			},
+			expectedArticle: nil,
+			expectedError:   errorx.New(articleerr.CodeArticleNotFound, repository.ErrNotFound),
+		},
+		{
+			name:      "Cache Error, DB Success",
 			articleID: 2,
 			setupMocks: func() {
-				mockArticleCacheRepo.EXPECT().GetArticle(gomock.Any(), uint(2)).Return(nil, repository.ErrNotFound)
+				mockArticleCacheRepo.EXPECT().GetArticle(gomock.Any(), uint(2)).Return(nil, errors.New("redis down"))
 				mockLogger.EXPECT().Error(gomock.Any(), gomock.Any())
 				mockLogger.EXPECT().Info(gomock.Any(), gomock.Any())
+				mockArticleCacheRepo.EXPECT().ArticleGeneration(gomock.Any(), uint(2)).Return(int64(0), nil)
 				mockArticleRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(expectedArticle, nil)
-				mockArticleCacheRepo.EXPECT().SetArticle(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				mockArticleCacheRepo.EXPECT().SetArticle(gomock.Any(), uint(2), gomock.Any(), policy.FreshTTL+policy.StaleTTL).
+					DoAndReturn(func(_ context.Context, _ uint, entry *repository.ArticleCacheEntry, _ time.Duration) error {
+						assert.Equal(t, expectedArticle, entry.Article)
+						return nil
+					})
 			},
 			expectedArticle: expectedArticle,
 			expectedError:   nil,
@@ -135,22 +169,45 @@ func TestArticleUsecase_GetArticleByID(t *testing.T) {
 			name:      "Cache Miss, DB Not Found",
 			articleID: 3,
 			setupMocks: func() {
-				mockArticleCacheRepo.EXPECT().GetArticle(gomock.Any(), uint(3)).Return(nil, repository.ErrNotFound)
-				mockLogger.EXPECT().Error(gomock.Any(), gomock.Any())
+				mockArticleCacheRepo.EXPECT().GetArticle(gomock.Any(), uint(3)).Return(nil, nil)
 				mockLogger.EXPECT().Info(gomock.Any(), gomock.Any())
+				mockArticleCacheRepo.EXPECT().ArticleGeneration(gomock.Any(), uint(3)).Return(int64(0), nil)
 				mockArticleRepo.EXPECT().GetByID(gomock.Any(), int64(3)).Return(nil, repository.ErrNotFound)
+				mockArticleCacheRepo.EXPECT().SetArticle(gomock.Any(), uint(3), gomock.Any(), policy.NotFoundTTL).
+					DoAndReturn(func(_ context.Context, _ uint, entry *repository.ArticleCacheEntry, _ time.Duration) error {
+						assert.Nil(t, entry.Article, "not-found IDs are cached as negative entries")
+						return nil
+					})
 			},
 			expectedArticle: nil,
-			expectedError:   errorx.New(errorx.CodeArticleNotFound, repository.ErrNotFound),
+			expectedError:   errorx.New(articleerr.CodeArticleNotFound, repository.ErrNotFound),
+		},
+		{
+			name:      "Cache Miss, Article Changed While Loading",
+			articleID: 6,
+			setupMocks: func() {
+				mockArticleCacheRepo.EXPECT().GetArticle(gomock.Any(), uint(6)).Return(nil, nil)
+				mockLogger.EXPECT().Info("article cache miss", gomock.Any())
+				mockArticleCacheRepo.EXPECT().ArticleGeneration(gomock.Any(), uint(6)).Return(int64(3), nil)
+				mockArticleRepo.EXPECT().GetByID(gomock.Any(), int64(6)).Return(expectedArticle, nil)
+				mockArticleCacheRepo.EXPECT().SetArticle(gomock.Any(), uint(6), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ uint, entry *repository.ArticleCacheEntry, _ time.Duration) error {
+						assert.Equal(t, int64(3), entry.Generation, "the generation read before the database")
+						return repository.ErrStaleCacheEntry
+					})
+				mockLogger.EXPECT().Info("article changed while loading, not cached", gomock.Any())
+			},
+			expectedArticle: expectedArticle,
+			expectedError:   nil,
 		},
 		{
 			name:      "Cache Miss, DB Error",
 			articleID: 4,
 			setupMocks: func() {
-				mockArticleCacheRepo.EXPECT().GetArticle(gomock.Any(), uint(4)).Return(nil, repository.ErrNotFound)
-				mockLogger.EXPECT().Error(gomock.Any(), gomock.Any())
+				mockArticleCacheRepo.EXPECT().GetArticle(gomock.Any(), uint(4)).Return(nil, nil)
 				mockLogger.EXPECT().Info(gomock.Any(), gomock.Any())
 				dbError := errors.New("db connection failed")
+				mockArticleCacheRepo.EXPECT().ArticleGeneration(gomock.Any(), uint(4)).Return(int64(0), nil)
 				mockArticleRepo.EXPECT().GetByID(gomock.Any(), int64(4)).Return(nil, dbError)
 			},
 			expectedArticle: nil,
@@ -177,6 +234,375 @@ func TestArticleUsecase_GetArticleByID(t *testing.T) {
 	}
 }
 
+func TestArticleUsecase_GetArticleByID_CoalescesMisses(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockArticleRepo := mock_repo.NewMockArticleRepository(ctrl)
+	mockArticleCacheRepo := mock_repo.NewMockArticleCacheRepository(ctrl)
+	mockLogger := mock_contracts.NewMockLogger(ctrl)
+	mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+
+	usecase := NewArticleUsecase(mockArticleRepo, mockArticleCacheRepo, nil, nil, mockLogger)
+
+	const callers = 20
+	var missed sync.WaitGroup
+	missed.Add(callers)
+	mockArticleCacheRepo.EXPECT().GetArticle(gomock.Any(), uint(1)).Times(callers).DoAndReturn(func(context.Context, uint) (*repository.ArticleCacheEntry, error) {
+		missed.Done()
+		return nil, nil
+	})
+	article := &domain.Article{ID: 1, Title: "hot"}
+	mockArticleCacheRepo.EXPECT().ArticleGeneration(gomock.Any(), uint(1)).Return(int64(0), nil)
+	mockArticleRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Times(1).DoAndReturn(func(context.Context, int64) (*domain.Article, error) {
+		// Hold the load until every caller has missed the cache and joined it.
+		missed.Wait()
+		time.Sleep(20 * time.Millisecond)
+		return article, nil
+	})
+	mockArticleCacheRepo.EXPECT().SetArticle(gomock.Any(), uint(1), gomock.Any(), gomock.Any()).Times(1).Return(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			callCtx := context.Background()
+			if i == 0 {
+				callCtx = ctx
+			}
+			got, err := usecase.GetArticleByID(callCtx, 1)
+			assert.NoError(t, err)
+			assert.Equal(t, article, got)
+		}(i)
+	}
+	cancel() // one caller going away does not fail the shared load
+	wg.Wait()
+}
+
+func TestArticleUsecase_GetArticleByID_ServesStaleWhileRefreshing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockArticleRepo := mock_repo.NewMockArticleRepository(ctrl)
+	mockArticleCacheRepo := mock_repo.NewMockArticleCacheRepository(ctrl)
+	mockLogger := mock_contracts.NewMockLogger(ctrl)
+	mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+
+	uc := NewArticleUsecase(mockArticleRepo, mockArticleCacheRepo, nil, nil, mockLogger).(*ArticleUsecase)
+
+	stale := &repository.ArticleCacheEntry{Article: &domain.Article{ID: 1, Title: "old"}, FreshUntil: time.Now().Add(-time.Second)}
+	mockArticleCacheRepo.EXPECT().GetArticle(gomock.Any(), uint(1)).Return(stale, nil).Times(3)
+
+	release := make(chan struct{})
+	mockArticleCacheRepo.EXPECT().ArticleGeneration(gomock.Any(), uint(1)).Return(int64(0), nil)
+	mockArticleRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Times(1).DoAndReturn(func(context.Context, int64) (*domain.Article, error) {
+		<-release
+		return &domain.Article{ID: 1, Title: "new"}, nil
+	})
+	mockArticleCacheRepo.EXPECT().SetArticle(gomock.Any(), uint(1), gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(_ context.Context, _ uint, entry *repository.ArticleCacheEntry, _ time.Duration) error {
+			assert.Equal(t, "new", entry.Article.Title)
+			assert.False(t, entry.Stale(time.Now()))
+			return nil
+		})
+
+	for i := 0; i < 3; i++ {
+		got, err := uc.GetArticleByID(context.Background(), 1)
+		assert.NoError(t, err)
+		assert.Equal(t, "old", got.Title, "stale data is served while the refresh runs")
+	}
+	close(release)
+	uc.refreshes.Wait()
+}
+
+func TestArticleUsecase_CloseWaitsForRefreshes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockArticleRepo := mock_repo.NewMockArticleRepository(ctrl)
+	mockArticleCacheRepo := mock_repo.NewMockArticleCacheRepository(ctrl)
+	mockLogger := mock_contracts.NewMockLogger(ctrl)
+	mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+
+	uc := NewArticleUsecase(mockArticleRepo, mockArticleCacheRepo, nil, nil, mockLogger).(*ArticleUsecase)
+
+	stale := &repository.ArticleCacheEntry{Article: &domain.Article{ID: 1, Title: "old"}, FreshUntil: time.Now().Add(-time.Second)}
+	mockArticleCacheRepo.EXPECT().GetArticle(gomock.Any(), uint(1)).Return(stale, nil).Times(2)
+
+	release := make(chan struct{})
+	mockArticleCacheRepo.EXPECT().ArticleGeneration(gomock.Any(), uint(1)).Return(int64(0), nil)
+	mockArticleRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Times(1).DoAndReturn(func(context.Context, int64) (*domain.Article, error) {
+		<-release
+		return &domain.Article{ID: 1, Title: "new"}, nil
+	})
+	mockArticleCacheRepo.EXPECT().SetArticle(gomock.Any(), uint(1), gomock.Any(), gomock.Any()).Return(nil)
+
+	_, err := uc.GetArticleByID(context.Background(), 1)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, uc.Close(ctx), context.DeadlineExceeded, "the refresh is still running")
+
+	// Once closed, stale entries are served without starting another refresh.
+	got, err := uc.GetArticleByID(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "old", got.Title)
+
+	close(release)
+	assert.NoError(t, uc.Close(context.Background()))
+}
+
+func TestArticleUsecase_GetArticleByID_CacheLock(t *testing.T) {
+	article := &domain.Article{ID: 1, Title: "title"}
+
+	testCases := []struct {
+		name       string
+		setupMocks func(cache *mock_repo.MockArticleCacheRepository, repo *mock_repo.MockArticleRepository, locker *mock_repo.MockCacheLocker)
+	}{
+		{
+			name: "Lock Acquired",
+			setupMocks: func(cache *mock_repo.MockArticleCacheRepository, repo *mock_repo.MockArticleRepository, locker *mock_repo.MockCacheLocker) {
+				unlocked := false
+				locker.EXPECT().TryLock(gomock.Any(), "article:1", DefaultArticleCachePolicy.LockTTL).Return(func(context.Context) error {
+					unlocked = true
+					return nil
+				}, true, nil)
+				cache.EXPECT().GetArticle(gomock.Any(), uint(1)).Return(nil, nil)
+				cache.EXPECT().ArticleGeneration(gomock.Any(), uint(1)).Return(int64(0), nil)
+				repo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(article, nil)
+				cache.EXPECT().SetArticle(gomock.Any(), uint(1), gomock.Any(), gomock.Any()).DoAndReturn(func(context.Context, uint, *repository.ArticleCacheEntry, time.Duration) error {
+					assert.False(t, unlocked, "the lock is held until the cache is filled")
+					return nil
+				})
+			},
+		},
+		{
+			name: "Peer Fills Cache",
+			setupMocks: func(cache *mock_repo.MockArticleCacheRepository, repo *mock_repo.MockArticleRepository, locker *mock_repo.MockCacheLocker) {
+				locker.EXPECT().TryLock(gomock.Any(), "article:1", gomock.Any()).Return(nil, false, nil)
+				gomock.InOrder(
+					cache.EXPECT().GetArticle(gomock.Any(), uint(1)).Return(nil, nil).Times(2),
+					cache.EXPECT().GetArticle(gomock.Any(), uint(1)).Return(&repository.ArticleCacheEntry{Article: article, FreshUntil: time.Now().Add(time.Minute)}, nil),
+				)
+			},
+		},
+		{
+			name: "Peer Too Slow",
+			setupMocks: func(cache *mock_repo.MockArticleCacheRepository, repo *mock_repo.MockArticleRepository, locker *mock_repo.MockCacheLocker) {
+				locker.EXPECT().TryLock(gomock.Any(), "article:1", gomock.Any()).Return(nil, false, nil)
+				cache.EXPECT().GetArticle(gomock.Any(), uint(1)).Return(nil, nil).MinTimes(2)
+				cache.EXPECT().ArticleGeneration(gomock.Any(), uint(1)).Return(int64(0), nil)
+				repo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(article, nil)
+				cache.EXPECT().SetArticle(gomock.Any(), uint(1), gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "Lock Unavailable",
+			setupMocks: func(cache *mock_repo.MockArticleCacheRepository, repo *mock_repo.MockArticleRepository, locker *mock_repo.MockCacheLocker) {
+				locker.EXPECT().TryLock(gomock.Any(), "article:1", gomock.Any()).Return(nil, false, errors.New("redis down"))
+				cache.EXPECT().GetArticle(gomock.Any(), uint(1)).Return(nil, nil)
+				cache.EXPECT().ArticleGeneration(gomock.Any(), uint(1)).Return(int64(0), nil)
+				repo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(article, nil)
+				cache.EXPECT().SetArticle(gomock.Any(), uint(1), gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockArticleRepo := mock_repo.NewMockArticleRepository(ctrl)
+			mockArticleCacheRepo := mock_repo.NewMockArticleCacheRepository(ctrl)
+			mockLocker := mock_repo.NewMockCacheLocker(ctrl)
+			mockLogger := mock_contracts.NewMockLogger(ctrl)
+			mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+			mockLogger.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+
+			policy := DefaultArticleCachePolicy
+			policy.LockWait = 50 * time.Millisecond
+			usecase := NewArticleUsecase(mockArticleRepo, mockArticleCacheRepo, nil, nil, mockLogger,
+				WithArticleCachePolicy(policy), WithCacheLocker(mockLocker))
+			tc.setupMocks(mockArticleCacheRepo, mockArticleRepo, mockLocker)
+
+			got, err := usecase.GetArticleByID(context.Background(), 1)
+			assert.NoError(t, err)
+			assert.Equal(t, article, got)
+		})
+	}
+}
+
+func TestArticleUsecase_GetArticleByID_CacheUnavailableNotLogged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	article := &domain.Article{ID: 1, Title: "title"}
+	mockArticleRepo := mock_repo.NewMockArticleRepository(ctrl)
+	mockArticleCacheRepo := mock_repo.NewMockArticleCacheRepository(ctrl)
+	mockLocker := mock_repo.NewMockCacheLocker(ctrl)
+	// No Error expectation: an open breaker is expected, not an error per miss.
+	mockLogger := mock_contracts.NewMockLogger(ctrl)
+	mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+
+	usecase := NewArticleUsecase(mockArticleRepo, mockArticleCacheRepo, nil, nil, mockLogger, WithCacheLocker(mockLocker))
+	mockLocker.EXPECT().TryLock(gomock.Any(), "article:1", gomock.Any()).
+		Return(nil, false, fmt.Errorf("set lock: %w", repository.ErrCacheUnavailable))
+	mockArticleCacheRepo.EXPECT().GetArticle(gomock.Any(), uint(1)).Return(nil, nil)
+	mockArticleCacheRepo.EXPECT().ArticleGeneration(gomock.Any(), uint(1)).Return(int64(0), nil)
+	mockArticleRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(article, nil)
+	mockArticleCacheRepo.EXPECT().SetArticle(gomock.Any(), uint(1), gomock.Any(), gomock.Any()).Return(nil)
+
+	got, err := usecase.GetArticleByID(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, article, got)
+}
+
+func TestArticleUsecase_RefreshSkippedWhenPeerHoldsLock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockArticleRepo := mock_repo.NewMockArticleRepository(ctrl)
+	mockArticleCacheRepo := mock_repo.NewMockArticleCacheRepository(ctrl)
+	mockLocker := mock_repo.NewMockCacheLocker(ctrl)
+	mockLogger := mock_contracts.NewMockLogger(ctrl)
+	mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+
+	uc := NewArticleUsecase(mockArticleRepo, mockArticleCacheRepo, nil, nil, mockLogger, WithCacheLocker(mockLocker)).(*ArticleUsecase)
+
+	stale := &repository.ArticleCacheEntry{Article: &domain.Article{ID: 1}, FreshUntil: time.Now().Add(-time.Second)}
+	mockArticleCacheRepo.EXPECT().GetArticle(gomock.Any(), uint(1)).Return(stale, nil)
+	mockLocker.EXPECT().TryLock(gomock.Any(), "article:1", gomock.Any()).Return(nil, false, nil)
+	// No GetByID: the instance holding the lock refreshes the entry.
+
+	_, err := uc.GetArticleByID(context.Background(), 1)
+	assert.NoError(t, err)
+	uc.refreshes.Wait()
+}
+
+func TestArticleUsecase_GetAllArticles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockArticleRepo := mock_repo.NewMockArticleRepository(ctrl)
+	mockArticleCacheRepo := mock_repo.NewMockArticleCacheRepository(ctrl)
+	mockLogger := mock_contracts.NewMockLogger(ctrl)
+	mockLogger.EXPECT().Info(gomock.Any()).AnyTimes()
+
+	policy := DefaultArticleCachePolicy
+	policy.ListTTL = 42 * time.Second
+	usecase := NewArticleUsecase(mockArticleRepo, mockArticleCacheRepo, nil, nil, mockLogger, WithArticleCachePolicy(policy))
+
+	articles := []*domain.Article{{ID: 1}, {ID: 2}}
+
+	testCases := []struct {
+		name             string
+		setupMocks       func()
+		expectedArticles []*domain.Article
+		expectedError    error
+	}{
+		{
+			name: "Cache Hit",
+			setupMocks: func() {
+				mockArticleCacheRepo.EXPECT().GetArticles(gomock.Any(), "articles:all").Return(articles, nil)
+			},
+			expectedArticles: articles,
+		},
+		{
+			name: "Cache Miss, DB Success",
+			setupMocks: func() {
+				mockArticleCacheRepo.EXPECT().GetArticles(gomock.Any(), "articles:all").Return(nil, nil)
+				mockArticleCacheRepo.EXPECT().ListGeneration(gomock.Any()).Return(int64(3), nil)
+				mockArticleRepo.EXPECT().GetAll(gomock.Any()).Return(articles, nil)
+				mockArticleCacheRepo.EXPECT().SetArticles(gomock.Any(), "articles:all", articles, int64(3), 42*time.Second).Return(nil)
+			},
+			expectedArticles: articles,
+		},
+		{
+			name: "Cache Miss, Lists Invalidated While Loading",
+			setupMocks: func() {
+				// The generation is read before the database, so a write
+				// committing in between makes the cache refuse the old list.
+				mockArticleCacheRepo.EXPECT().GetArticles(gomock.Any(), "articles:all").Return(nil, nil)
+				gomock.InOrder(
+					mockArticleCacheRepo.EXPECT().ListGeneration(gomock.Any()).Return(int64(3), nil),
+					mockArticleRepo.EXPECT().GetAll(gomock.Any()).Return(articles, nil),
+					mockArticleCacheRepo.EXPECT().SetArticles(gomock.Any(), "articles:all", articles, int64(3), gomock.Any()).
+						Return(repository.ErrStaleCacheEntry),
+				)
+			},
+			expectedArticles: articles,
+		},
+		{
+			name: "Cache Miss, DB Error",
+			setupMocks: func() {
+				mockArticleCacheRepo.EXPECT().GetArticles(gomock.Any(), "articles:all").Return(nil, nil)
+				mockArticleCacheRepo.EXPECT().ListGeneration(gomock.Any()).Return(int64(0), nil)
+				mockArticleRepo.EXPECT().GetAll(gomock.Any()).Return(nil, errors.New("db connection failed"))
+			},
+			expectedError: errorx.New(errorx.CodeInternalServerError, errors.New("db connection failed")),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.setupMocks()
+			got, err := usecase.GetAllArticles(context.Background())
+
+			if tc.expectedError != nil {
+				var detailErr *errorx.DetailError
+				if assert.ErrorAs(t, err, &detailErr) {
+					assert.Equal(t, tc.expectedError.(*errorx.DetailError).Code, detailErr.Code)
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedArticles, got)
+			}
+		})
+	}
+}
+
+func TestArticleUsecase_ListArticles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// No cache expectations: pages are read from the repository directly.
+	mockArticleRepo := mock_repo.NewMockArticleRepository(ctrl)
+	usecase := NewArticleUsecase(mockArticleRepo, mock_repo.NewMockArticleCacheRepository(ctrl), nil, nil, mock_contracts.NewMockLogger(ctrl))
+
+	articles := []*domain.Article{{ID: 3}, {ID: 4}}
+
+	testCases := []struct {
+		name             string
+		setupMocks       func()
+		expectedArticles []*domain.Article
+		expectedError    error
+	}{
+		{
+			name: "Success",
+			setupMocks: func() {
+				mockArticleRepo.EXPECT().List(gomock.Any(), 2, 2).Return(articles, nil)
+			},
+			expectedArticles: articles,
+		},
+		{
+			name: "DB Error",
+			setupMocks: func() {
+				mockArticleRepo.EXPECT().List(gomock.Any(), 2, 2).Return(nil, errors.New("db connection failed"))
+			},
+			expectedError: errorx.New(errorx.CodeInternalServerError, errors.New("db connection failed")),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.setupMocks()
+			got, err := usecase.ListArticles(context.Background(), 2, 2)
+
+			if tc.expectedError != nil {
+				var detailErr *errorx.DetailError
+				if assert.ErrorAs(t, err, &detailErr) {
+					assert.Equal(t, tc.expectedError.(*errorx.DetailError).Code, detailErr.Code)
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedArticles, got)
+			}
+		})
+	}
+}
+
 func TestArticleUsecase_UpdateArticle(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -185,8 +611,9 @@ func TestArticleUsecase_UpdateArticle(t *testing.T) {
 	mockArticleCacheRepo := mock_repo.NewMockArticleCacheRepository(ctrl)
 	mockAuthSvc := mock_contracts.NewMockAuthService(ctrl)
 	mockLogger := mock_contracts.NewMockLogger(ctrl)
+	mockTxManager := mock_repo.NewMockTxManager(ctrl)
 
-	usecase := NewArticleUsecase(mockArticleRepo, mockArticleCacheRepo, mockAuthSvc, mockLogger)
+	usecase := NewArticleUsecase(mockArticleRepo, mockArticleCacheRepo, mockTxManager, mockAuthSvc, mockLogger)
 
 	existingArticle := &domain.Article{ID: 1, AuthorID: 100, Title: "Old Title", Content: "Old Content"}
 	updatedArticle := &domain.Article{ID: 1, AuthorID: 100, Title: "New Title", Content: "New Content"}
@@ -204,9 +631,11 @@ func TestArticleUsecase_UpdateArticle(t *testing.T) {
 			ctxUserID:    100,
 			setupMocks: func() {
 				mockAuthSvc.EXPECT().GetUserIDFromContext(gomock.Any()).Return(int64(100), nil)
+				expectTx(mockTxManager)
 				mockArticleRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(existingArticle, nil)
 				mockArticleRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
 				mockArticleCacheRepo.EXPECT().DeleteArticle(gomock.Any(), uint(1)).Return(nil)
+				mockArticleCacheRepo.EXPECT().InvalidateArticleLists(gomock.Any()).Return(nil)
 			},
 			expectedError: nil,
 		},
@@ -216,6 +645,7 @@ func TestArticleUsecase_UpdateArticle(t *testing.T) {
 			ctxUserID:    999,
 			setupMocks: func() {
 				mockAuthSvc.EXPECT().GetUserIDFromContext(gomock.Any()).Return(int64(999), nil)
+				expectTx(mockTxManager)
 				mockArticleRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(existingArticle, nil)
 			},
 			expectedError: errorx.New(errorx.CodeUnauthorized, errors.New("user not authorized to update this article")),
@@ -226,9 +656,20 @@ func TestArticleUsecase_UpdateArticle(t *testing.T) {
 			ctxUserID:    100,
 			setupMocks: func() {
 				mockAuthSvc.EXPECT().GetUserIDFromContext(gomock.Any()).Return(int64(100), nil)
+				expectTx(mockTxManager)
 				mockArticleRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(nil, repository.ErrNotFound)
 			},
-			expectedError: errorx.New(errorx.CodeArticleNotFound, repository.ErrNotFound),
+			expectedError: errorx.New(articleerr.CodeArticleNotFound, repository.ErrNotFound),
+		},
+		{
+			name:         "Commit Failure",
+			inputArticle: updatedArticle,
+			ctxUserID:    100,
+			setupMocks: func() {
+				mockAuthSvc.EXPECT().GetUserIDFromContext(gomock.Any()).Return(int64(100), nil)
+				mockTxManager.EXPECT().WithinTx(gomock.Any(), gomock.Any()).Return(errors.New("commit failed"))
+			},
+			expectedError: errorx.New(errorx.CodeInternalServerError, errors.New("commit failed")),
 		},
 	}
 
@@ -261,8 +702,9 @@ func TestArticleUsecase_DeleteArticle(t *testing.T) {
 	mockArticleCacheRepo := mock_repo.NewMockArticleCacheRepository(ctrl)
 	mockAuthSvc := mock_contracts.NewMockAuthService(ctrl)
 	mockLogger := mock_contracts.NewMockLogger(ctrl)
+	mockTxManager := mock_repo.NewMockTxManager(ctrl)
 
-	usecase := NewArticleUsecase(mockArticleRepo, mockArticleCacheRepo, mockAuthSvc, mockLogger)
+	usecase := NewArticleUsecase(mockArticleRepo, mockArticleCacheRepo, mockTxManager, mockAuthSvc, mockLogger)
 
 	existingArticle := &domain.Article{ID: 1, AuthorID: 100}
 
@@ -279,9 +721,11 @@ func TestArticleUsecase_DeleteArticle(t *testing.T) {
 			ctxUserID: 100,
 			setupMocks: func() {
 				mockAuthSvc.EXPECT().GetUserIDFromContext(gomock.Any()).Return(int64(100), nil)
+				expectTx(mockTxManager)
 				mockArticleRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(existingArticle, nil)
 				mockArticleRepo.EXPECT().Delete(gomock.Any(), int64(1)).Return(nil)
 				mockArticleCacheRepo.EXPECT().DeleteArticle(gomock.Any(), uint(1)).Return(nil)
+				mockArticleCacheRepo.EXPECT().InvalidateArticleLists(gomock.Any()).Return(nil)
 			},
 			expectedError: nil,
 		},
@@ -291,6 +735,7 @@ func TestArticleUsecase_DeleteArticle(t *testing.T) {
 			ctxUserID: 999,
 			setupMocks: func() {
 				mockAuthSvc.EXPECT().GetUserIDFromContext(gomock.Any()).Return(int64(999), nil)
+				expectTx(mockTxManager)
 				mockArticleRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(existingArticle, nil)
 			},
 			expectedError: errorx.New(errorx.CodeUnauthorized, errors.New("user not authorized to delete this article")),
@@ -301,9 +746,10 @@ func TestArticleUsecase_DeleteArticle(t *testing.T) {
 			ctxUserID: 100,
 			setupMocks: func() {
 				mockAuthSvc.EXPECT().GetUserIDFromContext(gomock.Any()).Return(int64(100), nil)
+				expectTx(mockTxManager)
 				mockArticleRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(nil, repository.ErrNotFound)
 			},
-			expectedError: errorx.New(errorx.CodeArticleNotFound, repository.ErrNotFound),
+			expectedError: errorx.New(articleerr.CodeArticleNotFound, repository.ErrNotFound),
 		},
 	}
 
@@ -0,0 +1,22 @@
+// Package articleerr owns the error codes of the article bounded context
+// (30xxx).
+package articleerr
+
+import (
+	"net/http"
+
+	"go.uber.org/zap/zapcore"
+
+	"github.com/FormalYou/clean-architecture-blog/internal/errorx"
+)
+
+// Article service error codes (30xxx).
+const (
+	CodeArticleNotFound = 30001
+)
+
+var codes = errorx.Reserve("article", 30000, 39999)
+
+func init() {
+	codes.Register(CodeArticleNotFound, "Article not found", http.StatusNotFound, zapcore.WarnLevel)
+}
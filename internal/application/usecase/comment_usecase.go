@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/FormalYou/clean-architecture-blog/domain"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/contracts"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/repository"
+)
+
+// CommentUsecaseInterface 定义了评论相关的业务逻辑接口
+type CommentUsecaseInterface interface {
+	// GetCommentsByArticleIDs 批量获取多篇文章的评论，结果按文章 ID 索引，每篇文章的评论按发布顺序排列；
+	// limit 为正时每篇文章只取前 limit 条
+	GetCommentsByArticleIDs(ctx context.Context, articleIDs []int64, limit int) (map[int64][]*domain.Comment, error)
+}
+
+// CommentUsecase 提供了评论相关的业务逻辑
+type CommentUsecase struct {
+	repo   repository.CommentRepository
+	logger contracts.Logger
+}
+
+// NewCommentUsecase 创建一个新的 CommentUsecase
+func NewCommentUsecase(repo repository.CommentRepository, logger contracts.Logger) CommentUsecaseInterface {
+	return &CommentUsecase{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// GetCommentsByArticleIDs 以一次查询获取多篇文章的评论，重复的 ID 只查询一次
+func (uc *CommentUsecase) GetCommentsByArticleIDs(ctx context.Context, articleIDs []int64, limit int) (map[int64][]*domain.Comment, error) {
+	comments := make(map[int64][]*domain.Comment, len(articleIDs))
+	if len(articleIDs) == 0 {
+		return comments, nil
+	}
+
+	unique := make([]int64, 0, len(articleIDs))
+	for _, id := range articleIDs {
+		if _, seen := comments[id]; !seen {
+			comments[id] = nil
+			unique = append(unique, id)
+		}
+	}
+
+	found, err := uc.repo.FindByArticleIDs(ctx, unique, limit)
+	if err != nil {
+		uc.logger.Error("failed to get comments by article ids", "count", len(unique), "error", err)
+		return nil, repoError(err)
+	}
+	for _, comment := range found {
+		comments[comment.ArticleID] = append(comments[comment.ArticleID], comment)
+	}
+	return comments, nil
+}
@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/FormalYou/clean-architecture-blog/domain"
+	mock_contracts "github.com/FormalYou/clean-architecture-blog/internal/application/contracts/mocks"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/repository"
+	mock_repo "github.com/FormalYou/clean-architecture-blog/internal/application/repository/mocks"
+	"github.com/FormalYou/clean-architecture-blog/internal/errorx"
+)
+
+func TestCommentUsecase_GetCommentsByArticleIDs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCommentRepo := mock_repo.NewMockCommentRepository(ctrl)
+	mockLogger := mock_contracts.NewMockLogger(ctrl)
+	commentUsecase := NewCommentUsecase(mockCommentRepo, mockLogger)
+	ctx := context.Background()
+
+	first := &domain.Comment{ID: 1, ArticleID: 1, Content: "first"}
+	second := &domain.Comment{ID: 3, ArticleID: 1, Content: "second"}
+	other := &domain.Comment{ID: 2, ArticleID: 2, Content: "other"}
+
+	testCases := []struct {
+		name             string
+		articleIDs       []int64
+		setupMocks       func()
+		expectedComments map[int64][]*domain.Comment
+		expectedCode     int
+	}{
+		{
+			name:       "Grouped By Article",
+			articleIDs: []int64{1, 2, 1, 3},
+			setupMocks: func() {
+				mockCommentRepo.EXPECT().FindByArticleIDs(ctx, []int64{1, 2, 3}, 10).Return([]*domain.Comment{first, second, other}, nil)
+			},
+			expectedComments: map[int64][]*domain.Comment{1: {first, second}, 2: {other}, 3: nil},
+		},
+		{
+			name:             "No IDs",
+			setupMocks:       func() {},
+			expectedComments: map[int64][]*domain.Comment{},
+		},
+		{
+			name:       "Query Timeout",
+			articleIDs: []int64{1},
+			setupMocks: func() {
+				mockCommentRepo.EXPECT().FindByArticleIDs(ctx, []int64{1}, 10).Return(nil, repository.ErrTimeout)
+				mockLogger.EXPECT().Error(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any())
+			},
+			expectedCode: errorx.CodeTimeout,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.setupMocks()
+			comments, err := commentUsecase.GetCommentsByArticleIDs(ctx, tc.articleIDs, 10)
+
+			if tc.expectedCode != 0 {
+				var detailErr *errorx.DetailError
+				if assert.ErrorAs(t, err, &detailErr) {
+					assert.Equal(t, tc.expectedCode, detailErr.Code)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedComments, comments)
+		})
+	}
+}
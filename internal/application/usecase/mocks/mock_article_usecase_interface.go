@@ -99,6 +99,21 @@ func (mr *MockArticleUsecaseInterfaceMockRecorder) GetArticleByID(ctx, id any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetArticleByID", reflect.TypeOf((*MockArticleUsecaseInterface)(nil).GetArticleByID), ctx, id)
 }
 
+// ListArticles mocks base method.
+func (m *MockArticleUsecaseInterface) ListArticles(ctx context.Context, limit, offset int) ([]*domain.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListArticles", ctx, limit, offset)
+	ret0, _ := ret[0].([]*domain.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListArticles indicates an expected call of ListArticles.
+func (mr *MockArticleUsecaseInterfaceMockRecorder) ListArticles(ctx, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListArticles", reflect.TypeOf((*MockArticleUsecaseInterface)(nil).ListArticles), ctx, limit, offset)
+}
+
 // UpdateArticle mocks base method.
 func (m *MockArticleUsecaseInterface) UpdateArticle(ctx context.Context, article *domain.Article) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,57 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/application/usecase/comment_usecase.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/application/usecase/comment_usecase.go -destination=internal/application/usecase/mocks/mock_comment_usecase_interface.go -package=mocks CommentUsecaseInterface
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/FormalYou/clean-architecture-blog/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCommentUsecaseInterface is a mock of CommentUsecaseInterface interface.
+type MockCommentUsecaseInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockCommentUsecaseInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockCommentUsecaseInterfaceMockRecorder is the mock recorder for MockCommentUsecaseInterface.
+type MockCommentUsecaseInterfaceMockRecorder struct {
+	mock *MockCommentUsecaseInterface
+}
+
+// NewMockCommentUsecaseInterface creates a new mock instance.
+func NewMockCommentUsecaseInterface(ctrl *gomock.Controller) *MockCommentUsecaseInterface {
+	mock := &MockCommentUsecaseInterface{ctrl: ctrl}
+	mock.recorder = &MockCommentUsecaseInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCommentUsecaseInterface) EXPECT() *MockCommentUsecaseInterfaceMockRecorder {
+	return m.recorder
+}
+
+// GetCommentsByArticleIDs mocks base method.
+func (m *MockCommentUsecaseInterface) GetCommentsByArticleIDs(ctx context.Context, articleIDs []int64, limit int) (map[int64][]*domain.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCommentsByArticleIDs", ctx, articleIDs, limit)
+	ret0, _ := ret[0].(map[int64][]*domain.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCommentsByArticleIDs indicates an expected call of GetCommentsByArticleIDs.
+func (mr *MockCommentUsecaseInterfaceMockRecorder) GetCommentsByArticleIDs(ctx, articleIDs, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommentsByArticleIDs", reflect.TypeOf((*MockCommentUsecaseInterface)(nil).GetCommentsByArticleIDs), ctx, articleIDs, limit)
+}
@@ -10,6 +10,7 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 
 	domain "github.com/FormalYou/clean-architecture-blog/domain"
@@ -40,31 +41,46 @@ func (m *MockUserUsecaseInterface) EXPECT() *MockUserUsecaseInterfaceMockRecorde
 	return m.recorder
 }
 
+// GetUsersByIDs mocks base method.
+func (m *MockUserUsecaseInterface) GetUsersByIDs(ctx context.Context, ids []int64) (map[int64]*domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsersByIDs", ctx, ids)
+	ret0, _ := ret[0].(map[int64]*domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsersByIDs indicates an expected call of GetUsersByIDs.
+func (mr *MockUserUsecaseInterfaceMockRecorder) GetUsersByIDs(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersByIDs", reflect.TypeOf((*MockUserUsecaseInterface)(nil).GetUsersByIDs), ctx, ids)
+}
+
 // Login mocks base method.
-func (m *MockUserUsecaseInterface) Login(username, password string) (string, error) {
+func (m *MockUserUsecaseInterface) Login(ctx context.Context, email, password string) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Login", username, password)
+	ret := m.ctrl.Call(m, "Login", ctx, email, password)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Login indicates an expected call of Login.
-func (mr *MockUserUsecaseInterfaceMockRecorder) Login(username, password any) *gomock.Call {
+func (mr *MockUserUsecaseInterfaceMockRecorder) Login(ctx, email, password any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockUserUsecaseInterface)(nil).Login), username, password)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockUserUsecaseInterface)(nil).Login), ctx, email, password)
 }
 
 // Register mocks base method.
-func (m *MockUserUsecaseInterface) Register(user *domain.User) error {
+func (m *MockUserUsecaseInterface) Register(ctx context.Context, user *domain.User) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Register", user)
+	ret := m.ctrl.Call(m, "Register", ctx, user)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Register indicates an expected call of Register.
-func (mr *MockUserUsecaseInterfaceMockRecorder) Register(user any) *gomock.Call {
+func (mr *MockUserUsecaseInterfaceMockRecorder) Register(ctx, user any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockUserUsecaseInterface)(nil).Register), user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockUserUsecaseInterface)(nil).Register), ctx, user)
 }
@@ -1,20 +1,24 @@
 package usecase
 
 import (
+	"context"
 	"errors"
 	"time"
 
 	"github.com/FormalYou/clean-architecture-blog/domain"
 	"github.com/FormalYou/clean-architecture-blog/internal/application/contracts"
 	"github.com/FormalYou/clean-architecture-blog/internal/application/repository"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/usecase/usererr"
 	"github.com/FormalYou/clean-architecture-blog/internal/errorx"
 	"golang.org/x/crypto/bcrypt"
 )
 
 // UserUsecaseInterface 定义了用户相关的业务逻辑接口
 type UserUsecaseInterface interface {
-	Register(user *domain.User) error
-	Login(email, password string) (string, error)
+	Register(ctx context.Context, user *domain.User) error
+	Login(ctx context.Context, email, password string) (string, error)
+	// GetUsersByIDs 批量获取用户（例如文章作者），结果按 ID 索引，不存在的用户不在其中
+	GetUsersByIDs(ctx context.Context, ids []int64) (map[int64]*domain.User, error)
 }
 
 // UserUsecase 提供了用户相关的业务逻辑
@@ -36,15 +40,21 @@ func NewUserUsecase(userRepo repository.UserRepository, authSvc contracts.AuthSe
 }
 
 // Register 处理用户注册
-func (uc *UserUsecase) Register(user *domain.User) error {
+func (uc *UserUsecase) Register(ctx context.Context, user *domain.User) error {
+	// 验证领域实体，每种接口收到的同一输入都按同一规则处理
+	if err := user.Validate(); err != nil {
+		uc.logger.Warn("user validation failed", "error", err)
+		return errorx.New(errorx.CodeInvalidParams, err)
+	}
+
 	// Check if user already exists
-	_, err := uc.userRepo.FindByEmail(user.Email)
+	_, err := uc.userRepo.FindByEmail(ctx, user.Email)
 	if err == nil {
-		return errorx.New(errorx.CodeUserAlreadyExists, nil)
+		return errorx.New(usererr.CodeUserAlreadyExists, nil)
 	} else if !errors.Is(err, repository.ErrNotFound) {
 		// A real database error occurred
 		uc.logger.Error("failed to get user by email during registration", "error", err)
-		return errorx.New(errorx.CodeInternalServerError, err)
+		return repoError(err)
 	}
 
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.PasswordHash), bcrypt.DefaultCost)
@@ -54,10 +64,10 @@ func (uc *UserUsecase) Register(user *domain.User) error {
 	}
 	user.PasswordHash = string(hashedPassword)
 
-	err = uc.userRepo.Create(user)
+	err = uc.userRepo.Create(ctx, user)
 	if err != nil {
 		uc.logger.Error("failed to create user", "error", err)
-		return errorx.New(errorx.CodeInternalServerError, err)
+		return repoError(err)
 	}
 
 	uc.logger.Info("user registered successfully", "username", user.Username)
@@ -65,20 +75,20 @@ func (uc *UserUsecase) Register(user *domain.User) error {
 }
 
 // Login 处理用户登录
-func (uc *UserUsecase) Login(email, password string) (string, error) {
-	user, err := uc.userRepo.FindByEmail(email)
+func (uc *UserUsecase) Login(ctx context.Context, email, password string) (string, error) {
+	user, err := uc.userRepo.FindByEmail(ctx, email)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
-			return "", errorx.New(errorx.CodeInvalidCredentials, err)
+			return "", errorx.New(usererr.CodeInvalidCredentials, err)
 		}
 		uc.logger.Warn("failed to get user by email", "email", email, "error", err)
-		return "", errorx.New(errorx.CodeInternalServerError, err)
+		return "", repoError(err)
 	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password))
 	if err != nil {
 		uc.logger.Warn("invalid password", "email", email, "error", err)
-		return "", errorx.New(errorx.CodeInvalidCredentials, err)
+		return "", errorx.New(usererr.CodeInvalidCredentials, err)
 	}
 
 	token, err := uc.authSvc.GenerateToken(user.ID)
@@ -90,3 +100,30 @@ func (uc *UserUsecase) Login(email, password string) (string, error) {
 	uc.logger.Info("user logged in successfully", "email", email)
 	return token, nil
 }
+
+// GetUsersByIDs 以一次查询批量获取用户，重复的 ID 只查询一次
+func (uc *UserUsecase) GetUsersByIDs(ctx context.Context, ids []int64) (map[int64]*domain.User, error) {
+	users := make(map[int64]*domain.User, len(ids))
+	if len(ids) == 0 {
+		return users, nil
+	}
+
+	unique := make([]int64, 0, len(ids))
+	seen := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	found, err := uc.userRepo.FindByIDs(ctx, unique)
+	if err != nil {
+		uc.logger.Error("failed to get users by ids", "count", len(unique), "error", err)
+		return nil, repoError(err)
+	}
+	for _, user := range found {
+		users[user.ID] = user
+	}
+	return users, nil
+}
@@ -1,18 +1,23 @@
 package usecase
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
 	"github.com/FormalYou/clean-architecture-blog/domain"
 	mock_contracts "github.com/FormalYou/clean-architecture-blog/internal/application/contracts/mocks"
 	"github.com/FormalYou/clean-architecture-blog/internal/application/repository"
 	mock_repo "github.com/FormalYou/clean-architecture-blog/internal/application/repository/mocks"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/usecase/usererr"
 	"github.com/FormalYou/clean-architecture-blog/internal/errorx"
+	"golang.org/x/crypto/bcrypt"
 )
 
 func TestUserUsecase_Register(t *testing.T) {
@@ -43,8 +48,8 @@ func TestUserUsecase_Register(t *testing.T) {
 				Email:        "test@example.com",
 			},
 			setupMocks: func() {
-				mockUserRepo.EXPECT().FindByEmail("test@example.com").Return(nil, repository.ErrNotFound)
-				mockUserRepo.EXPECT().Create(gomock.Any()).Return(nil)
+				mockUserRepo.EXPECT().FindByEmail(gomock.Any(), "test@example.com").Return(nil, repository.ErrNotFound)
+				mockUserRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
 				mockLogger.EXPECT().Info(gomock.Any(), gomock.Any())
 			},
 			expectedError: nil,
@@ -57,9 +62,9 @@ func TestUserUsecase_Register(t *testing.T) {
 				Email:        "existing@example.com",
 			},
 			setupMocks: func() {
-				mockUserRepo.EXPECT().FindByEmail("existing@example.com").Return(&domain.User{}, nil)
+				mockUserRepo.EXPECT().FindByEmail(gomock.Any(), "existing@example.com").Return(&domain.User{}, nil)
 			},
-			expectedError: errorx.New(errorx.CodeUserAlreadyExists, nil),
+			expectedError: errorx.New(usererr.CodeUserAlreadyExists, nil),
 		},
 		{
 			name: "Database error on FindByEmail",
@@ -70,18 +75,32 @@ func TestUserUsecase_Register(t *testing.T) {
 			},
 			setupMocks: func() {
 				dbError := errors.New("database connection failed")
-				mockUserRepo.EXPECT().FindByEmail("db@example.com").Return(nil, dbError)
+				mockUserRepo.EXPECT().FindByEmail(gomock.Any(), "db@example.com").Return(nil, dbError)
 				mockLogger.EXPECT().Error(gomock.Any(), gomock.Any())
 			},
 			expectedError: errorx.New(errorx.CodeInternalServerError, errors.New("database connection failed")),
 		},
+		{
+			name: "Query timeout on FindByEmail",
+			inputUser: &domain.User{
+				Username:     "slowuser",
+				PasswordHash: "password123",
+				Email:        "slow@example.com",
+			},
+			setupMocks: func() {
+				timeoutErr := fmt.Errorf("%w: user.find_by_email", repository.ErrTimeout)
+				mockUserRepo.EXPECT().FindByEmail(gomock.Any(), "slow@example.com").Return(nil, timeoutErr)
+				mockLogger.EXPECT().Error(gomock.Any(), gomock.Any())
+			},
+			expectedError: errorx.New(errorx.CodeTimeout, repository.ErrTimeout),
+		},
 	}
 
 	// Run test cases
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.setupMocks()
-			err := userUsecase.Register(tc.inputUser)
+			err := userUsecase.Register(context.Background(), tc.inputUser)
 
 			// Assertions
 			if tc.expectedError != nil {
@@ -97,3 +116,161 @@ func TestUserUsecase_Register(t *testing.T) {
 		})
 	}
 }
+
+func TestUserUsecase_Register_RejectsInvalidUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// No repository expectations: an invalid user never reaches the database.
+	mockUserRepo := mock_repo.NewMockUserRepository(ctrl)
+	mockLogger := mock_contracts.NewMockLogger(ctrl)
+	mockLogger.EXPECT().Warn(gomock.Any(), gomock.Any())
+	userUsecase := NewUserUsecase(mockUserRepo, mock_contracts.NewMockAuthService(ctrl), 15*time.Minute, mockLogger)
+
+	err := userUsecase.Register(context.Background(), &domain.User{PasswordHash: "password123", Email: "not-an-email"})
+
+	var detailErr *errorx.DetailError
+	require.ErrorAs(t, err, &detailErr)
+	assert.Equal(t, errorx.CodeInvalidParams, detailErr.Code)
+	var validationErr *domain.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	fields := make([]string, len(validationErr.Violations))
+	for i, v := range validationErr.Violations {
+		fields[i] = v.Field
+	}
+	assert.Equal(t, []string{"username", "email"}, fields)
+}
+
+func TestUserUsecase_Login(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_repo.NewMockUserRepository(ctrl)
+	mockAuthSvc := mock_contracts.NewMockAuthService(ctrl)
+	mockLogger := mock_contracts.NewMockLogger(ctrl)
+
+	userUsecase := NewUserUsecase(mockUserRepo, mockAuthSvc, 15*time.Minute, mockLogger)
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	assert.NoError(t, err)
+	existingUser := &domain.User{ID: 7, Email: "test@example.com", PasswordHash: string(hashed)}
+
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "request")
+
+	testCases := []struct {
+		name          string
+		password      string
+		setupMocks    func()
+		expectedToken string
+		expectedError error
+	}{
+		{
+			name:     "Success",
+			password: "password123",
+			setupMocks: func() {
+				// The request context reaches the repository.
+				mockUserRepo.EXPECT().FindByEmail(ctx, "test@example.com").Return(existingUser, nil)
+				mockAuthSvc.EXPECT().GenerateToken(int64(7)).Return("token", nil)
+				mockLogger.EXPECT().Info(gomock.Any(), gomock.Any(), gomock.Any())
+			},
+			expectedToken: "token",
+		},
+		{
+			name:     "Wrong Password",
+			password: "wrong",
+			setupMocks: func() {
+				mockUserRepo.EXPECT().FindByEmail(ctx, "test@example.com").Return(existingUser, nil)
+				mockLogger.EXPECT().Warn(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any())
+			},
+			expectedError: errorx.New(usererr.CodeInvalidCredentials, nil),
+		},
+		{
+			name:     "Query Timeout",
+			password: "password123",
+			setupMocks: func() {
+				mockUserRepo.EXPECT().FindByEmail(ctx, "test@example.com").Return(nil, repository.ErrTimeout)
+				mockLogger.EXPECT().Warn(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any())
+			},
+			expectedError: errorx.New(errorx.CodeTimeout, repository.ErrTimeout),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.setupMocks()
+			token, err := userUsecase.Login(ctx, "test@example.com", tc.password)
+
+			if tc.expectedError != nil {
+				var detailErr *errorx.DetailError
+				if assert.ErrorAs(t, err, &detailErr) {
+					assert.Equal(t, tc.expectedError.(*errorx.DetailError).Code, detailErr.Code)
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedToken, token)
+			}
+		})
+	}
+}
+
+func TestUserUsecase_GetUsersByIDs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_repo.NewMockUserRepository(ctrl)
+	mockLogger := mock_contracts.NewMockLogger(ctrl)
+	userUsecase := NewUserUsecase(mockUserRepo, mock_contracts.NewMockAuthService(ctrl), 15*time.Minute, mockLogger)
+	ctx := context.Background()
+
+	alice := &domain.User{ID: 1, Username: "alice"}
+	bob := &domain.User{ID: 2, Username: "bob"}
+
+	testCases := []struct {
+		name          string
+		ids           []int64
+		setupMocks    func()
+		expectedUsers map[int64]*domain.User
+		expectedCode  int
+	}{
+		{
+			name: "Duplicates Are Queried Once",
+			ids:  []int64{1, 2, 1, 3},
+			setupMocks: func() {
+				mockUserRepo.EXPECT().FindByIDs(ctx, []int64{1, 2, 3}).Return([]*domain.User{alice, bob}, nil)
+			},
+			expectedUsers: map[int64]*domain.User{1: alice, 2: bob},
+		},
+		{
+			name:          "No IDs",
+			setupMocks:    func() {},
+			expectedUsers: map[int64]*domain.User{},
+		},
+		{
+			name: "Query Timeout",
+			ids:  []int64{1},
+			setupMocks: func() {
+				mockUserRepo.EXPECT().FindByIDs(ctx, []int64{1}).Return(nil, repository.ErrTimeout)
+				mockLogger.EXPECT().Error(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any())
+			},
+			expectedCode: errorx.CodeTimeout,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.setupMocks()
+			users, err := userUsecase.GetUsersByIDs(ctx, tc.ids)
+
+			if tc.expectedCode != 0 {
+				var detailErr *errorx.DetailError
+				if assert.ErrorAs(t, err, &detailErr) {
+					assert.Equal(t, tc.expectedCode, detailErr.Code)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedUsers, users)
+		})
+	}
+}
@@ -0,0 +1,25 @@
+// Package usererr owns the error codes of the user bounded context (20xxx).
+package usererr
+
+import (
+	"net/http"
+
+	"go.uber.org/zap/zapcore"
+
+	"github.com/FormalYou/clean-architecture-blog/internal/errorx"
+)
+
+// User service error codes (20xxx).
+const (
+	CodeUserAlreadyExists  = 20001
+	CodeUserNotFound       = 20002
+	CodeInvalidCredentials = 20003
+)
+
+var codes = errorx.Reserve("user", 20000, 29999)
+
+func init() {
+	codes.Register(CodeUserAlreadyExists, "User already exists", http.StatusBadRequest, zapcore.WarnLevel)
+	codes.Register(CodeUserNotFound, "User not found", http.StatusNotFound, zapcore.WarnLevel)
+	codes.Register(CodeInvalidCredentials, "Invalid username or password", http.StatusUnauthorized, zapcore.WarnLevel)
+}
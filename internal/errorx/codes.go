@@ -1,42 +1,137 @@
 package errorx
 
 import (
+	"fmt"
 	"net/http"
+	"sort"
+	"sync"
 
 	"go.uber.org/zap/zapcore"
 )
 
-// Predefined error codes.
+//go:generate go run ../../cmd/errorsgen -spec ../../api/openapi.yaml
+
+// Common error codes (10xxx). Every bounded context reserves a range of
+// codes with Reserve and registers its own in an init function, see
+// usecase/usererr and usecase/articleerr.
 const (
-	// Common Errors (10xxx)
 	CodeSuccess             = 0
 	CodeInternalServerError = 10001
 	CodeInvalidParams       = 10002
 	CodeUnauthorized        = 10003
 	CodeNotFound            = 10004
+	CodeTimeout             = 10005
+	CodeTooManyRequests     = 10006
+	CodeRequestInProgress   = 10007
+	CodeIdempotencyMismatch = 10008
+	CodeMethodNotAllowed    = 10009
+)
 
-	// User Service Errors (20xxx)
-	CodeUserAlreadyExists  = 20001
-	CodeUserNotFound       = 20002
-	CodeInvalidCredentials = 20003
+// commonCodes are the codes shared by every bounded context; 0 is success.
+var commonCodes = Reserve("common", 0, 19999)
 
-	// Article Service Errors (30xxx)
-	CodeArticleNotFound = 30001
-)
+func init() {
+	commonCodes.Register(CodeSuccess, "Success", http.StatusOK, zapcore.InfoLevel)
+	commonCodes.Register(CodeInternalServerError, "Internal Server Error", http.StatusInternalServerError, zapcore.ErrorLevel)
+	commonCodes.Register(CodeInvalidParams, "Invalid Parameters", http.StatusBadRequest, zapcore.WarnLevel)
+	commonCodes.Register(CodeUnauthorized, "Unauthorized", http.StatusUnauthorized, zapcore.WarnLevel)
+	commonCodes.Register(CodeNotFound, "Resource Not Found", http.StatusNotFound, zapcore.WarnLevel)
+	commonCodes.Register(CodeTimeout, "Request Timeout", http.StatusGatewayTimeout, zapcore.ErrorLevel)
+	commonCodes.Register(CodeTooManyRequests, "Too Many Requests", http.StatusTooManyRequests, zapcore.WarnLevel)
+	commonCodes.Register(CodeRequestInProgress, "A request with this Idempotency-Key is still in progress", http.StatusConflict, zapcore.WarnLevel)
+	commonCodes.Register(CodeIdempotencyMismatch, "Idempotency-Key was already used for a different request", http.StatusConflict, zapcore.WarnLevel)
+	commonCodes.Register(CodeMethodNotAllowed, "Method Not Allowed", http.StatusMethodNotAllowed, zapcore.WarnLevel)
+}
 
-var codes = map[int]struct {
+// Definition is a registered error code: its default (English) message, the
+// HTTP status it is reported with and the level it is logged at.
+type Definition struct {
 	Code       int
 	Message    string
 	HTTPStatus int
 	LogLevel   zapcore.Level
-}{
-	CodeSuccess:             {CodeSuccess, "Success", http.StatusOK, zapcore.InfoLevel},
-	CodeInternalServerError: {CodeInternalServerError, "Internal Server Error", http.StatusInternalServerError, zapcore.ErrorLevel},
-	CodeInvalidParams:       {CodeInvalidParams, "Invalid Parameters", http.StatusBadRequest, zapcore.WarnLevel},
-	CodeUnauthorized:        {CodeUnauthorized, "Unauthorized", http.StatusUnauthorized, zapcore.WarnLevel},
-	CodeNotFound:            {CodeNotFound, "Resource Not Found", http.StatusNotFound, zapcore.WarnLevel},
-	CodeUserAlreadyExists:   {CodeUserAlreadyExists, "User already exists", http.StatusBadRequest, zapcore.WarnLevel},
-	CodeUserNotFound:        {CodeUserNotFound, "User not found", http.StatusNotFound, zapcore.WarnLevel},
-	CodeInvalidCredentials:  {CodeInvalidCredentials, "Invalid username or password", http.StatusUnauthorized, zapcore.WarnLevel},
-	CodeArticleNotFound:     {CodeArticleNotFound, "Article not found", http.StatusNotFound, zapcore.WarnLevel},
+}
+
+var (
+	codesMu sync.RWMutex
+	codes   = map[int]Definition{}
+	ranges  []*CodeRange
+)
+
+// CodeRange is a block of error codes reserved for one bounded context,
+// which registers its codes through it.
+type CodeRange struct {
+	Owner string
+	Min   int
+	Max   int
+}
+
+// Reserve claims the codes from min to max for owner. It is meant to
+// initialise a package variable of the owning package and panics when the
+// block overlaps one reserved before, so two bounded contexts cannot hand
+// out the same codes.
+func Reserve(owner string, min, max int) *CodeRange {
+	if min > max {
+		panic(fmt.Sprintf("errorx: %s reserved the empty range %d-%d", owner, min, max))
+	}
+
+	codesMu.Lock()
+	defer codesMu.Unlock()
+	for _, r := range ranges {
+		if min <= r.Max && r.Min <= max {
+			panic(fmt.Sprintf("errorx: %s range %d-%d overlaps %s range %d-%d", owner, min, max, r.Owner, r.Min, r.Max))
+		}
+	}
+	r := &CodeRange{Owner: owner, Min: min, Max: max}
+	ranges = append(ranges, r)
+	return r
+}
+
+// Contains reports whether code belongs to the range.
+func (r *CodeRange) Contains(code int) bool {
+	return r.Min <= code && code <= r.Max
+}
+
+// Register adds an error code of the range to the catalogue. It is meant to
+// be called from init functions and panics when the code lies outside the
+// range or is already taken, so two bounded contexts claiming the same
+// code fail at startup instead of one silently shadowing the other.
+func (r *CodeRange) Register(code int, message string, httpStatus int, level zapcore.Level) {
+	if !r.Contains(code) {
+		panic(fmt.Sprintf("errorx: code %d is outside the %s range %d-%d", code, r.Owner, r.Min, r.Max))
+	}
+	if message == "" {
+		panic(fmt.Sprintf("errorx: code %d registered without a message", code))
+	}
+	if http.StatusText(httpStatus) == "" {
+		panic(fmt.Sprintf("errorx: code %d registered with invalid HTTP status %d", code, httpStatus))
+	}
+
+	codesMu.Lock()
+	defer codesMu.Unlock()
+	if existing, ok := codes[code]; ok {
+		panic(fmt.Sprintf("errorx: code %d registered twice (%q and %q)", code, existing.Message, message))
+	}
+	codes[code] = Definition{Code: code, Message: message, HTTPStatus: httpStatus, LogLevel: level}
+}
+
+// Lookup returns the definition of a registered code.
+func Lookup(code int) (Definition, bool) {
+	codesMu.RLock()
+	defer codesMu.RUnlock()
+	def, ok := codes[code]
+	return def, ok
+}
+
+// Definitions lists every registered code in ascending order.
+func Definitions() []Definition {
+	codesMu.RLock()
+	defs := make([]Definition, 0, len(codes))
+	for _, def := range codes {
+		defs = append(defs, def)
+	}
+	codesMu.RUnlock()
+
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Code < defs[j].Code })
+	return defs
 }
@@ -0,0 +1,86 @@
+package errorx
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestRegister_Rejects(t *testing.T) {
+	testCases := []struct {
+		name    string
+		code    int
+		message string
+		status  int
+		panics  string
+	}{
+		{"Duplicate code", CodeNotFound, "Page not found", http.StatusNotFound, `errorx: code 10004 registered twice ("Resource Not Found" and "Page not found")`},
+		{"Outside the range", 20001, "Borrowed", http.StatusBadRequest, "errorx: code 20001 is outside the common range 0-19999"},
+		{"Missing message", 19001, "", http.StatusBadRequest, "errorx: code 19001 registered without a message"},
+		{"Invalid status", 19002, "Broken", 999, "errorx: code 19002 registered with invalid HTTP status 999"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.PanicsWithValue(t, tc.panics, func() {
+				commonCodes.Register(tc.code, tc.message, tc.status, zapcore.WarnLevel)
+			})
+		})
+	}
+
+	_, ok := Lookup(19001)
+	assert.False(t, ok, "a rejected code must not be registered")
+	def, _ := Lookup(CodeNotFound)
+	assert.Equal(t, "Resource Not Found", def.Message)
+}
+
+func TestReserve_RejectsOverlaps(t *testing.T) {
+	assert.PanicsWithValue(t, "errorx: billing range 19000-20999 overlaps common range 0-19999", func() {
+		Reserve("billing", 19000, 20999)
+	})
+	assert.PanicsWithValue(t, "errorx: billing reserved the empty range 5-4", func() {
+		Reserve("billing", 5, 4)
+	})
+
+	billing := Reserve("billing", 90000, 90999)
+	assert.True(t, billing.Contains(90000))
+	assert.False(t, billing.Contains(91000))
+	assert.Panics(t, func() { Reserve("invoicing", 90500, 90500) }, "a range is reserved once")
+}
+
+func TestDefinitions_Sorted(t *testing.T) {
+	defs := Definitions()
+
+	assert.Len(t, defs, len(codes))
+	for i := 1; i < len(defs); i++ {
+		assert.Less(t, defs[i-1].Code, defs[i].Code)
+	}
+}
+
+func TestNew_UnregisteredCode(t *testing.T) {
+	cause := errors.New("boom")
+
+	err := New(99999, cause)
+
+	assert.Equal(t, CodeInternalServerError, err.Code)
+	assert.Equal(t, http.StatusInternalServerError, err.HTTPStatus)
+	assert.EqualError(t, err, "errorx: unregistered code 99999: boom")
+	assert.ErrorIs(t, err, cause)
+}
+
+func TestDescribe(t *testing.T) {
+	entry, ok := Describe(CodeTooManyRequests)
+
+	assert.True(t, ok)
+	assert.Equal(t, ProblemTypeBase+"10006", entry.Type)
+	assert.Equal(t, http.StatusTooManyRequests, entry.HTTPStatus)
+	assert.Equal(t, "Too Many Requests", entry.Title)
+	assert.Equal(t, "Too Many Requests, retry in {{.retry_after}} seconds", entry.Messages[DefaultLocale])
+	assert.Equal(t, "请求过于频繁，请在 {{.retry_after}} 秒后重试", entry.Messages["zh"])
+
+	_, ok = Describe(99999)
+	assert.False(t, ok)
+}
@@ -3,22 +3,42 @@ package errorx
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 
 	"go.uber.org/zap/zapcore"
+
+	"github.com/FormalYou/clean-architecture-blog/domain"
 )
 
 // BusinessError defines the user-facing part of an error.
 type BusinessError struct {
-	Code    int    `json:"code"`
+	Code    int      `json:"code"`
+	Message string   `json:"message"`
+	Details []Detail `json:"details,omitempty"`
+}
+
+// Detail describes one specific problem behind an error, such as a request
+// field that failed validation. Field is a dotted path into the request body
+// (e.g. "tags[0]") and Reason a machine-readable rule name.
+type Detail struct {
+	Field   string `json:"field"`
+	Reason  string `json:"reason"`
 	Message string `json:"message"`
 }
 
+// ReasonInvalidToken is the Detail reason of a bearer token that is
+// malformed, expired or not signed by this service.
+const ReasonInvalidToken = "invalid_token"
+
 // DetailError is the full error object used internally.
 type DetailError struct {
 	BusinessError
 	HTTPStatus int
 	LogLevel   zapcore.Level
 	Err        error // The original underlying error
+
+	params map[string]string // template parameters of localized messages
+	locale string            // set by Localize
 }
 
 func (e *DetailError) Error() string {
@@ -37,24 +57,29 @@ func (be *BusinessError) ToJSON() string {
 }
 
 // New creates a new DetailError.
-// It looks up the code in the predefined map to get the message and HTTP status.
+// It looks up the registered code to get the message and HTTP status. An
+// unregistered code is reported as an internal server error whose underlying
+// error names the code, so the mistake shows up in the logs.
 func New(code int, underlyingErr error) *DetailError {
-	be, ok := codes[code]
+	be, ok := Lookup(code)
 	if !ok {
-		// Fallback for undefined codes
-		be = codes[CodeInternalServerError]
+		be, _ = Lookup(CodeInternalServerError)
 	}
 
-	// If no specific underlying error is provided, use the message from the map.
+	// If no specific underlying error is provided, use the registered message.
 	err := underlyingErr
 	if err == nil {
 		err = errors.New(be.Message)
 	}
+	if !ok {
+		err = fmt.Errorf("errorx: unregistered code %d: %w", code, err)
+	}
 
 	return &DetailError{
 		BusinessError: BusinessError{
 			Code:    be.Code,
 			Message: be.Message,
+			Details: validationDetails(underlyingErr),
 		},
 		HTTPStatus: be.HTTPStatus,
 		LogLevel:   be.LogLevel,
@@ -62,7 +87,36 @@ func New(code int, underlyingErr error) *DetailError {
 	}
 }
 
+// WithDetails appends details describing the error.
+func (e *DetailError) WithDetails(details ...Detail) *DetailError {
+	e.BusinessError.Details = append(e.BusinessError.Details, details...)
+	return e
+}
+
+// validationDetails lists the violations of a domain validation error, so
+// every failed rule reaches the client rather than only the first.
+func validationDetails(err error) []Detail {
+	var verr *domain.ValidationError
+	if !errors.As(err, &verr) {
+		return nil
+	}
+	details := make([]Detail, len(verr.Violations))
+	for i, v := range verr.Violations {
+		details[i] = Detail{Field: v.Field, Reason: v.Reason, Message: v.Message}
+	}
+	return details
+}
+
+// WithParams sets the parameters that localized message templates refer to,
+// e.g. {{.method}}. The default English message does not use them.
+func (e *DetailError) WithParams(params map[string]string) *DetailError {
+	e.params = params
+	return e
+}
+
 // WithMessage allows overriding the default message for a given error code.
+// The override is never translated, so client-facing specifics belong in
+// details or template parameters instead.
 func (e *DetailError) WithMessage(message string) *DetailError {
 	e.BusinessError.Message = message
 	return e
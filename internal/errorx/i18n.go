@@ -0,0 +1,197 @@
+package errorx
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"golang.org/x/text/language"
+)
+
+// DefaultLocale is the language of the messages passed to Register and the
+// last locale of every fallback chain.
+const DefaultLocale = "en"
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// catalogue holds one locale's message templates.
+type catalogue struct {
+	codes   map[int]*template.Template
+	reasons map[string]*template.Template
+}
+
+// catalogueFile is the layout of locales/<locale>.json. Messages are
+// text/template templates over the error's parameters; a detail's reason
+// template also receives its field as "field". The DefaultLocale file only
+// needs codes: English details keep the specific message they were raised
+// with.
+type catalogueFile struct {
+	Codes   map[string]string `json:"codes"`
+	Reasons map[string]string `json:"reasons"`
+}
+
+var (
+	catalogues = mustLoadCatalogues()
+	matcher    = language.NewMatcher(supportedTags())
+)
+
+func mustLoadCatalogues() map[string]*catalogue {
+	files, err := localeFiles.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+	loaded := make(map[string]*catalogue, len(files))
+	for _, f := range files {
+		locale := strings.TrimSuffix(f.Name(), path.Ext(f.Name()))
+		cat, err := loadCatalogue(path.Join("locales", f.Name()))
+		if err != nil {
+			panic(fmt.Sprintf("errorx: locale %s: %v", locale, err))
+		}
+		loaded[locale] = cat
+	}
+	return loaded
+}
+
+func loadCatalogue(name string) (*catalogue, error) {
+	raw, err := localeFiles.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	var file catalogueFile
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return nil, err
+	}
+	cat := &catalogue{codes: map[int]*template.Template{}, reasons: map[string]*template.Template{}}
+	for key, text := range file.Codes {
+		code, err := strconv.Atoi(key)
+		if err != nil {
+			return nil, fmt.Errorf("code %q is not a number", key)
+		}
+		if cat.codes[code], err = parseMessage(key, text); err != nil {
+			return nil, err
+		}
+	}
+	for reason, text := range file.Reasons {
+		if cat.reasons[reason], err = parseMessage(reason, text); err != nil {
+			return nil, err
+		}
+	}
+	return cat, nil
+}
+
+// parseMessage parses a message template that fails on missing parameters,
+// so the lookup can fall back to a locale that does not need them.
+func parseMessage(name, text string) (*template.Template, error) {
+	return template.New(name).Option("missingkey=error").Parse(text)
+}
+
+// Locales lists the supported locales, the default first.
+func Locales() []string {
+	locales := []string{DefaultLocale}
+	for locale := range catalogues {
+		if locale != DefaultLocale {
+			locales = append(locales, locale)
+		}
+	}
+	sort.Strings(locales[1:])
+	return locales
+}
+
+func supportedTags() []language.Tag {
+	var tags []language.Tag
+	for _, locale := range Locales() {
+		tags = append(tags, language.MustParse(locale))
+	}
+	return tags
+}
+
+// NegotiateLocale picks the supported locale that best matches an
+// Accept-Language header, or DefaultLocale.
+func NegotiateLocale(acceptLanguage string) string {
+	_, index := language.MatchStrings(matcher, acceptLanguage)
+	return Locales()[index]
+}
+
+// fallbackChain lists the locales to try for locale, from the most specific
+// to DefaultLocale, e.g. zh-TW, zh, en.
+func fallbackChain(locale string) []string {
+	var chain []string
+	for locale != "" && locale != DefaultLocale {
+		if _, ok := catalogues[locale]; ok {
+			chain = append(chain, locale)
+		}
+		i := strings.LastIndex(locale, "-")
+		if i < 0 {
+			break
+		}
+		locale = locale[:i]
+	}
+	if _, ok := catalogues[DefaultLocale]; ok {
+		chain = append(chain, DefaultLocale)
+	}
+	return chain
+}
+
+// localize renders the first template found along locale's fallback chain
+// that has every parameter it needs, and returns ok false when the default
+// message should be used.
+func localize(locale string, pick func(*catalogue) *template.Template, params map[string]string) (string, bool) {
+	for _, l := range fallbackChain(locale) {
+		tmpl := pick(catalogues[l])
+		if tmpl == nil {
+			continue
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, params); err == nil {
+			return buf.String(), true
+		}
+	}
+	return "", false
+}
+
+// title returns the standard message of code in locale.
+func title(code int, locale string, params map[string]string) string {
+	if msg, ok := localize(locale, func(c *catalogue) *template.Template { return c.codes[code] }, params); ok {
+		return msg
+	}
+	return registeredMessage(code)
+}
+
+// registeredMessage is the message code was registered with, which every
+// new error starts out with.
+func registeredMessage(code int) string {
+	if def, ok := Lookup(code); ok {
+		return def.Message
+	}
+	def, _ := Lookup(CodeInternalServerError)
+	return def.Message
+}
+
+// Localize returns a copy of the error with its message and details
+// translated into locale. A message replaced with WithMessage is kept as is,
+// as are details whose reason has no translation.
+func (e *DetailError) Localize(locale string) *DetailError {
+	localized := *e
+	localized.locale = locale
+	if e.Message == registeredMessage(e.Code) {
+		localized.Message = title(e.Code, locale, e.params)
+	}
+	if len(e.Details) > 0 {
+		localized.Details = make([]Detail, len(e.Details))
+		for i, d := range e.Details {
+			params := map[string]string{"field": d.Field}
+			if msg, ok := localize(locale, func(c *catalogue) *template.Template { return c.reasons[d.Reason] }, params); ok {
+				d.Message = msg
+			}
+			localized.Details[i] = d
+		}
+	}
+	return &localized
+}
@@ -0,0 +1,160 @@
+package errorx
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FormalYou/clean-architecture-blog/domain"
+)
+
+// templateParams are every parameter a message template may use.
+var templateParams = map[string]string{"field": "title", "method": "PATCH", "retry_after": "30"}
+
+func TestCatalogues_TranslateEveryCode(t *testing.T) {
+	require.Contains(t, catalogues, DefaultLocale)
+
+	for locale, cat := range catalogues {
+		t.Run(locale, func(t *testing.T) {
+			for code := range codes {
+				tmpl, ok := cat.codes[code]
+				if assert.True(t, ok, "code %d has no %s translation", code, locale) {
+					assert.NoError(t, tmpl.Execute(new(bytes.Buffer), templateParams), "code %d", code)
+				}
+			}
+			// Codes of other bounded contexts are registered by their own
+			// packages, see cmd/errorsgen for the check across all of them.
+			for code := range cat.codes {
+				if commonCodes.Contains(code) {
+					_, ok := codes[code]
+					assert.True(t, ok, "%s translates unknown code %d", locale, code)
+				}
+			}
+
+			if locale == DefaultLocale {
+				assert.Empty(t, cat.reasons, "English details keep their own messages")
+				return
+			}
+			for _, reason := range []string{domain.ReasonRequired, domain.ReasonInvalidFormat, "invalid_type", ReasonInvalidToken} {
+				tmpl, ok := cat.reasons[reason]
+				if assert.True(t, ok, "reason %s has no %s translation", reason, locale) {
+					assert.NoError(t, tmpl.Execute(new(bytes.Buffer), templateParams), "reason %s", reason)
+				}
+			}
+		})
+	}
+}
+
+func TestLocales(t *testing.T) {
+	locales := Locales()
+	assert.Equal(t, DefaultLocale, locales[0])
+	assert.True(t, sort.StringsAreSorted(locales[1:]))
+	assert.Len(t, locales, len(catalogues))
+}
+
+func TestNegotiateLocale(t *testing.T) {
+	testCases := map[string]string{
+		"":                        DefaultLocale,
+		"fr-FR":                   DefaultLocale,
+		"en-US,en;q=0.9":          DefaultLocale,
+		"zh-CN,zh;q=0.9,en;q=0.8": "zh",
+		"zh":                      "zh",
+		"zh-TW":                   "zh-TW",
+		"fr;q=0.9, zh;q=0.5":      "zh",
+		"not a language tag":      DefaultLocale,
+	}
+	for header, expected := range testCases {
+		assert.Equal(t, expected, NegotiateLocale(header), "Accept-Language: %q", header)
+	}
+}
+
+func TestFallbackChain(t *testing.T) {
+	assert.Equal(t, []string{"zh-TW", "zh", DefaultLocale}, fallbackChain("zh-TW"))
+	assert.Equal(t, []string{"zh", DefaultLocale}, fallbackChain("zh-SG"))
+	assert.Equal(t, []string{DefaultLocale}, fallbackChain(DefaultLocale))
+}
+
+func TestDetailError_Localize(t *testing.T) {
+	testCases := []struct {
+		name            string
+		err             *DetailError
+		locale          string
+		expectedMessage string
+		expectedDetails []Detail
+	}{
+		{
+			name:            "Default Locale",
+			err:             New(CodeNotFound, nil),
+			locale:          DefaultLocale,
+			expectedMessage: "Resource Not Found",
+		},
+		{
+			name:            "Translated",
+			err:             New(CodeNotFound, nil),
+			locale:          "zh",
+			expectedMessage: "资源不存在",
+		},
+		{
+			name:            "Template Parameters",
+			err:             New(CodeTooManyRequests, nil).WithParams(map[string]string{"retry_after": "12"}),
+			locale:          "zh-TW",
+			expectedMessage: "請求過於頻繁，請在 12 秒後重試",
+		},
+		{
+			name:            "English Template Parameters",
+			err:             New(CodeTooManyRequests, nil).WithParams(map[string]string{"retry_after": "12"}),
+			locale:          DefaultLocale,
+			expectedMessage: "Too Many Requests, retry in 12 seconds",
+		},
+		{
+			name:            "Missing Parameter Falls Back To Default",
+			err:             New(CodeTooManyRequests, nil),
+			locale:          "zh",
+			expectedMessage: "Too Many Requests",
+		},
+		{
+			name:            "Custom Message Kept",
+			err:             New(CodeUnauthorized, nil).WithMessage("invalid token"),
+			locale:          "zh",
+			expectedMessage: "invalid token",
+		},
+		{
+			name:            "Details",
+			err:             New(CodeInvalidParams, (&domain.Article{Title: "t", AuthorID: 1}).Validate()).WithDetails(Detail{Field: "tags", Reason: "max", Message: "tags failed the \"max\" rule"}),
+			locale:          "zh",
+			expectedMessage: "请求参数无效",
+			expectedDetails: []Detail{
+				{Field: "content", Reason: domain.ReasonRequired, Message: "content 为必填项"},
+				{Field: "tags", Reason: "max", Message: "tags failed the \"max\" rule"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			original := *tc.err
+			localized := tc.err.Localize(tc.locale)
+
+			assert.Equal(t, tc.expectedMessage, localized.Message)
+			assert.Equal(t, tc.expectedDetails, localized.Details)
+			assert.Equal(t, original.BusinessError, tc.err.BusinessError, "the original is not modified")
+		})
+	}
+}
+
+func TestDetailError_LocalizedProblem(t *testing.T) {
+	err := New(CodeMethodNotAllowed, errors.New("method PATCH is not allowed for /x")).
+		WithParams(map[string]string{"method": "PATCH"}).
+		Localize("zh")
+
+	p := err.Problem("/x", "req-1")
+
+	assert.Equal(t, "不支持 PATCH 方法", p.Title)
+	assert.Equal(t, "method PATCH is not allowed for /x", p.Detail)
+	assert.Equal(t, ProblemTypeBase+strconv.Itoa(CodeMethodNotAllowed), p.Type)
+}
@@ -0,0 +1,49 @@
+package errorx
+
+import "strconv"
+
+// Entry describes a registered code in the error catalogue served under
+// ProblemTypeBase. Messages maps every locale that translates the code to its
+// message template, whose parameters are filled in when an error is reported.
+type Entry struct {
+	Code       int               `json:"code"`
+	Type       string            `json:"type"`
+	Title      string            `json:"title"`
+	HTTPStatus int               `json:"http_status"`
+	Messages   map[string]string `json:"messages"`
+}
+
+// Catalogue lists every registered code in ascending order.
+func Catalogue() []Entry {
+	defs := Definitions()
+	entries := make([]Entry, len(defs))
+	for i, def := range defs {
+		entries[i] = entry(def)
+	}
+	return entries
+}
+
+// Describe returns the catalogue entry of a registered code.
+func Describe(code int) (Entry, bool) {
+	def, ok := Lookup(code)
+	if !ok {
+		return Entry{}, false
+	}
+	return entry(def), true
+}
+
+func entry(def Definition) Entry {
+	messages := map[string]string{DefaultLocale: def.Message}
+	for locale, cat := range catalogues {
+		if tmpl, ok := cat.codes[def.Code]; ok {
+			messages[locale] = tmpl.Root.String()
+		}
+	}
+	return Entry{
+		Code:       def.Code,
+		Type:       ProblemTypeBase + strconv.Itoa(def.Code),
+		Title:      def.Message,
+		HTTPStatus: def.HTTPStatus,
+		Messages:   messages,
+	}
+}
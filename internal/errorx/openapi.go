@@ -0,0 +1,140 @@
+package errorx
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// The generated error schemas sit between these lines of the OpenAPI
+// document's components.schemas.
+const (
+	openAPIBegin = "    # BEGIN errorx schemas: generated by `go generate ./internal/errorx`, do not edit.\n"
+	openAPIEnd   = "    # END errorx schemas\n"
+)
+
+// openAPISchemas are the error types documented in the OpenAPI document.
+var openAPISchemas = []struct {
+	name        string
+	description string
+	typ         reflect.Type
+}{
+	{"Error", "Error response body.", reflect.TypeOf(BusinessError{})},
+	{"ErrorDetail", "One specific problem behind an error, such as a request field that failed validation.", reflect.TypeOf(Detail{})},
+	{"Problem", "RFC 7807 problem document, returned instead of Error when the request accepts " + ProblemContentType + ".", reflect.TypeOf(Problem{})},
+	{"ErrorCatalogueEntry", "A registered error code.", reflect.TypeOf(Entry{})},
+}
+
+// OpenAPISchemas renders the components.schemas entries describing the error
+// responses and every registered code.
+func OpenAPISchemas() string {
+	var b strings.Builder
+	w := func(indent int, format string, args ...interface{}) {
+		b.WriteString(strings.Repeat(" ", indent))
+		fmt.Fprintf(&b, format, args...)
+		b.WriteByte('\n')
+	}
+
+	defs := Definitions()
+	w(4, "ErrorCode:")
+	w(6, "type: integer")
+	w(6, "description: |-")
+	w(8, "Registered error codes, with the HTTP status they are reported with.")
+	for _, def := range defs {
+		w(8, "* `%d` (%d) %s", def.Code, def.HTTPStatus, def.Message)
+	}
+	w(6, "enum:")
+	for _, def := range defs {
+		w(8, "- %d", def.Code)
+	}
+
+	names := map[reflect.Type]string{}
+	for _, s := range openAPISchemas {
+		names[s.typ] = s.name
+	}
+	for _, s := range openAPISchemas {
+		w(4, "%s:", s.name)
+		w(6, "type: object")
+		w(6, "description: %s", yamlString(s.description))
+		var required []string
+		w(6, "properties:")
+		for i := 0; i < s.typ.NumField(); i++ {
+			name, omitempty, ok := jsonField(s.typ.Field(i))
+			if !ok {
+				continue
+			}
+			if !omitempty {
+				required = append(required, name)
+			}
+			w(8, "%s:", name)
+			writeSchema(w, 10, name, s.typ.Field(i).Type, names)
+		}
+		if len(required) > 0 {
+			w(6, "required: [%s]", strings.Join(required, ", "))
+		}
+	}
+	return b.String()
+}
+
+// jsonField returns a struct field's JSON name and whether it is omitted when
+// empty.
+func jsonField(f reflect.StructField) (name string, omitempty bool, ok bool) {
+	if !f.IsExported() {
+		return "", false, false
+	}
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", false, false
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = f.Name
+	}
+	return name, opts == "omitempty", true
+}
+
+func writeSchema(w func(int, string, ...interface{}), indent int, name string, t reflect.Type, names map[reflect.Type]string) {
+	switch {
+	case name == "code" && t.Kind() == reflect.Int:
+		w(indent, "$ref: '#/components/schemas/ErrorCode'")
+	case names[t] != "":
+		w(indent, "$ref: '#/components/schemas/%s'", names[t])
+	case t.Kind() == reflect.Int:
+		w(indent, "type: integer")
+	case t.Kind() == reflect.String:
+		w(indent, "type: string")
+	case t.Kind() == reflect.Slice:
+		w(indent, "type: array")
+		w(indent, "items:")
+		writeSchema(w, indent+2, "", t.Elem(), names)
+	case t.Kind() == reflect.Map:
+		w(indent, "type: object")
+		w(indent, "additionalProperties:")
+		writeSchema(w, indent+2, "", t.Elem(), names)
+	default:
+		panic(fmt.Sprintf("errorx: no OpenAPI schema for %s", t))
+	}
+}
+
+// yamlString quotes s as a single-quoted YAML scalar.
+func yamlString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// SyncOpenAPI replaces the generated error schemas of an OpenAPI document
+// with OpenAPISchemas. The document must contain the begin and end marker
+// lines inside components.schemas.
+func SyncOpenAPI(spec []byte) ([]byte, error) {
+	begin := bytes.Index(spec, []byte(openAPIBegin))
+	end := bytes.Index(spec, []byte(openAPIEnd))
+	if begin < 0 || end < begin {
+		return nil, errors.New("errorx: OpenAPI document lacks the generated error schema markers")
+	}
+	var out bytes.Buffer
+	out.Write(spec[:begin+len(openAPIBegin)])
+	out.WriteString(OpenAPISchemas())
+	out.Write(spec[end:])
+	return out.Bytes(), nil
+}
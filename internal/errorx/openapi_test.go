@@ -0,0 +1,21 @@
+package errorx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncOpenAPI(t *testing.T) {
+	spec := "components:\n  schemas:\n" + openAPIBegin + "    Stale:\n      type: string\n" + openAPIEnd + "# trailer\n"
+
+	synced, err := SyncOpenAPI([]byte(spec))
+
+	require.NoError(t, err)
+	assert.Equal(t, "components:\n  schemas:\n"+openAPIBegin+OpenAPISchemas()+openAPIEnd+"# trailer\n", string(synced))
+	assert.Contains(t, OpenAPISchemas(), "        - 10004\n")
+
+	_, err = SyncOpenAPI([]byte("components:\n  schemas:\n"))
+	assert.Error(t, err)
+}
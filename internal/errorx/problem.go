@@ -0,0 +1,51 @@
+package errorx
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// ProblemContentType is the media type of RFC 7807 problem documents.
+const ProblemContentType = "application/problem+json"
+
+// ProblemTypeBase prefixes the error code to form a problem's type URI.
+const ProblemTypeBase = "/api/v1/meta/errors/"
+
+// Problem is an RFC 7807 problem document. Code and Details carry the same
+// information as BusinessError for clients that understand both formats.
+type Problem struct {
+	Type      string   `json:"type"`
+	Title     string   `json:"title"`
+	Status    int      `json:"status"`
+	Detail    string   `json:"detail,omitempty"`
+	Instance  string   `json:"instance,omitempty"`
+	RequestID string   `json:"request_id,omitempty"`
+	Code      int      `json:"code"`
+	Details   []Detail `json:"details,omitempty"`
+}
+
+// Problem renders the error as a problem document about instance, the
+// request path. The title is the code's standard message. The detail is the
+// message set with WithMessage or, for client errors only, the underlying
+// error; server errors never expose their cause.
+func (e *DetailError) Problem(instance, requestID string) Problem {
+	std := title(e.Code, e.locale, e.params)
+	p := Problem{
+		Type:      ProblemTypeBase + strconv.Itoa(e.Code),
+		Title:     std,
+		Status:    e.HTTPStatus,
+		Instance:  instance,
+		RequestID: requestID,
+		Code:      e.Code,
+		Details:   e.Details,
+	}
+	switch {
+	case e.Message != std:
+		p.Detail = e.Message
+	case e.HTTPStatus < http.StatusInternalServerError && e.Err != nil:
+		p.Detail = e.Err.Error()
+	default:
+		p.Detail = std
+	}
+	return p
+}
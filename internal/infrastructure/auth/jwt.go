@@ -4,10 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/FormalYou/clean-architecture-blog/internal/application/contracts"
-	"github.com/FormalYou/clean-architecture-blog/internal/interfaces/http/dto"
+	"github.com/FormalYou/clean-architecture-blog/internal/interfaces/shared"
 	"github.com/golang-jwt/jwt/v5"
 )
 
@@ -35,6 +36,29 @@ func (s *JWTAuthService) GenerateToken(userID int64) (string, error) {
 
 // ValidateToken validates a JWT string and returns the user ID.
 func (s *JWTAuthService) ValidateToken(tokenString string) (int64, error) {
+	claims, err := s.parse(tokenString)
+	if err != nil {
+		return 0, err
+	}
+	if sub, ok := claims["sub"].(float64); ok {
+		return int64(sub), nil
+	}
+	return 0, errors.New("invalid token")
+}
+
+// TokenScopes validates a JWT string and returns the scopes listed in its
+// space-separated "scope" claim. Tokens issued by GenerateToken carry none.
+func (s *JWTAuthService) TokenScopes(tokenString string) ([]string, error) {
+	claims, err := s.parse(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	scope, _ := claims["scope"].(string)
+	return strings.Fields(scope), nil
+}
+
+// parse verifies the token's signature and expiry and returns its claims.
+func (s *JWTAuthService) parse(tokenString string) (jwt.MapClaims, error) {
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
@@ -43,21 +67,19 @@ func (s *JWTAuthService) ValidateToken(tokenString string) (int64, error) {
 	})
 
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
 	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		if sub, ok := claims["sub"].(float64); ok {
-			return int64(sub), nil
-		}
+		return claims, nil
 	}
 
-	return 0, errors.New("invalid token")
+	return nil, errors.New("invalid token")
 }
 
 // GetUserIDFromContext extracts user ID from a context.
 func (s *JWTAuthService) GetUserIDFromContext(ctx context.Context) (int64, error) {
-	userID, ok := ctx.Value(dto.UserIDKey).(int64)
+	userID, ok := shared.UserIDFrom(ctx)
 	if !ok {
 		return 0, errors.New("invalid user ID in context")
 	}
@@ -11,6 +11,68 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
+// listGenerationKey holds the current generation of cached article lists.
+// List keys embed the generation, so bumping it orphans every list at once
+// and the old keys age out through their TTL.
+const listGenerationKey = "articles:lists:generation"
+
+// articleKey is where a single article entry lives. The v2 prefix separates
+// ArticleCacheEntry values from the bare articles stored under article:<id>
+// by earlier releases, so instances of both releases running side by side
+// never decode each other's values.
+func articleKey(id uint) string {
+	return fmt.Sprintf("article:v2:%d", id)
+}
+
+// legacyArticleKey is the key earlier releases cache articles under.
+func legacyArticleKey(id uint) string {
+	return fmt.Sprintf("article:%d", id)
+}
+
+// generationKey holds the invalidation generation of an article.
+func generationKey(id uint) string {
+	return fmt.Sprintf("article:v2:%d:gen", id)
+}
+
+// generationTTL keeps a generation well past any load that may still hold
+// the previous one. Once it expires the generation restarts from zero, which
+// only makes loads that read a higher one fail to store.
+const generationTTL = 24 * time.Hour
+
+// setArticleScript stores the entry only while the article's generation is
+// still the one the entry was loaded at.
+var setArticleScript = redis.NewScript(`
+local gen = tonumber(redis.call("GET", KEYS[2]) or "0")
+if gen ~= tonumber(ARGV[2]) then
+	return 0
+end
+if tonumber(ARGV[3]) > 0 then
+	redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[3])
+else
+	redis.call("SET", KEYS[1], ARGV[1])
+end
+return 1`)
+
+// setArticlesScript stores a list only while the list generation is still
+// the one the list was loaded at. KEYS[2] embeds that generation.
+var setArticlesScript = redis.NewScript(`
+local gen = tonumber(redis.call("GET", KEYS[1]) or "0")
+if gen ~= tonumber(ARGV[2]) then
+	return 0
+end
+if tonumber(ARGV[3]) > 0 then
+	redis.call("SET", KEYS[2], ARGV[1], "PX", ARGV[3])
+else
+	redis.call("SET", KEYS[2], ARGV[1])
+end
+return 1`)
+
+// deleteArticleScript advances the generation and drops the entry.
+var deleteArticleScript = redis.NewScript(`
+redis.call("INCR", KEYS[1])
+redis.call("PEXPIRE", KEYS[1], ARGV[1])
+return redis.call("DEL", KEYS[2], KEYS[3])`)
+
 type articleCacheRepository struct {
 	redisClient *redis.Client
 }
@@ -19,33 +81,52 @@ func NewArticleCacheRepository(redisClient *redis.Client) repository.ArticleCach
 	return &articleCacheRepository{redisClient: redisClient}
 }
 
-func (r *articleCacheRepository) GetArticle(ctx context.Context, id uint) (*domain.Article, error) {
-	key := fmt.Sprintf("article:%d", id)
-	val, err := r.redisClient.Get(ctx, key).Result()
+func (r *articleCacheRepository) GetArticle(ctx context.Context, id uint) (*repository.ArticleCacheEntry, error) {
+	val, err := r.redisClient.Get(ctx, articleKey(id)).Result()
 	if err == redis.Nil {
 		return nil, nil // Cache miss
 	} else if err != nil {
 		return nil, err
 	}
 
-	var article domain.Article
-	err = json.Unmarshal([]byte(val), &article)
-	if err != nil {
-		return nil, err
+	// An undecodable value, or one without a fresh period, was not written
+	// by SetArticle; it is a miss and the next load overwrites it.
+	var entry repository.ArticleCacheEntry
+	if err := json.Unmarshal([]byte(val), &entry); err != nil || entry.FreshUntil.IsZero() {
+		return nil, nil
 	}
-	return &article, nil
+	return &entry, nil
 }
 
-func (r *articleCacheRepository) SetArticle(ctx context.Context, article *domain.Article, expiration time.Duration) error {
-	key := fmt.Sprintf("article:%d", article.ID)
-	val, err := json.Marshal(article)
+func (r *articleCacheRepository) SetArticle(ctx context.Context, id uint, entry *repository.ArticleCacheEntry, expiration time.Duration) error {
+	val, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	keys := []string{articleKey(id), generationKey(id)}
+	stored, err := setArticleScript.Run(ctx, r.redisClient, keys, val, entry.Generation, expiration.Milliseconds()).Int()
 	if err != nil {
 		return err
 	}
-	return r.redisClient.Set(ctx, key, val, expiration).Err()
+	if stored == 0 {
+		return repository.ErrStaleCacheEntry
+	}
+	return nil
+}
+
+func (r *articleCacheRepository) ArticleGeneration(ctx context.Context, id uint) (int64, error) {
+	gen, err := r.redisClient.Get(ctx, generationKey(id)).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return gen, err
 }
 
 func (r *articleCacheRepository) GetArticles(ctx context.Context, key string) ([]*domain.Article, error) {
+	key, err := r.listKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
 	val, err := r.redisClient.Get(ctx, key).Result()
 	if err == redis.Nil {
 		return nil, nil // Cache miss
@@ -61,15 +142,51 @@ func (r *articleCacheRepository) GetArticles(ctx context.Context, key string) ([
 	return articles, nil
 }
 
-func (r *articleCacheRepository) SetArticles(ctx context.Context, key string, articles []*domain.Article, expiration time.Duration) error {
+func (r *articleCacheRepository) SetArticles(ctx context.Context, key string, articles []*domain.Article, generation int64, expiration time.Duration) error {
 	val, err := json.Marshal(articles)
 	if err != nil {
 		return err
 	}
-	return r.redisClient.Set(ctx, key, val, expiration).Err()
+	keys := []string{listGenerationKey, listKeyAt(generation, key)}
+	stored, err := setArticlesScript.Run(ctx, r.redisClient, keys, val, generation, expiration.Milliseconds()).Int()
+	if err != nil {
+		return err
+	}
+	if stored == 0 {
+		return repository.ErrStaleCacheEntry
+	}
+	return nil
+}
+
+func (r *articleCacheRepository) ListGeneration(ctx context.Context) (int64, error) {
+	gen, err := r.redisClient.Get(ctx, listGenerationKey).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return gen, err
 }
 
+// DeleteArticle also drops the legacy key, so instances of the previous
+// release still running during a deploy do not serve the old article.
 func (r *articleCacheRepository) DeleteArticle(ctx context.Context, id uint) error {
-	key := fmt.Sprintf("article:%d", id)
-	return r.redisClient.Del(ctx, key).Err()
+	keys := []string{generationKey(id), articleKey(id), legacyArticleKey(id)}
+	return deleteArticleScript.Run(ctx, r.redisClient, keys, generationTTL.Milliseconds()).Err()
+}
+
+func (r *articleCacheRepository) InvalidateArticleLists(ctx context.Context) error {
+	return r.redisClient.Incr(ctx, listGenerationKey).Err()
+}
+
+// listKey prefixes key with the current list generation.
+func (r *articleCacheRepository) listKey(ctx context.Context, key string) (string, error) {
+	gen, err := r.ListGeneration(ctx)
+	if err != nil {
+		return "", err
+	}
+	return listKeyAt(gen, key), nil
+}
+
+// listKeyAt is where a list cached at generation gen lives.
+func listKeyAt(gen int64, key string) string {
+	return fmt.Sprintf("lists:%d:%s", gen, key)
 }
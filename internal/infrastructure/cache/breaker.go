@@ -0,0 +1,199 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/FormalYou/clean-architecture-blog/internal/application/repository"
+)
+
+// ErrCircuitOpen is returned for Redis commands rejected by an open breaker.
+// It is a repository.ErrCacheUnavailable, which callers treat as expected.
+var ErrCircuitOpen = fmt.Errorf("redis circuit breaker is open: %w", repository.ErrCacheUnavailable)
+
+// BreakerState is the state of a CircuitBreaker.
+type BreakerState int
+
+const (
+	// BreakerClosed lets every command through.
+	BreakerClosed BreakerState = iota
+	// BreakerOpen rejects commands until the open timeout elapses.
+	BreakerOpen
+	// BreakerHalfOpen lets a single probe through to test recovery.
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// BreakerOptions configures a CircuitBreaker.
+type BreakerOptions struct {
+	// FailureThreshold is the number of consecutive failures that opens the breaker.
+	FailureThreshold int
+	// OpenTimeout is how long the breaker stays open before probing again.
+	OpenTimeout time.Duration
+	// OnStateChange is called after every transition, outside the breaker's lock.
+	OnStateChange func(from, to BreakerState)
+}
+
+// CircuitBreaker 是 go-redis 的 Hook：连续失败达到阈值后在 OpenTimeout 内直接拒绝
+// 命令，避免 Redis 不可用时每个请求都等待超时；之后放行单个探测命令，成功即恢复。
+// 客户端本身会在连接失效后自动重连，探测命令即走新的连接。
+type CircuitBreaker struct {
+	opts BreakerOptions
+	now  func() time.Time
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+}
+
+// NewCircuitBreaker 创建熔断器，通过 client.AddHook 安装
+func NewCircuitBreaker(opts BreakerOptions) *CircuitBreaker {
+	if opts.FailureThreshold <= 0 {
+		opts.FailureThreshold = 5
+	}
+	if opts.OpenTimeout <= 0 {
+		opts.OpenTimeout = 5 * time.Second
+	}
+	return &CircuitBreaker{opts: opts, now: time.Now}
+}
+
+// State returns the current state.
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+func (b *CircuitBreaker) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+// probeKey marks the context of the half-open probe. Commands go-redis runs
+// on the probe's behalf, such as the handshake on a new connection, carry it
+// and are let through.
+type probeKey struct{}
+
+func (b *CircuitBreaker) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		ctx, err := b.allow(ctx)
+		if err != nil {
+			cmd.SetErr(err)
+			return err
+		}
+		err = next(ctx, cmd)
+		b.record(err)
+		return err
+	}
+}
+
+func (b *CircuitBreaker) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		ctx, err := b.allow(ctx)
+		if err != nil {
+			for _, cmd := range cmds {
+				cmd.SetErr(err)
+			}
+			return err
+		}
+		err = next(ctx, cmds)
+		b.record(err)
+		return err
+	}
+}
+
+// allow reports whether a command may run, moving an open breaker to
+// half-open once the timeout has elapsed. The returned context marks the
+// probe.
+func (b *CircuitBreaker) allow(ctx context.Context) (context.Context, error) {
+	if ctx.Value(probeKey{}) != nil {
+		return ctx, nil
+	}
+	b.mu.Lock()
+	switch b.state {
+	case BreakerClosed:
+		b.mu.Unlock()
+		return ctx, nil
+	case BreakerOpen:
+		if b.now().Sub(b.openedAt) >= b.opts.OpenTimeout {
+			b.transition(BreakerHalfOpen) // unlocks
+			return context.WithValue(ctx, probeKey{}, true), nil
+		}
+	}
+	// Open, or half-open with the probe already in flight.
+	b.mu.Unlock()
+	return ctx, ErrCircuitOpen
+}
+
+// record feeds the outcome of a command into the breaker.
+func (b *CircuitBreaker) record(err error) {
+	b.mu.Lock()
+	failed := isConnectionFailure(err)
+	switch {
+	case errors.Is(err, context.Canceled):
+		// The caller gave up; says nothing about Redis. A cancelled probe
+		// reopens the breaker so the next command probes again.
+		if b.state == BreakerHalfOpen {
+			b.openedAt = time.Time{}
+			b.transition(BreakerOpen)
+			return
+		}
+	case failed && b.state == BreakerHalfOpen:
+		b.openedAt = b.now()
+		b.transition(BreakerOpen)
+		return
+	case failed && b.state == BreakerClosed:
+		b.failures++
+		if b.failures >= b.opts.FailureThreshold {
+			b.openedAt = b.now()
+			b.transition(BreakerOpen)
+			return
+		}
+	case !failed:
+		b.failures = 0
+		if b.state == BreakerHalfOpen {
+			b.transition(BreakerClosed)
+			return
+		}
+	}
+	b.mu.Unlock()
+}
+
+// transition changes state and notifies the listener. The caller holds mu,
+// which transition releases.
+func (b *CircuitBreaker) transition(to BreakerState) {
+	from := b.state
+	b.state = to
+	if to != BreakerClosed {
+		b.failures = 0
+	}
+	b.mu.Unlock()
+	if from != to && b.opts.OnStateChange != nil {
+		b.opts.OnStateChange(from, to)
+	}
+}
+
+// isConnectionFailure tells Redis being unreachable or slow apart from
+// misses and error replies, which come from a healthy server.
+func isConnectionFailure(err error) bool {
+	if err == nil || errors.Is(err, redis.Nil) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var replyErr redis.Error
+	return !errors.As(err, &replyErr)
+}
@@ -0,0 +1,403 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FormalYou/clean-architecture-blog/domain"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/contracts"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/repository"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/repository/repositorytest"
+)
+
+// fakeClock is a manually advanced clock for the memory cache.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestMemoryArticleCache_Conformance(t *testing.T) {
+	repositorytest.RunArticleCache(t, func(t *testing.T) (repository.ArticleCacheRepository, func(time.Duration)) {
+		clock := &fakeClock{now: time.Now()}
+		return newMemoryArticleCacheRepository(clock.Now), clock.Advance
+	})
+}
+
+func TestRedisArticleCache_Conformance(t *testing.T) {
+	repositorytest.RunArticleCache(t, func(t *testing.T) (repository.ArticleCacheRepository, func(time.Duration)) {
+		mr := miniredis.RunT(t)
+		client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+		t.Cleanup(func() { client.Close() })
+		return NewArticleCacheRepository(client), mr.FastForward
+	})
+}
+
+func TestRedisArticleCache_IgnoresForeignValues(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	cache := NewArticleCacheRepository(client)
+	ctx := context.Background()
+
+	// Earlier releases cached the bare article under article:<id>.
+	require.NoError(t, mr.Set("article:1", `{"id":1,"title":"old shape"}`))
+	got, err := cache.GetArticle(ctx, 1)
+	require.NoError(t, err)
+	assert.Nil(t, got, "the legacy key is not read")
+
+	for _, val := range []string{`{"id":1,"title":"old shape"}`, `not json`} {
+		require.NoError(t, mr.Set("article:v2:1", val))
+		got, err = cache.GetArticle(ctx, 1)
+		require.NoError(t, err)
+		assert.Nil(t, got, "%s is a miss", val)
+	}
+
+	require.NoError(t, cache.DeleteArticle(ctx, 1))
+	assert.False(t, mr.Exists("article:1"), "deletes reach the legacy key")
+	assert.False(t, mr.Exists("article:v2:1"))
+}
+
+func TestMemoryArticleCache_SweepsExpiredEntries(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	cache := newMemoryArticleCacheRepository(clock.Now)
+	ctx := context.Background()
+
+	for i := 0; i < sweepEvery-1; i++ {
+		assert.NoError(t, cache.SetArticles(ctx, fmt.Sprintf("k%d", i), nil, 0, time.Second))
+	}
+	clock.Advance(2 * time.Second)
+	assert.NoError(t, cache.SetArticles(ctx, "fresh", nil, 0, time.Minute))
+
+	assert.Len(t, cache.entries, 1)
+}
+
+func TestRedisLocker(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	locker := NewRedisLocker(client)
+	ctx := context.Background()
+
+	unlock, acquired, err := locker.TryLock(ctx, "article:1", time.Second)
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	_, acquired, err = locker.TryLock(ctx, "article:1", time.Second)
+	require.NoError(t, err)
+	assert.False(t, acquired, "the lock is held")
+
+	_, acquired, err = locker.TryLock(ctx, "article:2", time.Second)
+	require.NoError(t, err)
+	assert.True(t, acquired, "locks are per key")
+
+	require.NoError(t, unlock(ctx))
+	unlock, acquired, err = locker.TryLock(ctx, "article:1", time.Second)
+	require.NoError(t, err)
+	require.True(t, acquired, "released locks can be taken again")
+
+	// An expired lock taken over by another holder is not released by the first.
+	mr.FastForward(2 * time.Second)
+	_, acquired, err = locker.TryLock(ctx, "article:1", time.Second)
+	require.NoError(t, err)
+	require.True(t, acquired)
+	require.NoError(t, unlock(ctx))
+	_, acquired, err = locker.TryLock(ctx, "article:1", time.Second)
+	require.NoError(t, err)
+	assert.False(t, acquired)
+}
+
+func TestTieredArticleCache_Conformance(t *testing.T) {
+	repositorytest.RunArticleCache(t, func(t *testing.T) (repository.ArticleCacheRepository, func(time.Duration)) {
+		mr := miniredis.RunT(t)
+		client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+		t.Cleanup(func() { client.Close() })
+		clock := &fakeClock{now: time.Now()}
+		tiered := newTieredArticleCache(NewArticleCacheRepository(client), client, TieredOptions{Size: 100, TTL: time.Hour}, clock.Now)
+		require.NoError(t, tiered.Start(context.Background()))
+		t.Cleanup(tiered.Close)
+		return tiered, func(d time.Duration) {
+			clock.Advance(d)
+			mr.FastForward(d)
+		}
+	})
+}
+
+// newTieredPair returns two tiered caches sharing one Redis, as two instances would.
+func newTieredPair(t *testing.T) (*miniredis.Miniredis, *TieredArticleCache, *TieredArticleCache) {
+	mr := miniredis.RunT(t)
+	newInstance := func() *TieredArticleCache {
+		client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+		tiered := NewTieredArticleCache(NewArticleCacheRepository(client), client, TieredOptions{Size: 100, TTL: time.Hour})
+		require.NoError(t, tiered.Start(context.Background()))
+		t.Cleanup(func() {
+			tiered.Close()
+			client.Close()
+		})
+		return tiered
+	}
+	return mr, newInstance(), newInstance()
+}
+
+func TestTieredArticleCache_ServesLocalCopies(t *testing.T) {
+	mr, a, _ := newTieredPair(t)
+	ctx := context.Background()
+	entry := &repository.ArticleCacheEntry{Article: &domain.Article{ID: 1, Title: "title"}, FreshUntil: time.Now().Add(time.Minute)}
+
+	require.NoError(t, a.SetArticle(ctx, 1, entry, time.Minute))
+	mr.Del("article:v2:1")
+
+	got, err := a.GetArticle(ctx, 1)
+	require.NoError(t, err)
+	require.NotNil(t, got, "answered by the local tier without Redis")
+	got.Article.Title = "mutated"
+	got, err = a.GetArticle(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "title", got.Article.Title, "callers get copies")
+
+	_, err = a.GetArticle(ctx, 2)
+	require.NoError(t, err)
+	stats := a.Stats()
+	assert.Equal(t, contracts.CacheTierStats{Hits: 2, Misses: 1, HitRatio: 2.0 / 3}, stats.Local)
+	assert.Equal(t, contracts.CacheTierStats{Hits: 0, Misses: 1}, stats.Remote)
+	assert.Equal(t, 1, stats.LocalEntries)
+}
+
+func TestTieredArticleCache_BroadcastsInvalidations(t *testing.T) {
+	_, a, b := newTieredPair(t)
+	ctx := context.Background()
+	article := func(title string) *repository.ArticleCacheEntry {
+		return &repository.ArticleCacheEntry{Article: &domain.Article{ID: 1, Title: title}, FreshUntil: time.Now().Add(time.Minute)}
+	}
+	title := func(c *TieredArticleCache) string {
+		got, err := c.GetArticle(ctx, 1)
+		require.NoError(t, err)
+		if got == nil {
+			return ""
+		}
+		return got.Article.Title
+	}
+
+	require.NoError(t, a.SetArticle(ctx, 1, article("v1"), time.Minute))
+	assert.Equal(t, "v1", title(b))
+	require.NoError(t, b.SetArticles(ctx, "articles:all", []*domain.Article{{ID: 1}}, 0, time.Minute))
+
+	// b now holds local copies; writes through a must evict them.
+	require.NoError(t, a.SetArticle(ctx, 1, article("v2"), time.Minute))
+	assert.Eventually(t, func() bool { return title(b) == "v2" }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, "v2", title(a), "the writer keeps its own fresh copy")
+
+	require.NoError(t, a.DeleteArticle(ctx, 1))
+	assert.Eventually(t, func() bool { return title(b) == "" }, time.Second, 5*time.Millisecond)
+
+	require.NoError(t, a.InvalidateArticleLists(ctx))
+	assert.Eventually(t, func() bool {
+		list, err := b.GetArticles(ctx, "articles:all")
+		return err == nil && list == nil
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestLRU_EvictsLeastRecentlyUsed(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	c := newLRU(2, clock.Now)
+	c.set("a", 1, time.Minute)
+	c.set("b", 2, time.Minute)
+	_, _ = c.get("a")
+	c.set("c", 3, time.Minute)
+
+	_, ok := c.get("b")
+	assert.False(t, ok, "b was least recently used")
+	_, ok = c.get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 2, c.len())
+
+	clock.Advance(time.Minute)
+	_, ok = c.get("c")
+	assert.False(t, ok, "entries expire")
+}
+
+// newBreakerClient returns a client whose commands go through a breaker
+// driven by clock.
+func newBreakerClient(t *testing.T, mr *miniredis.Miniredis, clock *fakeClock) (*redis.Client, *CircuitBreaker) {
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1, DialTimeout: 100 * time.Millisecond})
+	t.Cleanup(func() { client.Close() })
+	breaker := NewCircuitBreaker(BreakerOptions{FailureThreshold: 3, OpenTimeout: time.Second})
+	breaker.now = clock.Now
+	client.AddHook(breaker)
+	return client, breaker
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	mr := miniredis.RunT(t)
+	clock := &fakeClock{now: time.Now()}
+	client, breaker := newBreakerClient(t, mr, clock)
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		assert.ErrorIs(t, client.Get(ctx, "missing").Err(), redis.Nil)
+	}
+	assert.Equal(t, BreakerClosed, breaker.State(), "misses are not failures")
+
+	mr.Close()
+	for i := 0; i < 3; i++ {
+		err := client.Get(ctx, "k").Err()
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, ErrCircuitOpen)
+	}
+	assert.Equal(t, BreakerOpen, breaker.State())
+	assert.ErrorIs(t, client.Get(ctx, "k").Err(), ErrCircuitOpen, "an open breaker rejects without calling Redis")
+	assert.ErrorIs(t, client.Get(ctx, "k").Err(), repository.ErrCacheUnavailable, "callers see the rejection as an expected degradation")
+
+	clock.Advance(time.Second)
+	assert.NotErrorIs(t, client.Get(ctx, "k").Err(), ErrCircuitOpen, "one probe goes through after the timeout")
+	assert.Equal(t, BreakerOpen, breaker.State(), "the failed probe reopens the breaker")
+
+	require.NoError(t, mr.Restart())
+	clock.Advance(time.Second)
+	assert.ErrorIs(t, client.Get(ctx, "k").Err(), redis.Nil)
+	assert.Equal(t, BreakerClosed, breaker.State(), "a successful probe closes the breaker")
+}
+
+func TestFallbackArticleCache(t *testing.T) {
+	mr := miniredis.RunT(t)
+	clock := &fakeClock{now: time.Now()}
+	client, _ := newBreakerClient(t, mr, clock)
+	fallback := NewFallbackArticleCache(NewArticleCacheRepository(client), NewMemoryArticleCacheRepository(), time.Minute)
+	ctx := context.Background()
+	entry := func(title string) *repository.ArticleCacheEntry {
+		return &repository.ArticleCacheEntry{Article: &domain.Article{ID: 1, Title: title}, FreshUntil: time.Now().Add(time.Minute)}
+	}
+
+	require.NoError(t, fallback.SetArticle(ctx, 1, entry("before outage"), time.Hour))
+	require.NoError(t, fallback.SetArticles(ctx, "articles:all", []*domain.Article{{ID: 1}}, 0, time.Hour))
+
+	mr.Close()
+	require.NoError(t, fallback.DeleteArticle(ctx, 1), "a missed delete is not an error")
+	require.NoError(t, fallback.InvalidateArticleLists(ctx))
+	require.NoError(t, fallback.SetArticle(ctx, 2, entry("during outage"), time.Hour))
+	got, err := fallback.GetArticle(ctx, 2)
+	require.NoError(t, err)
+	require.NotNil(t, got, "served from the in-memory cache")
+	assert.Equal(t, "during outage", got.Article.Title)
+	assert.Equal(t, 2, fallback.Pending())
+
+	// Redis comes back with the data it had before the outage.
+	require.NoError(t, mr.Restart())
+	clock.Advance(time.Minute)
+	got, err = fallback.GetArticle(ctx, 1)
+	require.NoError(t, err)
+	assert.Nil(t, got, "the delete missed during the outage was replayed")
+	list, err := fallback.GetArticles(ctx, "articles:all")
+	require.NoError(t, err)
+	assert.Nil(t, list)
+	assert.Zero(t, fallback.Pending())
+}
+
+func TestTieredArticleCache_StartsWithoutRedis(t *testing.T) {
+	mr := miniredis.RunT(t)
+	addr := mr.Addr()
+	mr.Close()
+
+	client := redis.NewClient(&redis.Options{Addr: addr, MaxRetries: -1, DialTimeout: 50 * time.Millisecond})
+	t.Cleanup(func() { client.Close() })
+	tiered := NewTieredArticleCache(NewArticleCacheRepository(client), client, TieredOptions{Size: 10, TTL: time.Minute})
+	t.Cleanup(tiered.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	assert.Error(t, tiered.Start(ctx))
+
+	require.NoError(t, mr.StartAddr(addr))
+	t.Cleanup(mr.Close)
+	assert.Eventually(t, tiered.subscribed.Load, 3*time.Second, 10*time.Millisecond, "the subscription is retried")
+
+	require.NoError(t, tiered.SetArticle(context.Background(), 1, &repository.ArticleCacheEntry{}, time.Minute))
+	assert.Equal(t, 1, tiered.Stats().LocalEntries)
+}
+
+func TestIdempotencyStore(t *testing.T) {
+	stores := map[string]func(t *testing.T) (repository.IdempotencyStore, func(time.Duration)){
+		"Memory": func(t *testing.T) (repository.IdempotencyStore, func(time.Duration)) {
+			clock := &fakeClock{now: time.Now()}
+			return newMemoryIdempotencyStore(clock.Now), clock.Advance
+		},
+		"Redis": func(t *testing.T) (repository.IdempotencyStore, func(time.Duration)) {
+			mr := miniredis.RunT(t)
+			client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+			t.Cleanup(func() { _ = client.Close() })
+			return NewRedisIdempotencyStore(client), mr.FastForward
+		},
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			store, advance := newStore(t)
+			resp := repository.IdempotentResponse{Status: 201, ContentType: "application/json", Body: []byte(`{"id":1}`)}
+
+			existing, claim, err := store.Begin(ctx, "k", "fp", time.Minute)
+			require.NoError(t, err)
+			require.NotEmpty(t, claim)
+			assert.Nil(t, existing)
+
+			existing, other, err := store.Begin(ctx, "k", "other", time.Minute)
+			require.NoError(t, err)
+			assert.Empty(t, other)
+			assert.Equal(t, &repository.IdempotencyRecord{Fingerprint: "fp"}, existing, "in flight")
+
+			require.NoError(t, store.Complete(ctx, "k", claim, "fp", resp, time.Hour))
+			existing, other, err = store.Begin(ctx, "k", "fp", time.Minute)
+			require.NoError(t, err)
+			assert.Empty(t, other)
+			assert.Equal(t, &repository.IdempotencyRecord{Fingerprint: "fp", Response: &resp}, existing)
+			assert.ErrorIs(t, store.Complete(ctx, "k", claim, "fp", resp, time.Hour), repository.ErrIdempotencyClaimLost, "completed once")
+			require.NoError(t, store.Release(ctx, "k", claim))
+			existing, _, err = store.Begin(ctx, "k", "fp", time.Minute)
+			require.NoError(t, err)
+			assert.NotNil(t, existing, "a completed record is not released")
+
+			advance(2 * time.Hour)
+			_, claim, err = store.Begin(ctx, "k", "fp", time.Minute)
+			require.NoError(t, err)
+			assert.NotEmpty(t, claim, "completed records expire")
+
+			require.NoError(t, store.Release(ctx, "k", claim))
+			_, claim, err = store.Begin(ctx, "k", "fp", time.Minute)
+			require.NoError(t, err)
+			assert.NotEmpty(t, claim, "released keys can be claimed again")
+
+			advance(2 * time.Minute)
+			_, retry, err := store.Begin(ctx, "k", "fp", time.Minute)
+			require.NoError(t, err)
+			require.NotEmpty(t, retry, "abandoned claims expire")
+			assert.NotEqual(t, claim, retry)
+
+			assert.ErrorIs(t, store.Complete(ctx, "k", claim, "fp", resp, time.Hour), repository.ErrIdempotencyClaimLost, "an expired claim cannot store a response")
+			require.NoError(t, store.Release(ctx, "k", claim))
+			existing, _, err = store.Begin(ctx, "k", "other", time.Minute)
+			require.NoError(t, err)
+			assert.Equal(t, &repository.IdempotencyRecord{Fingerprint: "fp"}, existing, "an expired claim cannot release the retry's claim")
+
+			require.NoError(t, store.Complete(ctx, "k", retry, "fp", resp, time.Hour))
+		})
+	}
+}
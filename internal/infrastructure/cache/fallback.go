@@ -0,0 +1,182 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/FormalYou/clean-architecture-blog/domain"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/repository"
+)
+
+// FallbackArticleCache 在主缓存（Redis）出错时改用备用缓存（通常是内存缓存），
+// 使 Redis 故障只降低命中率而不影响请求。故障期间无法送达主缓存的删除与列表失效
+// 会被记录，并在主缓存恢复后的第一次成功调用时重放，避免恢复后读到故障期间已被
+// 修改的旧数据。
+type FallbackArticleCache struct {
+	primary  repository.ArticleCacheRepository
+	fallback repository.ArticleCacheRepository
+	// ttl caps entries in the fallback, which other instances cannot
+	// invalidate.
+	ttl time.Duration
+
+	mu          sync.Mutex
+	missedIDs   map[uint]struct{}
+	missedLists bool
+	pending     atomic.Bool
+	replaying   atomic.Bool
+}
+
+// NewFallbackArticleCache 创建带降级的文章缓存
+func NewFallbackArticleCache(primary, fallback repository.ArticleCacheRepository, ttl time.Duration) *FallbackArticleCache {
+	return &FallbackArticleCache{primary: primary, fallback: fallback, ttl: ttl, missedIDs: map[uint]struct{}{}}
+}
+
+func (c *FallbackArticleCache) GetArticle(ctx context.Context, id uint) (*repository.ArticleCacheEntry, error) {
+	entry, err := c.primary.GetArticle(ctx, id)
+	if err != nil {
+		return c.fallback.GetArticle(ctx, id)
+	}
+	if c.replayPending(ctx) {
+		// The entry may predate an invalidation that was just replayed.
+		return c.primary.GetArticle(ctx, id)
+	}
+	return entry, nil
+}
+
+func (c *FallbackArticleCache) SetArticle(ctx context.Context, id uint, entry *repository.ArticleCacheEntry, expiration time.Duration) error {
+	if err := c.primary.SetArticle(ctx, id, entry, expiration); errors.Is(err, repository.ErrStaleCacheEntry) {
+		return err
+	} else if err != nil {
+		return c.fallback.SetArticle(ctx, id, entry, c.fallbackTTL(expiration))
+	}
+	c.replayPending(ctx)
+	return nil
+}
+
+// ArticleGeneration reads the generation from the cache the entry will be
+// stored in. Should the primary fail in between, the fallback refuses the
+// entry unless its own generation matches, which only costs a cache fill.
+func (c *FallbackArticleCache) ArticleGeneration(ctx context.Context, id uint) (int64, error) {
+	gen, err := c.primary.ArticleGeneration(ctx, id)
+	if err != nil {
+		return c.fallback.ArticleGeneration(ctx, id)
+	}
+	return gen, nil
+}
+
+func (c *FallbackArticleCache) GetArticles(ctx context.Context, key string) ([]*domain.Article, error) {
+	articles, err := c.primary.GetArticles(ctx, key)
+	if err != nil {
+		return c.fallback.GetArticles(ctx, key)
+	}
+	if c.replayPending(ctx) {
+		return c.primary.GetArticles(ctx, key)
+	}
+	return articles, nil
+}
+
+func (c *FallbackArticleCache) SetArticles(ctx context.Context, key string, articles []*domain.Article, generation int64, expiration time.Duration) error {
+	if err := c.primary.SetArticles(ctx, key, articles, generation, expiration); errors.Is(err, repository.ErrStaleCacheEntry) {
+		return err
+	} else if err != nil {
+		return c.fallback.SetArticles(ctx, key, articles, generation, c.fallbackTTL(expiration))
+	}
+	c.replayPending(ctx)
+	return nil
+}
+
+// ListGeneration reads the list generation like ArticleGeneration.
+func (c *FallbackArticleCache) ListGeneration(ctx context.Context) (int64, error) {
+	gen, err := c.primary.ListGeneration(ctx)
+	if err != nil {
+		return c.fallback.ListGeneration(ctx)
+	}
+	return gen, nil
+}
+
+// DeleteArticle always clears both caches. A delete the primary missed is
+// replayed later, so it is not reported as an error.
+func (c *FallbackArticleCache) DeleteArticle(ctx context.Context, id uint) error {
+	_ = c.fallback.DeleteArticle(ctx, id)
+	if err := c.primary.DeleteArticle(ctx, id); err != nil {
+		c.miss(func() { c.missedIDs[id] = struct{}{} })
+		return nil
+	}
+	c.replayPending(ctx)
+	return nil
+}
+
+// InvalidateArticleLists always clears both caches, like DeleteArticle.
+func (c *FallbackArticleCache) InvalidateArticleLists(ctx context.Context) error {
+	_ = c.fallback.InvalidateArticleLists(ctx)
+	if err := c.primary.InvalidateArticleLists(ctx); err != nil {
+		c.miss(func() { c.missedLists = true })
+		return nil
+	}
+	c.replayPending(ctx)
+	return nil
+}
+
+// Pending reports how many invalidations are waiting to be replayed.
+func (c *FallbackArticleCache) Pending() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := len(c.missedIDs)
+	if c.missedLists {
+		n++
+	}
+	return n
+}
+
+func (c *FallbackArticleCache) miss(record func()) {
+	c.mu.Lock()
+	record()
+	c.mu.Unlock()
+	c.pending.Store(true)
+}
+
+// replayPending replays missed invalidations after the primary answered
+// again and reports whether it did. Only one caller replays at a time.
+func (c *FallbackArticleCache) replayPending(ctx context.Context) bool {
+	if !c.pending.Load() || !c.replaying.CompareAndSwap(false, true) {
+		return false
+	}
+	defer c.replaying.Store(false)
+	_ = c.replay(ctx)
+	return true
+}
+
+// replay sends the missed invalidations to the primary. Those that fail
+// again stay pending.
+func (c *FallbackArticleCache) replay(ctx context.Context) error {
+	c.mu.Lock()
+	ids, lists := c.missedIDs, c.missedLists
+	c.missedIDs, c.missedLists = map[uint]struct{}{}, false
+	c.pending.Store(false)
+	c.mu.Unlock()
+
+	var errs []error
+	for id := range ids {
+		if err := c.primary.DeleteArticle(ctx, id); err != nil {
+			errs = append(errs, err)
+			c.miss(func() { c.missedIDs[id] = struct{}{} })
+		}
+	}
+	if lists {
+		if err := c.primary.InvalidateArticleLists(ctx); err != nil {
+			errs = append(errs, err)
+			c.miss(func() { c.missedLists = true })
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (c *FallbackArticleCache) fallbackTTL(expiration time.Duration) time.Duration {
+	if expiration > 0 && expiration < c.ttl {
+		return expiration
+	}
+	return c.ttl
+}
@@ -0,0 +1,204 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/FormalYou/clean-architecture-blog/internal/application/repository"
+)
+
+// idempotencyPrefix namespaces idempotency records in Redis.
+const idempotencyPrefix = "idempotency:"
+
+// completeIdempotencyScript stores the response only while the pending record
+// still carries the caller's claim, so a request whose lock expired cannot
+// overwrite the record of the retry that claimed the key after it.
+var completeIdempotencyScript = redis.NewScript(`
+local val = redis.call("GET", KEYS[1])
+if not val then
+	return 0
+end
+local record = cjson.decode(val)
+if record.response ~= nil or record.claim ~= ARGV[1] then
+	return 0
+end
+redis.call("SET", KEYS[1], ARGV[2], "PX", ARGV[3])
+return 1`)
+
+// releaseIdempotencyScript deletes the pending record only while it still
+// carries the caller's claim.
+var releaseIdempotencyScript = redis.NewScript(`
+local val = redis.call("GET", KEYS[1])
+if not val then
+	return 0
+end
+local record = cjson.decode(val)
+if record.response ~= nil or record.claim ~= ARGV[1] then
+	return 0
+end
+return redis.call("DEL", KEYS[1])`)
+
+// newClaim returns a random token identifying one request's hold on a key.
+func newClaim() (string, error) {
+	var buf [16]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf[:]), nil
+}
+
+type redisIdempotencyStore struct {
+	redisClient *redis.Client
+}
+
+// NewRedisIdempotencyStore 创建基于 Redis 的幂等键存储，所有实例共享
+func NewRedisIdempotencyStore(redisClient *redis.Client) repository.IdempotencyStore {
+	return &redisIdempotencyStore{redisClient: redisClient}
+}
+
+func (s *redisIdempotencyStore) Begin(ctx context.Context, key, fingerprint string, lockTTL time.Duration) (*repository.IdempotencyRecord, string, error) {
+	claim, err := newClaim()
+	if err != nil {
+		return nil, "", err
+	}
+	pending, err := json.Marshal(repository.IdempotencyRecord{Fingerprint: fingerprint, Claim: claim})
+	if err != nil {
+		return nil, "", err
+	}
+	key = idempotencyPrefix + key
+	// The record can expire between SET NX and GET; claiming again covers that.
+	for attempt := 0; attempt < 2; attempt++ {
+		claimed, err := s.redisClient.SetNX(ctx, key, pending, lockTTL).Result()
+		if err != nil {
+			return nil, "", err
+		}
+		if claimed {
+			return nil, claim, nil
+		}
+		val, err := s.redisClient.Get(ctx, key).Bytes()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		var record repository.IdempotencyRecord
+		if err := json.Unmarshal(val, &record); err != nil {
+			return nil, "", err
+		}
+		record.Claim = ""
+		return &record, "", nil
+	}
+	return nil, "", errors.New("idempotency key expired while being claimed")
+}
+
+func (s *redisIdempotencyStore) Complete(ctx context.Context, key, claim, fingerprint string, resp repository.IdempotentResponse, ttl time.Duration) error {
+	val, err := json.Marshal(repository.IdempotencyRecord{Fingerprint: fingerprint, Response: &resp})
+	if err != nil {
+		return err
+	}
+	stored, err := completeIdempotencyScript.Run(ctx, s.redisClient, []string{idempotencyPrefix + key}, claim, val, ttl.Milliseconds()).Int()
+	if err != nil {
+		return err
+	}
+	if stored == 0 {
+		return repository.ErrIdempotencyClaimLost
+	}
+	return nil
+}
+
+func (s *redisIdempotencyStore) Release(ctx context.Context, key, claim string) error {
+	return releaseIdempotencyScript.Run(ctx, s.redisClient, []string{idempotencyPrefix + key}, claim).Err()
+}
+
+type memoryIdempotencyEntry struct {
+	record    repository.IdempotencyRecord
+	expiresAt time.Time
+}
+
+// memoryIdempotencyStore 是 IdempotencyStore 的内存实现，用于 storage: memory。
+type memoryIdempotencyStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryIdempotencyEntry
+	writes  int
+	now     func() time.Time
+}
+
+// NewMemoryIdempotencyStore 创建进程内的幂等键存储
+func NewMemoryIdempotencyStore() repository.IdempotencyStore {
+	return newMemoryIdempotencyStore(time.Now)
+}
+
+func newMemoryIdempotencyStore(now func() time.Time) *memoryIdempotencyStore {
+	return &memoryIdempotencyStore{entries: map[string]memoryIdempotencyEntry{}, now: now}
+}
+
+func (s *memoryIdempotencyStore) Begin(ctx context.Context, key, fingerprint string, lockTTL time.Duration) (*repository.IdempotencyRecord, string, error) {
+	claim, err := newClaim()
+	if err != nil {
+		return nil, "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if entry, ok := s.entries[key]; ok && now.Before(entry.expiresAt) {
+		record := entry.record
+		record.Claim = ""
+		return &record, "", nil
+	}
+	s.put(now, key, repository.IdempotencyRecord{Fingerprint: fingerprint, Claim: claim}, lockTTL)
+	return nil, claim, nil
+}
+
+func (s *memoryIdempotencyStore) Complete(ctx context.Context, key, claim, fingerprint string, resp repository.IdempotentResponse, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if !s.holds(now, key, claim) {
+		return repository.ErrIdempotencyClaimLost
+	}
+	resp.Body = append([]byte(nil), resp.Body...)
+	s.put(now, key, repository.IdempotencyRecord{Fingerprint: fingerprint, Response: &resp}, ttl)
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Release(ctx context.Context, key, claim string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.holds(s.now(), key, claim) {
+		delete(s.entries, key)
+	}
+	return nil
+}
+
+// holds reports whether key is still pending under claim. Callers hold s.mu.
+func (s *memoryIdempotencyStore) holds(now time.Time, key, claim string) bool {
+	entry, ok := s.entries[key]
+	return ok && now.Before(entry.expiresAt) && entry.record.Response == nil && entry.record.Claim == claim
+}
+
+// put stores record under key and now and then drops expired entries.
+// Callers hold s.mu.
+func (s *memoryIdempotencyStore) put(now time.Time, key string, record repository.IdempotencyRecord, ttl time.Duration) {
+	s.entries[key] = memoryIdempotencyEntry{record: record, expiresAt: now.Add(ttl)}
+	s.writes++
+	if s.writes%sweepEvery != 0 {
+		return
+	}
+	for k, entry := range s.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(s.entries, k)
+		}
+	}
+}
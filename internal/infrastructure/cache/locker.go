@@ -0,0 +1,46 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/FormalYou/clean-architecture-blog/internal/application/repository"
+	"github.com/redis/go-redis/v9"
+)
+
+// unlockScript deletes the lock only while it still holds our token, so a
+// holder whose lock already expired cannot release someone else's.
+var unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+type redisLocker struct {
+	redisClient *redis.Client
+}
+
+// NewRedisLocker 创建基于 Redis SET NX PX 的跨实例锁
+func NewRedisLocker(redisClient *redis.Client) repository.CacheLocker {
+	return &redisLocker{redisClient: redisClient}
+}
+
+func (l *redisLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (func(context.Context) error, bool, error) {
+	var buf [16]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return nil, false, err
+	}
+	token := hex.EncodeToString(buf[:])
+
+	key = "lock:" + key
+	acquired, err := l.redisClient.SetNX(ctx, key, token, ttl).Result()
+	if err != nil || !acquired {
+		return nil, false, err
+	}
+	unlock := func(ctx context.Context) error {
+		return unlockScript.Run(ctx, l.redisClient, []string{key}, token).Err()
+	}
+	return unlock, true, nil
+}
@@ -0,0 +1,181 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FormalYou/clean-architecture-blog/domain"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/repository"
+)
+
+// sweepEvery is how many writes pass between purges of expired entries.
+const sweepEvery = 128
+
+// listPrefix namespaces cached lists so they can be dropped together.
+const listPrefix = "lists:"
+
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time // zero means no expiry
+}
+
+// memoryArticleCacheRepository 是 ArticleCacheRepository 的内存实现。值以 JSON
+// 保存，与 Redis 实现一样，读出的是副本。
+type memoryArticleCacheRepository struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+	// listGen is advanced by InvalidateArticleLists.
+	listGen int64
+	writes  int
+	now     func() time.Time
+}
+
+// NewMemoryArticleCacheRepository 创建一个带过期时间支持的内存文章缓存
+func NewMemoryArticleCacheRepository() repository.ArticleCacheRepository {
+	return newMemoryArticleCacheRepository(time.Now)
+}
+
+func newMemoryArticleCacheRepository(now func() time.Time) *memoryArticleCacheRepository {
+	return &memoryArticleCacheRepository{entries: map[string]memoryEntry{}, now: now}
+}
+
+func (r *memoryArticleCacheRepository) GetArticle(ctx context.Context, id uint) (*repository.ArticleCacheEntry, error) {
+	val, ok := r.get(fmt.Sprintf("article:%d", id))
+	if !ok {
+		return nil, nil // Cache miss
+	}
+	var entry repository.ArticleCacheEntry
+	if err := json.Unmarshal(val, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *memoryArticleCacheRepository) SetArticle(ctx context.Context, id uint, entry *repository.ArticleCacheEntry, expiration time.Duration) error {
+	val, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.generation(id) != entry.Generation {
+		return repository.ErrStaleCacheEntry
+	}
+	r.store(fmt.Sprintf("article:%d", id), val, expiration)
+	return nil
+}
+
+func (r *memoryArticleCacheRepository) ArticleGeneration(ctx context.Context, id uint) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.generation(id), nil
+}
+
+func (r *memoryArticleCacheRepository) GetArticles(ctx context.Context, key string) ([]*domain.Article, error) {
+	val, ok := r.get(listPrefix + key)
+	if !ok {
+		return nil, nil // Cache miss
+	}
+	var articles []*domain.Article
+	if err := json.Unmarshal(val, &articles); err != nil {
+		return nil, err
+	}
+	return articles, nil
+}
+
+func (r *memoryArticleCacheRepository) SetArticles(ctx context.Context, key string, articles []*domain.Article, generation int64, expiration time.Duration) error {
+	val, err := json.Marshal(articles)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.listGen != generation {
+		return repository.ErrStaleCacheEntry
+	}
+	r.store(listPrefix+key, val, expiration)
+	return nil
+}
+
+func (r *memoryArticleCacheRepository) ListGeneration(ctx context.Context) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.listGen, nil
+}
+
+func (r *memoryArticleCacheRepository) DeleteArticle(ctx context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	gen := r.generation(id) + 1
+	r.store(fmt.Sprintf("article:%d:gen", id), []byte(strconv.FormatInt(gen, 10)), generationTTL)
+	delete(r.entries, fmt.Sprintf("article:%d", id))
+	return nil
+}
+
+// generation returns the invalidation generation of id. The caller holds mu.
+func (r *memoryArticleCacheRepository) generation(id uint) int64 {
+	entry, ok := r.entries[fmt.Sprintf("article:%d:gen", id)]
+	if !ok || r.expired(entry) {
+		return 0
+	}
+	gen, _ := strconv.ParseInt(string(entry.value), 10, 64)
+	return gen
+}
+
+func (r *memoryArticleCacheRepository) InvalidateArticleLists(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.listGen++
+	for key := range r.entries {
+		if strings.HasPrefix(key, listPrefix) {
+			delete(r.entries, key)
+		}
+	}
+	return nil
+}
+
+func (r *memoryArticleCacheRepository) get(key string) ([]byte, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if r.expired(entry) {
+		delete(r.entries, key)
+		return nil, false
+	}
+	return entry.value, true
+}
+
+// store saves val under key. The caller holds mu.
+func (r *memoryArticleCacheRepository) store(key string, val []byte, expiration time.Duration) {
+	entry := memoryEntry{value: val}
+	if expiration > 0 {
+		entry.expiresAt = r.now().Add(expiration)
+	}
+	r.entries[key] = entry
+
+	// Expired entries are otherwise only dropped when read again.
+	r.writes++
+	if r.writes%sweepEvery == 0 {
+		for k, e := range r.entries {
+			if r.expired(e) {
+				delete(r.entries, k)
+			}
+		}
+	}
+}
+
+// expired reports whether entry has passed its expiry. The caller holds mu.
+func (r *memoryArticleCacheRepository) expired(entry memoryEntry) bool {
+	return !entry.expiresAt.IsZero() && !r.now().Before(entry.expiresAt)
+}
@@ -9,24 +9,32 @@ import (
 	"github.com/spf13/viper"
 )
 
-func NewRedisClient() (*redis.Client, error) {
-	addr := viper.GetString("redis.addr")
-	password := viper.GetString("redis.password")
-	db := viper.GetInt("redis.db")
+// RedisOptions reads the client settings from the redis.* keys. Zero
+// timeouts keep the go-redis defaults.
+func RedisOptions() *redis.Options {
+	timeout := time.Duration(viper.GetInt("redis.timeout_ms")) * time.Millisecond
+	return &redis.Options{
+		Addr:         viper.GetString("redis.addr"),
+		Password:     viper.GetString("redis.password"),
+		DB:           viper.GetInt("redis.db"),
+		DialTimeout:  timeout,
+		ReadTimeout:  timeout,
+		WriteTimeout: timeout,
+	}
+}
 
-	rdb := redis.NewClient(&redis.Options{
-		Addr:     addr,
-		Password: password,
-		DB:       db,
-	})
+// NewRedisClient connects to Redis and fails if it does not answer a ping.
+func NewRedisClient() (*redis.Client, error) {
+	rdb := redis.NewClient(RedisOptions())
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	_, err := rdb.Ping(ctx).Result()
 	if err != nil {
+		rdb.Close()
 		return nil, fmt.Errorf("failed to connect to redis: %w", err)
 	}
 
 	return rdb, nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,375 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/FormalYou/clean-architecture-blog/domain"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/contracts"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/repository"
+)
+
+// DefaultInvalidationChannel is the pub/sub channel used when none is configured.
+const DefaultInvalidationChannel = "blog:cache:invalidate"
+
+// invalidateLists is the message payload that drops every local list.
+const invalidateLists = "lists"
+
+// TieredOptions configures the in-process tier of TieredArticleCache.
+type TieredOptions struct {
+	// Size bounds the number of local entries.
+	Size int
+	// TTL caps how long a local copy lives, bounding staleness should an
+	// invalidation message be lost.
+	TTL time.Duration
+	// Channel is the Redis pub/sub channel carrying invalidations.
+	Channel string
+	// OnError reports failures to publish or receive invalidations.
+	OnError func(err error)
+}
+
+type tierCounter struct {
+	hits, misses atomic.Uint64
+}
+
+func (c *tierCounter) record(hit bool) {
+	if hit {
+		c.hits.Add(1)
+	} else {
+		c.misses.Add(1)
+	}
+}
+
+func (c *tierCounter) stats() contracts.CacheTierStats {
+	s := contracts.CacheTierStats{Hits: c.hits.Load(), Misses: c.misses.Load()}
+	if total := s.Hits + s.Misses; total > 0 {
+		s.HitRatio = float64(s.Hits) / float64(total)
+	}
+	return s
+}
+
+// TieredArticleCache 在远程缓存（Redis）前增加一层进程内 LRU，命中时省去网络往返与
+// JSON 解码。写入与失效通过 Redis pub/sub 广播，其他实例收到后淘汰本地副本。
+type TieredArticleCache struct {
+	remote repository.ArticleCacheRepository
+	client *redis.Client
+	local  *lru
+	opts   TieredOptions
+	origin string
+
+	// epoch advances on every local eviction. A value read from the remote
+	// tier is only kept locally if no eviction happened meanwhile, so a
+	// concurrent invalidation cannot be overwritten by the older value.
+	// evictMu makes the check and the store atomic with respect to evictions.
+	evictMu sync.Mutex
+	epoch   atomic.Uint64
+
+	// subscribed is false while invalidations cannot be received. The local
+	// tier is bypassed then, since its copies could not be evicted.
+	subscribed atomic.Bool
+
+	localStats, remoteStats tierCounter
+
+	stop chan struct{}
+	done sync.WaitGroup
+}
+
+// NewTieredArticleCache 创建两级文章缓存，调用 Start 后开始接收其他实例的失效消息
+func NewTieredArticleCache(remote repository.ArticleCacheRepository, client *redis.Client, opts TieredOptions) *TieredArticleCache {
+	return newTieredArticleCache(remote, client, opts, time.Now)
+}
+
+func newTieredArticleCache(remote repository.ArticleCacheRepository, client *redis.Client, opts TieredOptions, now func() time.Time) *TieredArticleCache {
+	if opts.Channel == "" {
+		opts.Channel = DefaultInvalidationChannel
+	}
+	if opts.OnError == nil {
+		opts.OnError = func(error) {}
+	}
+	var buf [8]byte
+	_, _ = rand.Read(buf[:])
+	return &TieredArticleCache{
+		remote: remote,
+		client: client,
+		local:  newLRU(opts.Size, now),
+		opts:   opts,
+		origin: hex.EncodeToString(buf[:]),
+		stop:   make(chan struct{}),
+	}
+}
+
+// Start subscribes to invalidations and waits until the subscription is
+// active or ctx ends. An error means Redis could not be reached in time; the
+// subscription keeps retrying in the background and the local tier starts
+// caching once it is established.
+func (c *TieredArticleCache) Start(ctx context.Context) error {
+	pubsub := c.client.Subscribe(context.Background(), c.opts.Channel)
+	subscribed := make(chan struct{})
+
+	c.done.Add(1)
+	go func() {
+		defer c.done.Done()
+		<-c.stop
+		pubsub.Close()
+	}()
+
+	c.done.Add(1)
+	go func() {
+		defer c.done.Done()
+		c.listen(pubsub, subscribed)
+	}()
+
+	select {
+	case <-subscribed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops receiving invalidations.
+func (c *TieredArticleCache) Close() {
+	select {
+	case <-c.stop:
+	default:
+		close(c.stop)
+	}
+	c.done.Wait()
+}
+
+func (c *TieredArticleCache) listen(pubsub *redis.PubSub, subscribed chan struct{}) {
+	const minBackoff, maxBackoff = 100 * time.Millisecond, 5 * time.Second
+	backoff := minBackoff
+	failing := false
+	for {
+		msg, err := pubsub.Receive(context.Background())
+		if err != nil {
+			select {
+			case <-c.stop:
+				return
+			default:
+			}
+			// go-redis reconnects on the next Receive. Messages may have been
+			// missed in between, so nothing local can be trusted, and nothing
+			// is cached locally until the subscription is back.
+			c.subscribed.Store(false)
+			c.evictAll()
+			if !failing {
+				// Reported once per outage, not on every retry.
+				c.opts.OnError(fmt.Errorf("cache invalidation subscription: %w", err))
+				failing = true
+			}
+			select {
+			case <-c.stop:
+				return
+			case <-time.After(backoff):
+			}
+			backoff = min(backoff*2, maxBackoff)
+			continue
+		}
+		backoff = minBackoff
+
+		switch m := msg.(type) {
+		case *redis.Subscription:
+			if m.Kind == "subscribe" {
+				failing = false
+				c.evictAll() // (re)subscribed; earlier messages may be lost
+				if !c.subscribed.Swap(true) && subscribed != nil {
+					close(subscribed)
+					subscribed = nil
+				}
+			}
+		case *redis.Message:
+			origin, key, ok := strings.Cut(m.Payload, " ")
+			if ok && origin != c.origin {
+				c.evict(key)
+			}
+		}
+	}
+}
+
+func (c *TieredArticleCache) GetArticle(ctx context.Context, id uint) (*repository.ArticleCacheEntry, error) {
+	key := fmt.Sprintf("article:%d", id)
+	if v, ok := c.local.get(key); ok {
+		c.localStats.record(true)
+		return cloneEntry(v.(*repository.ArticleCacheEntry)), nil
+	}
+	c.localStats.record(false)
+
+	epoch := c.epoch.Load()
+	entry, err := c.remote.GetArticle(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	c.remoteStats.record(entry != nil)
+	if entry != nil {
+		c.fill(epoch, key, cloneEntry(entry), c.opts.TTL)
+	}
+	return entry, nil
+}
+
+func (c *TieredArticleCache) SetArticle(ctx context.Context, id uint, entry *repository.ArticleCacheEntry, expiration time.Duration) error {
+	// Taken first, so an invalidation received while the remote tier stores
+	// the entry keeps it out of the local tier.
+	epoch := c.epoch.Load()
+	if err := c.remote.SetArticle(ctx, id, entry, expiration); err != nil {
+		return err
+	}
+	key := fmt.Sprintf("article:%d", id)
+	c.fill(epoch, key, cloneEntry(entry), c.localTTL(expiration))
+	c.publish(ctx, key)
+	return nil
+}
+
+// ArticleGeneration is always read from the remote tier, which every
+// instance's invalidations advance.
+func (c *TieredArticleCache) ArticleGeneration(ctx context.Context, id uint) (int64, error) {
+	return c.remote.ArticleGeneration(ctx, id)
+}
+
+func (c *TieredArticleCache) GetArticles(ctx context.Context, key string) ([]*domain.Article, error) {
+	localKey := invalidateLists + ":" + key
+	if v, ok := c.local.get(localKey); ok {
+		c.localStats.record(true)
+		return cloneArticles(v.([]*domain.Article)), nil
+	}
+	c.localStats.record(false)
+
+	epoch := c.epoch.Load()
+	articles, err := c.remote.GetArticles(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	c.remoteStats.record(articles != nil)
+	if articles != nil {
+		c.fill(epoch, localKey, cloneArticles(articles), c.opts.TTL)
+	}
+	return articles, nil
+}
+
+func (c *TieredArticleCache) SetArticles(ctx context.Context, key string, articles []*domain.Article, generation int64, expiration time.Duration) error {
+	// Taken first, as in SetArticle.
+	epoch := c.epoch.Load()
+	if err := c.remote.SetArticles(ctx, key, articles, generation, expiration); err != nil {
+		return err
+	}
+	// Lists are only replaced after InvalidateArticleLists, which already
+	// told every instance, so there is nothing to broadcast here.
+	c.fill(epoch, invalidateLists+":"+key, cloneArticles(articles), c.localTTL(expiration))
+	return nil
+}
+
+// ListGeneration is always read from the remote tier, like ArticleGeneration.
+func (c *TieredArticleCache) ListGeneration(ctx context.Context) (int64, error) {
+	return c.remote.ListGeneration(ctx)
+}
+
+func (c *TieredArticleCache) DeleteArticle(ctx context.Context, id uint) error {
+	key := fmt.Sprintf("article:%d", id)
+	c.evict(key)
+	if err := c.remote.DeleteArticle(ctx, id); err != nil {
+		return err
+	}
+	c.publish(ctx, key)
+	return nil
+}
+
+func (c *TieredArticleCache) InvalidateArticleLists(ctx context.Context) error {
+	c.evict(invalidateLists)
+	if err := c.remote.InvalidateArticleLists(ctx); err != nil {
+		return err
+	}
+	c.publish(ctx, invalidateLists)
+	return nil
+}
+
+// Stats reports the hit ratio of each tier.
+func (c *TieredArticleCache) Stats() contracts.CacheStats {
+	return contracts.CacheStats{
+		Local:        c.localStats.stats(),
+		Remote:       c.remoteStats.stats(),
+		LocalEntries: c.local.len(),
+	}
+}
+
+// publish tells the other instances to evict key. The write itself already
+// succeeded, so a failure is only reported; the other instances' copies then
+// stay stale for at most the local TTL. Nothing is sent while this instance
+// has lost its own subscription, since Redis is unreachable from here then.
+func (c *TieredArticleCache) publish(ctx context.Context, key string) {
+	if !c.subscribed.Load() {
+		return
+	}
+	if err := c.client.Publish(ctx, c.opts.Channel, c.origin+" "+key).Err(); err != nil {
+		c.opts.OnError(fmt.Errorf("publish cache invalidation: %w", err))
+	}
+}
+
+// evict drops key from the local tier; the lists key drops every list.
+func (c *TieredArticleCache) evict(key string) {
+	c.evictMu.Lock()
+	defer c.evictMu.Unlock()
+	c.epoch.Add(1)
+	if key == invalidateLists {
+		c.local.removeMatching(func(k string) bool { return strings.HasPrefix(k, invalidateLists+":") })
+		return
+	}
+	c.local.remove(key)
+}
+
+func (c *TieredArticleCache) evictAll() {
+	c.evictMu.Lock()
+	defer c.evictMu.Unlock()
+	c.epoch.Add(1)
+	c.local.removeMatching(func(string) bool { return true })
+}
+
+// fill stores a value read from the remote tier unless an eviction happened
+// since epoch was taken.
+func (c *TieredArticleCache) fill(epoch uint64, key string, value interface{}, ttl time.Duration) {
+	c.evictMu.Lock()
+	defer c.evictMu.Unlock()
+	if c.epoch.Load() == epoch && c.subscribed.Load() {
+		c.local.set(key, value, ttl)
+	}
+}
+
+// localTTL is the remote expiration capped by the local TTL.
+func (c *TieredArticleCache) localTTL(expiration time.Duration) time.Duration {
+	if expiration > 0 && expiration < c.opts.TTL {
+		return expiration
+	}
+	return c.opts.TTL
+}
+
+func cloneArticle(a *domain.Article) *domain.Article {
+	if a == nil {
+		return nil
+	}
+	c := *a
+	c.Tags = slices.Clone(a.Tags)
+	return &c
+}
+
+func cloneEntry(e *repository.ArticleCacheEntry) *repository.ArticleCacheEntry {
+	c := *e
+	c.Article = cloneArticle(e.Article)
+	return &c
+}
+
+func cloneArticles(articles []*domain.Article) []*domain.Article {
+	c := make([]*domain.Article, len(articles))
+	for i, a := range articles {
+		c[i] = cloneArticle(a)
+	}
+	return c
+}
@@ -0,0 +1,41 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// writtenPrefix namespaces the keys of RedisWriteTracker.
+const writtenPrefix = "written:"
+
+// RedisWriteTracker 在 Redis 中记录最近写入的键，键随读己之写窗口过期，
+// 使写入后的读取无论落在哪个实例上都走主库。
+type RedisWriteTracker struct {
+	redisClient *redis.Client
+}
+
+// NewRedisWriteTracker 创建基于 Redis 的写入记录
+func NewRedisWriteTracker(redisClient *redis.Client) *RedisWriteTracker {
+	return &RedisWriteTracker{redisClient: redisClient}
+}
+
+func (t *RedisWriteTracker) MarkWritten(ctx context.Context, window time.Duration, keys ...string) error {
+	_, err := t.redisClient.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range keys {
+			pipe.Set(ctx, writtenPrefix+key, 1, window)
+		}
+		return nil
+	})
+	return err
+}
+
+func (t *RedisWriteTracker) RecentlyWritten(ctx context.Context, keys ...string) (bool, error) {
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = writtenPrefix + key
+	}
+	n, err := t.redisClient.Exists(ctx, prefixed...).Result()
+	return n > 0, err
+}
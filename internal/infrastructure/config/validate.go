@@ -0,0 +1,136 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// minJWTSecretLength is the shortest accepted HMAC secret.
+const minJWTSecretLength = 16
+
+// Validate checks the configuration and reports every problem found,
+// joined into a single error.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.Server.ReadTimeoutSeconds >= 0, "server.read_timeout_seconds must not be negative")
+	check(c.Server.ReadHeaderTimeoutSeconds >= 0, "server.read_header_timeout_seconds must not be negative")
+	check(c.Server.WriteTimeoutSeconds >= 0, "server.write_timeout_seconds must not be negative")
+	check(c.Server.IdleTimeoutSeconds >= 0, "server.idle_timeout_seconds must not be negative")
+	check(c.Server.MaxHeaderBytes >= 0, "server.max_header_bytes must not be negative")
+	check(c.Server.ShutdownTimeoutSeconds >= 0, "server.shutdown_timeout_seconds must not be negative")
+	check(c.Server.DrainDelaySeconds >= 0, "server.drain_delay_seconds must not be negative")
+	for _, proxy := range c.Server.TrustedProxies {
+		_, _, cidrErr := net.ParseCIDR(proxy)
+		check(cidrErr == nil || net.ParseIP(proxy) != nil,
+			"server.trusted_proxies entry %q is neither an IP nor a CIDR", proxy)
+	}
+
+	switch c.Storage {
+	case "", StorageDatabase:
+		c.validateDatabase(check)
+	case StorageMemory:
+	default:
+		check(false, "storage must be %s or %s, got %q", StorageDatabase, StorageMemory, c.Storage)
+	}
+
+	check(c.JWT.Secret != "", "jwt.secret is required")
+	check(c.JWT.Secret == "" || len(c.JWT.Secret) >= minJWTSecretLength,
+		"jwt.secret must be at least %d characters", minJWTSecretLength)
+	check(c.JWT.ExpiresInMinutes > 0, "jwt.expires_in_minutes must be positive")
+
+	if c.Logger.Level != "" {
+		var level zapcore.Level
+		check(level.UnmarshalText([]byte(c.Logger.Level)) == nil, "logger.level %q is not a valid level", c.Logger.Level)
+	}
+	check(c.Logger.Encoding == "" || c.Logger.Encoding == "json" || c.Logger.Encoding == "console",
+		"logger.encoding must be json or console, got %q", c.Logger.Encoding)
+
+	if c.Tracing.Enabled {
+		switch c.Tracing.Exporter {
+		case "", "otlp", "stdout", "memory":
+		default:
+			check(false, "tracing.exporter must be otlp, stdout or memory, got %q", c.Tracing.Exporter)
+		}
+	}
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "tracing.sample_ratio must be between 0 and 1")
+
+	check(c.Cache.ArticleTTLSeconds >= 0, "cache.article_ttl_seconds must not be negative")
+	check(c.Cache.ArticleStaleSeconds >= 0, "cache.article_stale_seconds must not be negative")
+	check(c.Cache.NotFoundTTLSeconds >= 0, "cache.not_found_ttl_seconds must not be negative")
+	check(c.Cache.ListTTLSeconds >= 0, "cache.list_ttl_seconds must not be negative")
+	check(c.Cache.LockTTLMS >= 0, "cache.lock_ttl_ms must not be negative")
+	check(c.Cache.LockWaitMS >= 0, "cache.lock_wait_ms must not be negative")
+	check(c.Cache.LocalSize >= 0, "cache.local_size must not be negative")
+	check(c.Cache.LocalTTLSeconds >= 0, "cache.local_ttl_seconds must not be negative")
+	check(c.Cache.FallbackTTLSeconds >= 0, "cache.fallback_ttl_seconds must not be negative")
+
+	for group, p := range c.RateLimit.Policies {
+		check(p.Limit >= 0, "rate_limit.policies.%s.limit must not be negative", group)
+		check(p.Limit == 0 || p.WindowSeconds > 0, "rate_limit.policies.%s.window_seconds must be positive", group)
+		switch p.KeyBy {
+		case "", "ip", "user", "token":
+		default:
+			check(false, "rate_limit.policies.%s.key_by must be ip, user or token, got %q", group, p.KeyBy)
+		}
+	}
+
+	check(c.Idempotency.TTLSeconds >= 0, "idempotency.ttl_seconds must not be negative")
+	check(c.Idempotency.LockTTLSeconds >= 0, "idempotency.lock_ttl_seconds must not be negative")
+
+	check(c.GraphQL.MaxDepth >= 0, "graphql.max_depth must not be negative")
+	check(c.GraphQL.MaxComplexity >= 0, "graphql.max_complexity must not be negative")
+
+	check(!c.GRPC.Enabled || c.GRPC.Addr == "" || c.GRPC.Addr != c.Server.Addr,
+		"grpc.addr must differ from server.addr, both are %q", c.Server.Addr)
+	check((c.GRPC.TLS.CertFile == "") == (c.GRPC.TLS.KeyFile == ""),
+		"grpc.tls.cert_file and grpc.tls.key_file must be set together")
+
+	check(c.Health.CheckTimeoutMS >= 0, "health.check_timeout_ms must not be negative")
+	check(c.Health.CacheTTLMS >= 0, "health.cache_ttl_ms must not be negative")
+	check(c.Health.DiskMinFreeMB >= 0, "health.disk_min_free_mb must not be negative")
+
+	return errors.Join(errs...)
+}
+
+// validateDatabase checks the settings only needed when a database and Redis
+// back the storage.
+func (c *Config) validateDatabase(check func(ok bool, format string, args ...interface{})) {
+	switch c.Database.Driver {
+	case "", "mysql", "postgres":
+		check(c.Database.Host != "", "database.host is required")
+		check(c.Database.Port != "", "database.port is required")
+		check(c.Database.User != "", "database.user is required")
+	case "sqlite":
+	default:
+		check(false, "database.driver must be mysql, postgres or sqlite, got %q", c.Database.Driver)
+	}
+	check(c.Database.DBName != "", "database.dbname is required")
+	check(c.Database.TxMaxRetries >= 0, "database.tx_max_retries must not be negative")
+	check(c.Database.QueryTimeoutMS >= 0, "database.query_timeout_ms must not be negative")
+	for op, ms := range c.Database.QueryTimeoutsMS {
+		check(ms >= 0, "database.query_timeouts_ms.%s must not be negative", op)
+	}
+	check(c.Database.ReplicaCheckIntervalMS >= 0, "database.replica_check_interval_ms must not be negative")
+	check(c.Database.ReplicaMaxFailures >= 0, "database.replica_max_failures must not be negative")
+	check(c.Database.ReadYourWritesMS >= 0, "database.read_your_writes_ms must not be negative")
+	if len(c.Database.Replicas) > 0 {
+		check(c.Database.Driver != "sqlite", "database.replicas are not supported with sqlite")
+	}
+	for i, r := range c.Database.Replicas {
+		check(r.Host != "", "database.replicas[%d].host is required", i)
+	}
+
+	check(c.Redis.Addr != "", "redis.addr is required")
+	check(c.Redis.TimeoutMS >= 0, "redis.timeout_ms must not be negative")
+	check(c.Redis.BreakerFailures >= 0, "redis.breaker_failures must not be negative")
+	check(c.Redis.BreakerOpenMS >= 0, "redis.breaker_open_ms must not be negative")
+}
@@ -1,17 +1,65 @@
 package config
 
 import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
 	"github.com/spf13/viper"
 )
 
+// EnvPrefix is the prefix for environment overrides. Nested keys map to
+// upper-case names with dots replaced by underscores, e.g. database.password
+// is read from BLOG_DATABASE_PASSWORD. Appending _FILE to the name reads the
+// value from the referenced file instead, which is how secrets are mounted.
+const EnvPrefix = "BLOG"
+
+// Storage backends selectable with the storage key.
+const (
+	StorageDatabase = "database"
+	StorageMemory   = "memory"
+)
+
 // Config holds all configuration for the application
 type Config struct {
+	// Storage 选择持久化与缓存的实现：database（默认，使用数据库和 Redis）或 memory
+	Storage string `mapstructure:"storage"`
+
+	Server struct {
+		Addr                     string `mapstructure:"addr"`
+		ReadTimeoutSeconds       int    `mapstructure:"read_timeout_seconds"`
+		ReadHeaderTimeoutSeconds int    `mapstructure:"read_header_timeout_seconds"`
+		WriteTimeoutSeconds      int    `mapstructure:"write_timeout_seconds"`
+		IdleTimeoutSeconds       int    `mapstructure:"idle_timeout_seconds"`
+		MaxHeaderBytes           int    `mapstructure:"max_header_bytes"`
+		ShutdownTimeoutSeconds   int    `mapstructure:"shutdown_timeout_seconds"`
+		DrainDelaySeconds        int    `mapstructure:"drain_delay_seconds"`
+		// TrustedProxies 是可信反向代理的 IP 或 CIDR，只有来自它们的请求才采信
+		// X-Forwarded-For 等头部中的客户端地址；为空表示不信任任何代理
+		TrustedProxies []string `mapstructure:"trusted_proxies"`
+		// DebugEndpoints 开启 /debug/ 下的运维接口（如缓存命中率）；这些接口不做认证，
+		// 只应在内部部署或前置访问控制时开启
+		DebugEndpoints bool `mapstructure:"debug_endpoints"`
+	} `mapstructure:"server"`
 	Database struct {
+		Driver   string `mapstructure:"driver"`
 		Host     string `mapstructure:"host"`
 		Port     string `mapstructure:"port"`
 		User     string `mapstructure:"user"`
 		Password string `mapstructure:"password"`
 		DBName   string `mapstructure:"dbname"`
+		SSLMode  string `mapstructure:"sslmode"`
+		// TxMaxRetries 是事务遇到死锁或序列化失败时的最大重试次数
+		TxMaxRetries int `mapstructure:"tx_max_retries"`
+		// QueryTimeoutMS 是仓库操作的默认查询超时，QueryTimeoutsMS 按操作名（如 user.find_by_email）覆盖
+		QueryTimeoutMS  int            `mapstructure:"query_timeout_ms"`
+		QueryTimeoutsMS map[string]int `mapstructure:"query_timeouts_ms"`
+		// Replicas 是只读副本；未填写的字段沿用主库的配置
+		Replicas               []ReplicaConfig `mapstructure:"replicas"`
+		ReplicaCheckIntervalMS int             `mapstructure:"replica_check_interval_ms"`
+		ReplicaMaxFailures     int             `mapstructure:"replica_max_failures"`
+		ReadYourWritesMS       int             `mapstructure:"read_your_writes_ms"`
 	} `mapstructure:"database"`
 	JWT struct {
 		Secret           string `mapstructure:"secret"`
@@ -28,17 +76,114 @@ type Config struct {
 			Compress   bool   `mapstructure:"compress"`
 		} `mapstructure:"file"`
 	} `mapstructure:"logger"`
+	Redis struct {
+		Addr     string `mapstructure:"addr"`
+		Password string `mapstructure:"password"`
+		DB       int    `mapstructure:"db"`
+		// TimeoutMS 限制连接与读写耗时；熔断器在连续 BreakerFailures 次失败后打开 BreakerOpenMS
+		TimeoutMS       int `mapstructure:"timeout_ms"`
+		BreakerFailures int `mapstructure:"breaker_failures"`
+		BreakerOpenMS   int `mapstructure:"breaker_open_ms"`
+	} `mapstructure:"redis"`
+	// Cache 控制文章缓存的过期时间，0 表示使用默认值
+	Cache struct {
+		ArticleTTLSeconds   int `mapstructure:"article_ttl_seconds"`
+		ArticleStaleSeconds int `mapstructure:"article_stale_seconds"`
+		NotFoundTTLSeconds  int `mapstructure:"not_found_ttl_seconds"`
+		ListTTLSeconds      int `mapstructure:"list_ttl_seconds"`
+		LockTTLMS           int `mapstructure:"lock_ttl_ms"`
+		LockWaitMS          int `mapstructure:"lock_wait_ms"`
+		// LocalSize 大于 0 时在 Redis 前启用进程内 LRU 缓存，失效消息经 InvalidationChannel 广播
+		LocalSize           int    `mapstructure:"local_size"`
+		LocalTTLSeconds     int    `mapstructure:"local_ttl_seconds"`
+		InvalidationChannel string `mapstructure:"invalidation_channel"`
+		// FallbackTTLSeconds 是 Redis 不可用期间内存降级缓存条目的最长存活时间
+		FallbackTTLSeconds int `mapstructure:"fallback_ttl_seconds"`
+	} `mapstructure:"cache"`
 	AuditLog struct {
 		File string `mapstructure:"file"`
 	} `mapstructure:"audit_log"`
+	Tracing struct {
+		Enabled      bool    `mapstructure:"enabled"`
+		ServiceName  string  `mapstructure:"service_name"`
+		Exporter     string  `mapstructure:"exporter"` // otlp, stdout or memory
+		OTLPEndpoint string  `mapstructure:"otlp_endpoint"`
+		Insecure     bool    `mapstructure:"insecure"`
+		SampleRatio  float64 `mapstructure:"sample_ratio"`
+	} `mapstructure:"tracing"`
+	// RateLimit 按路由组（auth、read、write）限流；ExemptUserIDs（管理员）与携带 TrustedScopes 的令牌不受限制，修改后无需重启
+	RateLimit struct {
+		Enabled       bool                       `mapstructure:"enabled"`
+		ExemptUserIDs []int64                    `mapstructure:"exempt_user_ids"`
+		TrustedScopes []string                   `mapstructure:"trusted_scopes"`
+		Policies      map[string]RateLimitPolicy `mapstructure:"policies"`
+	} `mapstructure:"rate_limit"`
+	// Idempotency 控制 Idempotency-Key：成功响应保留 TTLSeconds，处理中的请求最多占用键 LockTTLSeconds
+	Idempotency struct {
+		TTLSeconds     int `mapstructure:"ttl_seconds"`
+		LockTTLSeconds int `mapstructure:"lock_ttl_seconds"`
+	} `mapstructure:"idempotency"`
+	// OpenAPI 按 api/openapi.yaml 校验请求；ValidateResponses 会缓冲并校验所有响应，仅在 gin 测试模式（GIN_MODE=test）下生效
+	OpenAPI struct {
+		ValidateRequests  bool `mapstructure:"validate_requests"`
+		ValidateResponses bool `mapstructure:"validate_responses"`
+	} `mapstructure:"openapi"`
+	// HTTPCache 设置公开文章读取接口的 Cache-Control 与代理缓存键响应头，为空时不发送；ETag 与 Last-Modified 始终发送
+	HTTPCache struct {
+		Article            string `mapstructure:"article"`
+		List               string `mapstructure:"list"`
+		SurrogateKeyHeader string `mapstructure:"surrogate_key_header"`
+	} `mapstructure:"http_cache"`
+	// GraphQL 配置 /graphql 接口；MaxDepth 与 MaxComplexity 限制单个查询的嵌套深度与估算复杂度，为 0 时不限制
+	GraphQL struct {
+		Enabled       bool `mapstructure:"enabled"`
+		MaxDepth      int  `mapstructure:"max_depth"`
+		MaxComplexity int  `mapstructure:"max_complexity"`
+	} `mapstructure:"graphql"`
+	// GRPC 配置 gRPC 服务（文章与用户），监听与 HTTP 服务不同的端口；
+	// 未配置 TLS 证书时以明文提供服务
+	GRPC struct {
+		Enabled bool   `mapstructure:"enabled"`
+		Addr    string `mapstructure:"addr"`
+		TLS     struct {
+			CertFile string `mapstructure:"cert_file"`
+			KeyFile  string `mapstructure:"key_file"`
+		} `mapstructure:"tls"`
+	} `mapstructure:"grpc"`
+	Health struct {
+		CheckTimeoutMS int `mapstructure:"check_timeout_ms"`
+		CacheTTLMS     int `mapstructure:"cache_ttl_ms"`
+		DiskMinFreeMB  int `mapstructure:"disk_min_free_mb"`
+	} `mapstructure:"health"`
 }
 
-// LoadConfig reads configuration from file or environment variables.
+// ReplicaConfig describes one read replica of the primary database.
+type ReplicaConfig struct {
+	Name     string `mapstructure:"name"`
+	Host     string `mapstructure:"host"`
+	Port     string `mapstructure:"port"`
+	User     string `mapstructure:"user"`
+	Password string `mapstructure:"password"`
+	DBName   string `mapstructure:"dbname"`
+}
+
+// RateLimitPolicy allows Limit requests per WindowSeconds for each caller
+// of a route group, told apart by KeyBy: ip (default), user or token.
+type RateLimitPolicy struct {
+	Limit         int    `mapstructure:"limit"`
+	WindowSeconds int    `mapstructure:"window_seconds"`
+	KeyBy         string `mapstructure:"key_by"`
+}
+
+// LoadConfig reads configuration from file or environment variables and
+// validates the result.
 func LoadConfig(path string) (config Config, err error) {
 	viper.AddConfigPath(path)
 	viper.SetConfigName("config")
 	viper.SetConfigType("yaml")
 
+	viper.SetEnvPrefix(EnvPrefix)
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	viper.AutomaticEnv()
 
 	err = viper.ReadInConfig()
@@ -46,6 +191,66 @@ func LoadConfig(path string) (config Config, err error) {
 		return
 	}
 
-	err = viper.Unmarshal(&config)
+	keys := configKeys(reflect.TypeOf(config), "")
+	for _, key := range keys {
+		// AutomaticEnv only applies to keys viper already knows about, so
+		// bind every key explicitly to allow overriding keys missing from the file.
+		if err = viper.BindEnv(key); err != nil {
+			return
+		}
+	}
+	if err = applySecretFiles(keys); err != nil {
+		return
+	}
+
+	config, err = unmarshal()
 	return
-}
\ No newline at end of file
+}
+
+// unmarshal decodes the current viper state and validates it.
+func unmarshal() (config Config, err error) {
+	if err = viper.Unmarshal(&config); err != nil {
+		return
+	}
+	err = config.Validate()
+	return
+}
+
+// configKeys lists the dotted keys of every leaf field in t.
+func configKeys(t reflect.Type, prefix string) []string {
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key := field.Tag.Get("mapstructure")
+		if key == "" {
+			continue
+		}
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if field.Type.Kind() == reflect.Struct {
+			keys = append(keys, configKeys(field.Type, key)...)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// applySecretFiles sets each key whose <ENV>_FILE variable is present to
+// the contents of that file.
+func applySecretFiles(keys []string) error {
+	for _, key := range keys {
+		name := EnvPrefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_")) + "_FILE"
+		path, ok := os.LookupEnv(name)
+		if !ok || path == "" {
+			continue
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", name, err)
+		}
+		viper.Set(key, strings.TrimRight(string(content), "\r\n"))
+	}
+	return nil
+}
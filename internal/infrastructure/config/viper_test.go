@@ -0,0 +1,166 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfigYAML = `
+database:
+  host: "127.0.0.1"
+  port: "3306"
+  user: "root"
+  password: "from-file"
+  dbname: "miniblog"
+jwt:
+  secret: "a-sufficiently-long-secret"
+  expires_in_minutes: 60
+logger:
+  level: "info"
+redis:
+  addr: "127.0.0.1:6379"
+`
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(content), 0o600))
+	return dir
+}
+
+func TestLoadConfig_EnvOverrides(t *testing.T) {
+	viper.Reset()
+	dir := writeConfig(t, testConfigYAML)
+
+	secretFile := filepath.Join(t.TempDir(), "jwt_secret")
+	require.NoError(t, os.WriteFile(secretFile, []byte("secret-from-mounted-file\n"), 0o600))
+
+	t.Setenv("BLOG_DATABASE_PASSWORD", "from-env")
+	t.Setenv("BLOG_JWT_SECRET_FILE", secretFile)
+	t.Setenv("BLOG_SERVER_ADDR", ":9090")
+
+	cfg, err := LoadConfig(dir)
+
+	require.NoError(t, err)
+	assert.Equal(t, "from-env", cfg.Database.Password)
+	assert.Equal(t, "secret-from-mounted-file", cfg.JWT.Secret)
+	assert.Equal(t, ":9090", cfg.Server.Addr)
+}
+
+func TestLoadConfig_ReportsEveryProblem(t *testing.T) {
+	viper.Reset()
+	dir := writeConfig(t, `
+database:
+  host: "127.0.0.1"
+jwt:
+  secret: ""
+logger:
+  level: "loud"
+server:
+  addr: ":8080"
+  trusted_proxies: ["10.0.0.0/8", "proxy.internal"]
+grpc:
+  enabled: true
+  addr: ":8080"
+  tls:
+    cert_file: "server.pem"
+`)
+
+	_, err := LoadConfig(dir)
+
+	require.Error(t, err)
+	for _, problem := range []string{
+		"database.port is required",
+		"database.user is required",
+		"database.dbname is required",
+		"jwt.secret is required",
+		"jwt.expires_in_minutes must be positive",
+		`logger.level "loud" is not a valid level`,
+		"redis.addr is required",
+		`grpc.addr must differ from server.addr, both are ":8080"`,
+		"grpc.tls.cert_file and grpc.tls.key_file must be set together",
+		`server.trusted_proxies entry "proxy.internal" is neither an IP nor a CIDR`,
+	} {
+		assert.Contains(t, err.Error(), problem)
+	}
+}
+
+func TestLoadConfig_MemoryStorageNeedsNoDatabase(t *testing.T) {
+	viper.Reset()
+	dir := writeConfig(t, `
+storage: "memory"
+jwt:
+  secret: "a-sufficiently-long-secret"
+  expires_in_minutes: 60
+`)
+
+	cfg, err := LoadConfig(dir)
+
+	require.NoError(t, err)
+	assert.Equal(t, StorageMemory, cfg.Storage)
+
+	viper.Reset()
+	t.Setenv("BLOG_STORAGE", "floppy")
+	_, err = LoadConfig(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `storage must be database or memory, got "floppy"`)
+}
+
+func TestLoadConfig_Replicas(t *testing.T) {
+	viper.Reset()
+	dir := writeConfig(t, strings.Replace(testConfigYAML, "database:\n", `database:
+  replicas:
+    - name: "read-1"
+      host: "10.0.0.2"
+    - port: "3307"
+`, 1))
+
+	_, err := LoadConfig(dir)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "database.replicas[1].host is required")
+	assert.NotContains(t, err.Error(), "database.replicas[0]")
+}
+
+func TestLoadConfig_RateLimitPolicies(t *testing.T) {
+	viper.Reset()
+	dir := writeConfig(t, testConfigYAML+`
+rate_limit:
+  enabled: true
+  exempt_user_ids: [1]
+  policies:
+    auth:
+      limit: 10
+      window_seconds: 60
+    write:
+      limit: 5
+      key_by: "session"
+`)
+
+	_, err := LoadConfig(dir)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "rate_limit.policies.write.window_seconds must be positive")
+	assert.Contains(t, err.Error(), `rate_limit.policies.write.key_by must be ip, user or token, got "session"`)
+	assert.NotContains(t, err.Error(), "rate_limit.policies.auth")
+}
+
+func TestRestartRequired(t *testing.T) {
+	var running Config
+	running.Logger.Level = "info"
+	running.Database.Host = "db-1"
+
+	next := running
+	next.Logger.Level = "debug"
+	next.RateLimit.Policies = map[string]RateLimitPolicy{"auth": {Limit: 5, WindowSeconds: 60}}
+	assert.Empty(t, restartRequired(running, next))
+
+	next.Database.Host = "db-2"
+	assert.Equal(t, []string{"database"}, restartRequired(running, next))
+}
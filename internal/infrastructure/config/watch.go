@@ -0,0 +1,55 @@
+package config
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+// applyReloadable copies the keys that are safe to change at runtime
+// from src into dst.
+func applyReloadable(dst *Config, src Config) {
+	dst.Logger.Level = src.Logger.Level
+	dst.RateLimit = src.RateLimit
+}
+
+// restartRequired lists the top-level sections that differ between the
+// running and the new configuration once reloadable keys are ignored.
+func restartRequired(running, next Config) []string {
+	applyReloadable(&running, next)
+
+	var sections []string
+	rv, nv := reflect.ValueOf(running), reflect.ValueOf(next)
+	for i := 0; i < rv.NumField(); i++ {
+		if !reflect.DeepEqual(rv.Field(i).Interface(), nv.Field(i).Interface()) {
+			sections = append(sections, rv.Type().Field(i).Tag.Get("mapstructure"))
+		}
+	}
+	return sections
+}
+
+// Watch reloads the configuration file whenever it changes. Only the
+// reloadable keys (logger.level and rate_limit) take effect: onReload receives
+// the running configuration with those keys updated, plus the sections whose
+// changes were ignored until the next restart. A change that fails
+// validation is passed to onError and discarded.
+func Watch(running Config, onReload func(cfg Config, restartRequired []string), onError func(error)) {
+	var mu sync.Mutex
+	viper.OnConfigChange(func(fsnotify.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		next, err := unmarshal()
+		if err != nil {
+			onError(err)
+			return
+		}
+
+		restart := restartRequired(running, next)
+		applyReloadable(&running, next)
+		onReload(running, restart)
+	})
+	viper.WatchConfig()
+}
@@ -0,0 +1,53 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"gorm.io/gorm"
+)
+
+// NewDBChecker pings the database behind a *gorm.DB.
+func NewDBChecker(name string, db *gorm.DB) Checker {
+	return NewCheckerFunc(name, func(ctx context.Context) error {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.PingContext(ctx)
+	})
+}
+
+// NewDiskChecker fails when the filesystem holding path has less than
+// minFreeBytes available.
+func NewDiskChecker(name, path string, minFreeBytes uint64) Checker {
+	return NewCheckerFunc(name, func(ctx context.Context) error {
+		free, err := freeBytes(existingAncestor(path))
+		if err != nil {
+			return err
+		}
+		if free < minFreeBytes {
+			return fmt.Errorf("only %d bytes free on %s, need %d", free, path, minFreeBytes)
+		}
+		return nil
+	})
+}
+
+// existingAncestor returns path or its nearest parent that exists, so the
+// check works before the log directory has been created.
+func existingAncestor(path string) string {
+	for {
+		if _, err := os.Stat(path); !errors.Is(err, fs.ErrNotExist) {
+			return path
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return path
+		}
+		path = parent
+	}
+}
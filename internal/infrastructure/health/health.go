@@ -0,0 +1,192 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Status values reported by checks and by the readiness report.
+const (
+	StatusOK       = "ok"
+	StatusFail     = "fail"
+	StatusDraining = "draining"
+	// StatusDegraded marks a failed optional check; the process stays ready.
+	StatusDegraded = "degraded"
+)
+
+// Checker is a single readiness dependency check.
+type Checker interface {
+	Name() string
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc adapts a plain function to the Checker interface.
+type CheckerFunc struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// NewCheckerFunc creates a Checker from a name and a function.
+func NewCheckerFunc(name string, fn func(ctx context.Context) error) Checker {
+	return CheckerFunc{name: name, fn: fn}
+}
+
+// Name returns the check name.
+func (c CheckerFunc) Name() string { return c.name }
+
+// Check runs the check function.
+func (c CheckerFunc) Check(ctx context.Context) error { return c.fn(ctx) }
+
+type optionalChecker struct {
+	Checker
+}
+
+// NewOptionalChecker wraps a check on a dependency the service can run
+// without. Its failures are reported as degraded and do not make the process
+// unready.
+func NewOptionalChecker(c Checker) Checker {
+	return optionalChecker{c}
+}
+
+// CheckResult is the outcome of a single check.
+type CheckResult struct {
+	Status     string `json:"status"`
+	DurationMS int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Report is the aggregated readiness result.
+type Report struct {
+	Status    string                 `json:"status"`
+	Checks    map[string]CheckResult `json:"checks"`
+	CheckedAt time.Time              `json:"checked_at"`
+}
+
+// Ready reports whether every check passed and the process is not draining.
+func (r Report) Ready() bool {
+	return r.Status == StatusOK
+}
+
+// Service runs readiness checks with a per-check timeout and caches the
+// result for a short period so probes don't hammer the dependencies.
+type Service struct {
+	checkers []Checker
+	timeout  time.Duration
+	cacheTTL time.Duration
+	draining atomic.Bool
+
+	// runs merges concurrent probes into one run of the checks; mu only
+	// guards cached, so probes never queue behind a slow check.
+	runs   singleflight.Group
+	mu     sync.Mutex
+	cached *Report
+}
+
+// NewService creates a new health Service.
+func NewService(timeout, cacheTTL time.Duration, checkers ...Checker) *Service {
+	if timeout <= 0 {
+		timeout = 2 * time.Second
+	}
+	return &Service{
+		checkers: checkers,
+		timeout:  timeout,
+		cacheTTL: cacheTTL,
+	}
+}
+
+// Register adds a checker. It must be called before the service is used.
+func (s *Service) Register(c Checker) {
+	s.checkers = append(s.checkers, c)
+}
+
+// SetDraining marks the process as shutting down, which makes it not ready.
+func (s *Service) SetDraining(draining bool) {
+	s.draining.Store(draining)
+}
+
+// Draining reports whether the process is shutting down.
+func (s *Service) Draining() bool {
+	return s.draining.Load()
+}
+
+// Readiness runs all checks, or returns the cached report if it is still fresh.
+// The report is shared by every probe, so checks run detached from ctx and
+// are bounded only by the per-check timeout; a probe that gives up early
+// cannot make the dependencies look down for the next ones.
+func (s *Service) Readiness(ctx context.Context) Report {
+	if s.Draining() {
+		return Report{Status: StatusDraining, Checks: map[string]CheckResult{}, CheckedAt: time.Now()}
+	}
+
+	s.mu.Lock()
+	cached := s.cached
+	s.mu.Unlock()
+	if cached != nil && time.Since(cached.CheckedAt) < s.cacheTTL {
+		return *cached
+	}
+
+	report, _, _ := s.runs.Do("readiness", func() (interface{}, error) {
+		report := s.run(context.WithoutCancel(ctx))
+		s.mu.Lock()
+		s.cached = &report
+		s.mu.Unlock()
+		return report, nil
+	})
+	return report.(Report)
+}
+
+func (s *Service) run(ctx context.Context) Report {
+	results := make(map[string]CheckResult, len(s.checkers))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, c := range s.checkers {
+		wg.Add(1)
+		go func(c Checker) {
+			defer wg.Done()
+			result := s.runOne(ctx, c)
+			mu.Lock()
+			results[c.Name()] = result
+			mu.Unlock()
+		}(c)
+	}
+	wg.Wait()
+
+	status := StatusOK
+	for _, r := range results {
+		if r.Status == StatusFail {
+			status = StatusFail
+			break
+		}
+	}
+	return Report{Status: status, Checks: results, CheckedAt: time.Now()}
+}
+
+func (s *Service) runOne(ctx context.Context, c Checker) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	start := time.Now()
+	errCh := make(chan error, 1)
+	go func() { errCh <- c.Check(ctx) }()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result := CheckResult{Status: StatusOK, DurationMS: time.Since(start).Milliseconds()}
+	if err != nil {
+		result.Status = StatusFail
+		if _, optional := c.(optionalChecker); optional {
+			result.Status = StatusDegraded
+		}
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,141 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_Readiness(t *testing.T) {
+	testCases := []struct {
+		name           string
+		checkers       []Checker
+		draining       bool
+		expectedStatus string
+		expectedChecks map[string]string
+	}{
+		{
+			name: "All Checks Pass",
+			checkers: []Checker{
+				NewCheckerFunc("mysql", func(ctx context.Context) error { return nil }),
+				NewCheckerFunc("redis", func(ctx context.Context) error { return nil }),
+			},
+			expectedStatus: StatusOK,
+			expectedChecks: map[string]string{"mysql": StatusOK, "redis": StatusOK},
+		},
+		{
+			name: "One Check Fails",
+			checkers: []Checker{
+				NewCheckerFunc("mysql", func(ctx context.Context) error { return errors.New("connection refused") }),
+				NewCheckerFunc("redis", func(ctx context.Context) error { return nil }),
+			},
+			expectedStatus: StatusFail,
+			expectedChecks: map[string]string{"mysql": StatusFail, "redis": StatusOK},
+		},
+		{
+			name: "Optional Check Fails",
+			checkers: []Checker{
+				NewCheckerFunc("mysql", func(ctx context.Context) error { return nil }),
+				NewOptionalChecker(NewCheckerFunc("redis", func(ctx context.Context) error { return errors.New("circuit open") })),
+			},
+			expectedStatus: StatusOK,
+			expectedChecks: map[string]string{"mysql": StatusOK, "redis": StatusDegraded},
+		},
+		{
+			name: "Check Times Out",
+			checkers: []Checker{
+				NewCheckerFunc("slow", func(ctx context.Context) error {
+					time.Sleep(time.Second)
+					return nil
+				}),
+			},
+			expectedStatus: StatusFail,
+			expectedChecks: map[string]string{"slow": StatusFail},
+		},
+		{
+			name: "Draining",
+			checkers: []Checker{
+				NewCheckerFunc("mysql", func(ctx context.Context) error { return nil }),
+			},
+			draining:       true,
+			expectedStatus: StatusDraining,
+			expectedChecks: map[string]string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := NewService(50*time.Millisecond, 0, tc.checkers...)
+			svc.SetDraining(tc.draining)
+
+			report := svc.Readiness(context.Background())
+
+			assert.Equal(t, tc.expectedStatus, report.Status)
+			assert.Equal(t, tc.expectedStatus == StatusOK, report.Ready())
+			checks := make(map[string]string, len(report.Checks))
+			for name, result := range report.Checks {
+				checks[name] = result.Status
+			}
+			assert.Equal(t, tc.expectedChecks, checks)
+		})
+	}
+}
+
+func TestService_ReadinessIsCached(t *testing.T) {
+	var calls atomic.Int32
+	svc := NewService(time.Second, time.Minute, NewCheckerFunc("mysql", func(ctx context.Context) error {
+		calls.Add(1)
+		return nil
+	}))
+
+	svc.Readiness(context.Background())
+	svc.Readiness(context.Background())
+
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestService_ConcurrentProbesShareOneRun(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	svc := NewService(time.Second, 0, NewCheckerFunc("mysql", func(ctx context.Context) error {
+		calls.Add(1)
+		<-release
+		return nil
+	}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, StatusOK, svc.Readiness(context.Background()).Status)
+		}()
+	}
+	// Let every probe arrive while the first run is still blocked.
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load(), "probes waiting on a running check join it instead of queueing")
+}
+
+func TestService_ReadinessIgnoresProbeCancellation(t *testing.T) {
+	svc := NewService(time.Second, time.Minute, NewCheckerFunc("mysql", func(ctx context.Context) error {
+		select {
+		case <-time.After(10 * time.Millisecond):
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, StatusOK, svc.Readiness(ctx).Status)
+	assert.Equal(t, StatusOK, svc.Readiness(context.Background()).Status, "a cancelled probe caches no failure")
+}
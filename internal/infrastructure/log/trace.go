@@ -0,0 +1,34 @@
+package log
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// TraceFields returns the request ID and the trace and span IDs of the span
+// in ctx as zap fields. It returns nil when ctx carries neither.
+func TraceFields(ctx context.Context) []zap.Field {
+	var fields []zap.Field
+	if id := RequestID(ctx); id != "" {
+		fields = append(fields, zap.String("request_id", id))
+	}
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return fields
+	}
+	return append(fields,
+		zap.String("trace_id", sc.TraceID().String()),
+		zap.String("span_id", sc.SpanID().String()),
+	)
+}
+
+// WithTrace returns a logger that includes the request and trace IDs found in ctx.
+func WithTrace(ctx context.Context, logger *zap.Logger) *zap.Logger {
+	fields := TraceFields(ctx)
+	if len(fields) == 0 {
+		return logger
+	}
+	return logger.With(fields...)
+}
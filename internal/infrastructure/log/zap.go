@@ -71,3 +71,13 @@ func InitLogger() {
 func GetLogger() *zap.Logger {
 	return logger
 }
+
+// SetLevel changes the level of the global logger at runtime.
+func SetLevel(level string) error {
+	var l zapcore.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return err
+	}
+	AtomicLevel.SetLevel(l)
+	return nil
+}
@@ -0,0 +1,19 @@
+package gorm
+
+import (
+	"testing"
+
+	"github.com/FormalYou/clean-architecture-blog/internal/application/repository/repositorytest"
+)
+
+func TestConformance(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) repositorytest.Repositories {
+		db := newTestDB(t)
+		return repositorytest.Repositories{
+			Articles: NewGormArticleRepository(db),
+			Users:    NewGormUserRepository(db),
+			Tags:     NewGormTagRepository(db),
+			Comments: NewGormCommentRepository(db),
+		}
+	})
+}
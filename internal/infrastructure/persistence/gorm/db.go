@@ -2,47 +2,92 @@ package gorm
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/glebarez/sqlite"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
+)
 
-	)
+// Supported database drivers.
+const (
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
 
 // DSNConfig (Data Source Name) for the database connection
 type DSNConfig struct {
+	Driver   string // mysql (default), postgres or sqlite
 	User     string
 	Password string
 	Host     string
 	Port     string
-	DBName   string
+	DBName   string // For sqlite this is the database file path, or ":memory:"
+	SSLMode  string // Only used by postgres, defaults to "disable"
 }
 
-// NewDB creates a new database connection and performs auto-migration.
-func NewDB(cfg DSNConfig) (*gorm.DB, error) {
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-		cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.DBName)
+// Dialector returns the GORM dialector for the configured driver.
+func Dialector(cfg DSNConfig) (gorm.Dialector, error) {
+	switch cfg.Driver {
+	case "", DriverMySQL:
+		dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+			cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.DBName)
+		return mysql.Open(dsn), nil
+	case DriverPostgres:
+		sslMode := cfg.SSLMode
+		if sslMode == "" {
+			sslMode = "disable"
+		}
+		dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+			cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName, sslMode)
+		return postgres.Open(dsn), nil
+	case DriverSQLite:
+		return sqlite.Open(sqliteDSN(cfg.DBName)), nil
+	default:
+		return nil, fmt.Errorf("unsupported database driver: %q", cfg.Driver)
+	}
+}
 
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info), // Log all SQL queries
-	})
-	if err != nil {
-		return nil, err
+// sqliteDSN turns a file path into a DSN with foreign keys enabled.
+// ":memory:" becomes a shared in-memory database so every pooled
+// connection sees the same data.
+func sqliteDSN(path string) string {
+	if path == "" || path == ":memory:" {
+		path = "file::memory:?cache=shared"
 	}
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	return path + sep + "_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+}
 
-	// Auto-migrate the schema using GORM models
-	// Auto-migrate the schema, but handle many2many separately
-	err = db.AutoMigrate(&UserModel{}, &ArticleModel{}, &TagModel{})
+// NewDB creates a new database connection. The schema is managed by the
+// versioned migrations in the migrations package.
+func NewDB(cfg DSNConfig) (*gorm.DB, error) {
+	dialector, err := Dialector(cfg)
 	if err != nil {
 		return nil, err
 	}
-	// Manually create the many2many relationship to avoid GORM's default primary key
-	if !db.Migrator().HasConstraint(&ArticleModel{}, "Tags") {
-		db.Migrator().CreateConstraint(&ArticleModel{}, "Tags")
-	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Info), // Log all SQL queries
+	})
 	if err != nil {
 		return nil, err
 	}
 
+	if cfg.Driver == DriverSQLite {
+		// SQLite allows a single writer; serialising connections avoids SQLITE_BUSY.
+		sqlDB, err := db.DB()
+		if err != nil {
+			return nil, err
+		}
+		sqlDB.SetMaxOpenConns(1)
+	}
+
 	return db, nil
-}
\ No newline at end of file
+}
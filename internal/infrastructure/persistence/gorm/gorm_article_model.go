@@ -8,30 +8,46 @@ import (
 
 // ArticleModel 是文章在GORM中的持久化模型
 type ArticleModel struct {
-	ID        int64     `gorm:"primaryKey"`
-	Title     string    `gorm:"not null"`
-	Content   string    `gorm:"type:text"`
-	AuthorID  int64     `gorm:"not null"`
-	CreatedAt time.Time `gorm:"autoCreateTime"`
-	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+	ID        int64      `gorm:"primaryKey"`
+	Title     string     `gorm:"not null"`
+	Content   string     `gorm:"type:text"`
+	AuthorID  int64      `gorm:"not null"`
+	Tags      []TagModel `gorm:"many2many:article_tags;"`
+	Version   int64      `gorm:"not null;default:1"`
+	CreatedAt time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt time.Time  `gorm:"autoUpdateTime"`
 }
 
 // ToDomain 将持久化模型转换为领域模型
 func (m *ArticleModel) ToDomain() *domain.Article {
-	return &domain.Article{
-		ID:       m.ID,
-		Title:    m.Title,
-		Content:  m.Content,
-		AuthorID: m.AuthorID,
+	article := &domain.Article{
+		ID:        m.ID,
+		Title:     m.Title,
+		Content:   m.Content,
+		AuthorID:  m.AuthorID,
+		Version:   m.Version,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
 	}
+	for _, tag := range m.Tags {
+		article.Tags = append(article.Tags, *tag.ToDomain())
+	}
+	return article
 }
 
 // FromDomain 将领域模型转换为持久化模型
 func FromDomain(a *domain.Article) *ArticleModel {
-	return &ArticleModel{
-		ID:       a.ID,
-		Title:    a.Title,
-		Content:  a.Content,
-		AuthorID: a.AuthorID,
+	m := &ArticleModel{
+		ID:        a.ID,
+		Title:     a.Title,
+		Content:   a.Content,
+		AuthorID:  a.AuthorID,
+		Version:   a.Version,
+		CreatedAt: a.CreatedAt,
+		UpdatedAt: a.UpdatedAt,
+	}
+	for i := range a.Tags {
+		m.Tags = append(m.Tags, *FromDomainTag(&a.Tags[i]))
 	}
+	return m
 }
@@ -2,6 +2,8 @@ package gorm
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"gorm.io/gorm"
 
@@ -9,38 +11,74 @@ import (
 	"github.com/FormalYou/clean-architecture-blog/internal/application/repository"
 )
 
+// Read-your-writes keys for articles.
+const articleListKey = "articles"
+
+func articleKey(id int64) string {
+	return fmt.Sprintf("article:%d", id)
+}
+
 type GormArticleRepository struct {
-	db *gorm.DB
+	db   *gorm.DB
+	opts repoOptions
 }
 
-func NewGormArticleRepository(db *gorm.DB) repository.ArticleRepository {
-	return &GormArticleRepository{db: db}
+func NewGormArticleRepository(db *gorm.DB, opts ...RepositoryOption) repository.ArticleRepository {
+	return &GormArticleRepository{db: db, opts: newRepoOptions(opts)}
 }
 
 func (r *GormArticleRepository) Create(ctx context.Context, article *domain.Article) error {
+	db, ctx, cancel := r.opts.query(ctx, r.db, "article.create")
+	defer cancel()
+
 	articleModel := FromDomain(article)
-	if err := r.db.WithContext(ctx).Create(articleModel).Error; err != nil {
-		return err
+	articleModel.Version = 1
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// Reuse existing tags by name so the unique index on tag names holds.
+		for i := range articleModel.Tags {
+			tag := &articleModel.Tags[i]
+			if err := tx.Where(TagModel{Name: tag.Name}).FirstOrCreate(tag).Error; err != nil {
+				return err
+			}
+		}
+		// The tags exist now, so only the article and the join rows are inserted.
+		return tx.Omit("Tags.*").Create(articleModel).Error
+	})
+	if err != nil {
+		return queryError(ctx, "article.create", err)
 	}
 	article.ID = articleModel.ID
+	article.Version = articleModel.Version
+	article.CreatedAt = articleModel.CreatedAt
+	article.UpdatedAt = articleModel.UpdatedAt
+	for i := range articleModel.Tags {
+		article.Tags[i].ID = articleModel.Tags[i].ID
+	}
+	r.opts.written(ctx, articleKey(article.ID), articleListKey, tagListKey)
 	return nil
 }
 
 func (r *GormArticleRepository) GetByID(ctx context.Context, id int64) (*domain.Article, error) {
+	db, ctx, cancel := r.opts.read(ctx, r.db, "article.get_by_id", articleKey(id))
+	defer cancel()
+
 	var articleModel ArticleModel
-	if err := r.db.WithContext(ctx).First(&articleModel, id).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
+	if err := db.Preload("Tags").First(&articleModel, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, repository.ErrNotFound
 		}
-		return nil, err
+		return nil, queryError(ctx, "article.get_by_id", err)
 	}
 	return articleModel.ToDomain(), nil
 }
 
 func (r *GormArticleRepository) GetAll(ctx context.Context) ([]*domain.Article, error) {
+	db, ctx, cancel := r.opts.read(ctx, r.db, "article.get_all", articleListKey)
+	defer cancel()
+
 	var articleModels []ArticleModel
-	if err := r.db.WithContext(ctx).Find(&articleModels).Error; err != nil {
-		return nil, err
+	if err := db.Preload("Tags").Order("id").Find(&articleModels).Error; err != nil {
+		return nil, queryError(ctx, "article.get_all", err)
 	}
 	var articles []*domain.Article
 	for _, model := range articleModels {
@@ -49,11 +87,54 @@ func (r *GormArticleRepository) GetAll(ctx context.Context) ([]*domain.Article,
 	return articles, nil
 }
 
+func (r *GormArticleRepository) List(ctx context.Context, limit, offset int) ([]*domain.Article, error) {
+	db, ctx, cancel := r.opts.read(ctx, r.db, "article.list", articleListKey)
+	defer cancel()
+
+	var articleModels []ArticleModel
+	if err := db.Preload("Tags").Order("id").Limit(limit).Offset(offset).Find(&articleModels).Error; err != nil {
+		return nil, queryError(ctx, "article.list", err)
+	}
+	articles := make([]*domain.Article, 0, len(articleModels))
+	for _, model := range articleModels {
+		articles = append(articles, model.ToDomain())
+	}
+	return articles, nil
+}
+
 func (r *GormArticleRepository) Update(ctx context.Context, article *domain.Article) error {
-	articleModel := FromDomain(article)
-	return r.db.WithContext(ctx).Model(&ArticleModel{}).Where("id = ?", article.ID).Updates(articleModel).Error
+	db, ctx, cancel := r.opts.query(ctx, r.db, "article.update")
+	defer cancel()
+
+	// Only non-zero fields are written, like Updates with a struct, and the
+	// version is bumped in the same statement.
+	updates := map[string]interface{}{"version": gorm.Expr("version + 1")}
+	if article.Title != "" {
+		updates["title"] = article.Title
+	}
+	if article.Content != "" {
+		updates["content"] = article.Content
+	}
+	if article.AuthorID != 0 {
+		updates["author_id"] = article.AuthorID
+	}
+	err := db.Model(&ArticleModel{}).Where("id = ?", article.ID).Updates(updates).Error
+	if err != nil {
+		return queryError(ctx, "article.update", err)
+	}
+	r.opts.written(ctx, articleKey(article.ID), articleListKey)
+	return nil
 }
 
 func (r *GormArticleRepository) Delete(ctx context.Context, id int64) error {
-	return r.db.WithContext(ctx).Delete(&ArticleModel{}, id).Error
+	db, ctx, cancel := r.opts.query(ctx, r.db, "article.delete")
+	defer cancel()
+
+	// Selecting Tags removes the join rows first so the foreign keys hold.
+	err := db.Select("Tags").Delete(&ArticleModel{ID: id}).Error
+	if err != nil {
+		return queryError(ctx, "article.delete", err)
+	}
+	r.opts.written(ctx, articleKey(id), articleListKey)
+	return nil
 }
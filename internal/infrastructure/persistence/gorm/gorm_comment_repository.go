@@ -1,27 +1,38 @@
 package gorm
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/FormalYou/clean-architecture-blog/domain"
 	"github.com/FormalYou/clean-architecture-blog/internal/application/repository"
 	"gorm.io/gorm"
 )
 
+func commentsKey(articleID int64) string {
+	return fmt.Sprintf("comments:%d", articleID)
+}
+
 // GormCommentRepository 是 CommentRepository 的 GORM 实现
 type GormCommentRepository struct {
-	db *gorm.DB
+	db   *gorm.DB
+	opts repoOptions
 }
 
 // NewGormCommentRepository 创建一个新的 GormCommentRepository
-func NewGormCommentRepository(db *gorm.DB) repository.CommentRepository {
-	return &GormCommentRepository{db: db}
+func NewGormCommentRepository(db *gorm.DB, opts ...RepositoryOption) repository.CommentRepository {
+	return &GormCommentRepository{db: db, opts: newRepoOptions(opts)}
 }
 
 // FindByArticleID 通过文章 ID 从数据库中获取评论
-func (r *GormCommentRepository) FindByArticleID(articleID uint) ([]*domain.Comment, error) {
+func (r *GormCommentRepository) FindByArticleID(ctx context.Context, articleID uint) ([]*domain.Comment, error) {
+	db, ctx, cancel := r.opts.read(ctx, r.db, "comment.find_by_article_id", commentsKey(int64(articleID)))
+	defer cancel()
+
 	var commentModels []CommentModel
-	err := r.db.Where("article_id = ?", articleID).Find(&commentModels).Error
+	err := db.Where("article_id = ?", articleID).Order("id").Find(&commentModels).Error
 	if err != nil {
-		return nil, err
+		return nil, queryError(ctx, "comment.find_by_article_id", err)
 	}
 
 	var comments []*domain.Comment
@@ -31,8 +42,50 @@ func (r *GormCommentRepository) FindByArticleID(articleID uint) ([]*domain.Comme
 	return comments, nil
 }
 
+// FindByArticleIDs 以一次查询获取多篇文章的评论，按文章 ID 与评论 ID 排序。
+// limit 为正时由数据库按文章截取前 limit 条（窗口函数），读取量不随评论总数增长。
+// ROW_NUMBER() 要求 MySQL 8.0 及以上（PostgreSQL 与 SQLite 3.25 及以上均支持）。
+func (r *GormCommentRepository) FindByArticleIDs(ctx context.Context, articleIDs []int64, limit int) ([]*domain.Comment, error) {
+	if len(articleIDs) == 0 {
+		return nil, nil
+	}
+	keys := make([]string, len(articleIDs))
+	for i, id := range articleIDs {
+		keys[i] = commentsKey(id)
+	}
+	db, ctx, cancel := r.opts.read(ctx, r.db, "comment.find_by_article_ids", keys...)
+	defer cancel()
+
+	var commentModels []CommentModel
+	query := db.Where("article_id IN ?", articleIDs)
+	if limit > 0 {
+		ranked := query.Model(&CommentModel{}).
+			Select("*, ROW_NUMBER() OVER (PARTITION BY article_id ORDER BY id) AS comment_rank")
+		query = db.Table("(?) AS ranked", ranked).Where("comment_rank <= ?", limit)
+	}
+	err := query.Order("article_id, id").Find(&commentModels).Error
+	if err != nil {
+		return nil, queryError(ctx, "comment.find_by_article_ids", err)
+	}
+
+	comments := make([]*domain.Comment, len(commentModels))
+	for i := range commentModels {
+		comments[i] = commentModels[i].ToDomain()
+	}
+	return comments, nil
+}
+
 // Save 在数据库中保存一条评论
-func (r *GormCommentRepository) Save(comment *domain.Comment) error {
+func (r *GormCommentRepository) Save(ctx context.Context, comment *domain.Comment) error {
+	db, ctx, cancel := r.opts.query(ctx, r.db, "comment.save")
+	defer cancel()
+
 	commentModel := FromDomainComment(comment)
-	return r.db.Save(commentModel).Error
+	if err := db.Save(commentModel).Error; err != nil {
+		return queryError(ctx, "comment.save", err)
+	}
+	comment.ID = commentModel.ID
+	comment.CreatedAt = commentModel.CreatedAt
+	r.opts.written(ctx, commentsKey(comment.ArticleID))
+	return nil
 }
@@ -1,27 +1,38 @@
 package gorm
 
 import (
+	"context"
+	"errors"
+
 	"github.com/FormalYou/clean-architecture-blog/domain"
 	"github.com/FormalYou/clean-architecture-blog/internal/application/repository"
 	"gorm.io/gorm"
 )
 
+// tagListKey is the read-your-writes key for tags. Tags change rarely, so a
+// single key covers every tag read.
+const tagListKey = "tags"
+
 // GormTagRepository 是 TagRepository 的 GORM 实现
 type GormTagRepository struct {
-	db *gorm.DB
+	db   *gorm.DB
+	opts repoOptions
 }
 
 // NewGormTagRepository 创建一个新的 GormTagRepository
-func NewGormTagRepository(db *gorm.DB) repository.TagRepository {
-	return &GormTagRepository{db: db}
+func NewGormTagRepository(db *gorm.DB, opts ...RepositoryOption) repository.TagRepository {
+	return &GormTagRepository{db: db, opts: newRepoOptions(opts)}
 }
 
 // FindAll 从数据库中获取所有标签
-func (r *GormTagRepository) FindAll() ([]*domain.Tag, error) {
+func (r *GormTagRepository) FindAll(ctx context.Context) ([]*domain.Tag, error) {
+	db, ctx, cancel := r.opts.read(ctx, r.db, "tag.find_all", tagListKey)
+	defer cancel()
+
 	var tagModels []TagModel
-	err := r.db.Find(&tagModels).Error
+	err := db.Order("id").Find(&tagModels).Error
 	if err != nil {
-		return nil, err
+		return nil, queryError(ctx, "tag.find_all", err)
 	}
 
 	var tags []*domain.Tag
@@ -32,17 +43,31 @@ func (r *GormTagRepository) FindAll() ([]*domain.Tag, error) {
 }
 
 // FindByName 通过名称从数据库中获取标签
-func (r *GormTagRepository) FindByName(name string) (*domain.Tag, error) {
+func (r *GormTagRepository) FindByName(ctx context.Context, name string) (*domain.Tag, error) {
+	db, ctx, cancel := r.opts.read(ctx, r.db, "tag.find_by_name", tagListKey)
+	defer cancel()
+
 	var tagModel TagModel
-	err := r.db.Where("name = ?", name).First(&tagModel).Error
+	err := db.Where("name = ?", name).First(&tagModel).Error
 	if err != nil {
-		return nil, err
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, queryError(ctx, "tag.find_by_name", err)
 	}
 	return tagModel.ToDomain(), nil
 }
 
 // Save 在数据库中保存一个标签
-func (r *GormTagRepository) Save(tag *domain.Tag) error {
+func (r *GormTagRepository) Save(ctx context.Context, tag *domain.Tag) error {
+	db, ctx, cancel := r.opts.query(ctx, r.db, "tag.save")
+	defer cancel()
+
 	tagModel := FromDomainTag(tag)
-	return r.db.Save(tagModel).Error
+	if err := db.Save(tagModel).Error; err != nil {
+		return queryError(ctx, "tag.save", err)
+	}
+	tag.ID = tagModel.ID
+	r.opts.written(ctx, tagListKey)
+	return nil
 }
@@ -1,7 +1,9 @@
 package gorm
 
 import (
+	"context"
 	"errors"
+	"fmt"
 
 	"github.com/FormalYou/clean-architecture-blog/domain"
 	"github.com/FormalYou/clean-architecture-blog/internal/application/repository"
@@ -10,58 +12,98 @@ import (
 
 // GormUserRepository 是 UserRepository 的 GORM 实现
 type GormUserRepository struct {
-	db *gorm.DB
+	db   *gorm.DB
+	opts repoOptions
 }
 
 // NewGormUserRepository 创建一个新的 GormUserRepository
-func NewGormUserRepository(db *gorm.DB) repository.UserRepository {
-	return &GormUserRepository{db: db}
+func NewGormUserRepository(db *gorm.DB, opts ...RepositoryOption) repository.UserRepository {
+	return &GormUserRepository{db: db, opts: newRepoOptions(opts)}
 }
 
 // FindByID 通过 ID 从数据库中获取用户
-func (r *GormUserRepository) FindByID(id uint) (*domain.User, error) {
-	var userModel UserModel
-	err := r.db.First(&userModel, id).Error
-	if err != nil {
-		return nil, err
+func (r *GormUserRepository) FindByID(ctx context.Context, id uint) (*domain.User, error) {
+	return r.first(ctx, "user.find_by_id", fmt.Sprintf("user:id:%d", id), "id = ?", id)
+}
+
+// FindByIDs 一次查询获取多个用户，不存在的 ID 被忽略
+func (r *GormUserRepository) FindByIDs(ctx context.Context, ids []int64) ([]*domain.User, error) {
+	if len(ids) == 0 {
+		return nil, nil
 	}
-	return userModel.ToDomain(), nil
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = fmt.Sprintf("user:id:%d", id)
+	}
+	db, ctx, cancel := r.opts.read(ctx, r.db, "user.find_by_ids", keys...)
+	defer cancel()
+
+	var userModels []UserModel
+	if err := db.Where("id IN ?", ids).Find(&userModels).Error; err != nil {
+		return nil, queryError(ctx, "user.find_by_ids", err)
+	}
+	users := make([]*domain.User, len(userModels))
+	for i := range userModels {
+		users[i] = userModels[i].ToDomain()
+	}
+	return users, nil
 }
 
 // FindByEmail 通过 Email 从数据库中获取用户
-func (r *GormUserRepository) FindByEmail(email string) (*domain.User, error) {
-	var userModel UserModel
-	err := r.db.Where("email = ?", email).First(&userModel).Error
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, repository.ErrNotFound
-		}
-		return nil, err
-	}
-	return userModel.ToDomain(), nil
+func (r *GormUserRepository) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
+	return r.first(ctx, "user.find_by_email", "user:email:"+email, "email = ?", email)
 }
 
 // GetByUsername 通过用户名从数据库中获取用户
-func (r *GormUserRepository) GetByUsername(username string) (*domain.User, error) {
+func (r *GormUserRepository) GetByUsername(ctx context.Context, username string) (*domain.User, error) {
+	return r.first(ctx, "user.get_by_username", "user:username:"+username, "username = ?", username)
+}
+
+// first 返回第一个满足条件的用户，不存在时返回 repository.ErrNotFound
+func (r *GormUserRepository) first(ctx context.Context, op, key string, query string, args ...interface{}) (*domain.User, error) {
+	db, ctx, cancel := r.opts.read(ctx, r.db, op, key)
+	defer cancel()
+
 	var userModel UserModel
-	err := r.db.Where("username = ?", username).First(&userModel).Error
+	err := db.Where(query, args...).First(&userModel).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, repository.ErrNotFound
 		}
-		return nil, err
+		return nil, queryError(ctx, op, err)
 	}
 	return userModel.ToDomain(), nil
 }
 
 // Save 在数据库中保存一个用户
-func (r *GormUserRepository) Save(user *domain.User) error {
+func (r *GormUserRepository) Save(ctx context.Context, user *domain.User) error {
+	db, ctx, cancel := r.opts.query(ctx, r.db, "user.save")
+	defer cancel()
+
 	userModel := FromDomainUser(user)
-	return r.db.Save(userModel).Error
+	if err := db.Save(userModel).Error; err != nil {
+		return queryError(ctx, "user.save", err)
+	}
+	user.ID = userModel.ID
+	r.written(ctx, user)
+	return nil
 }
 
 // Create 在数据库中创建一个新用户
-func (r *GormUserRepository) Create(user *domain.User) error {
+func (r *GormUserRepository) Create(ctx context.Context, user *domain.User) error {
+	db, ctx, cancel := r.opts.query(ctx, r.db, "user.create")
+	defer cancel()
+
 	userModel := FromDomainUser(user)
-	return r.db.Create(userModel).Error
+	if err := db.Create(userModel).Error; err != nil {
+		return queryError(ctx, "user.create", err)
+	}
+	user.ID = userModel.ID
+	r.written(ctx, user)
+	return nil
+}
+
+// written marks every key a user can be read by as recently written.
+func (r *GormUserRepository) written(ctx context.Context, user *domain.User) {
+	r.opts.written(ctx, fmt.Sprintf("user:id:%d", user.ID), "user:email:"+user.Email, "user:username:"+user.Username)
 }
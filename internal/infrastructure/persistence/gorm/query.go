@@ -0,0 +1,99 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/FormalYou/clean-architecture-blog/internal/application/repository"
+)
+
+// QueryTimeouts 配置仓库操作的查询超时。Operations 以操作名（如
+// "user.find_by_email"）为键覆盖 Default；值为 0 表示不设超时。
+type QueryTimeouts struct {
+	Default    time.Duration
+	Operations map[string]time.Duration
+}
+
+// For returns the timeout for the named operation.
+func (t QueryTimeouts) For(op string) time.Duration {
+	if d, ok := t.Operations[op]; ok {
+		return d
+	}
+	return t.Default
+}
+
+// RepositoryOption configures a GORM repository.
+type RepositoryOption func(*repoOptions)
+
+type repoOptions struct {
+	timeouts QueryTimeouts
+	replicas *ReplicaSet
+}
+
+// WithQueryTimeouts sets the per-operation query timeouts of a repository.
+func WithQueryTimeouts(timeouts QueryTimeouts) RepositoryOption {
+	return func(o *repoOptions) {
+		o.timeouts = timeouts
+	}
+}
+
+// WithReplicas sends the repository's reads to the replicas in rs.
+func WithReplicas(rs *ReplicaSet) RepositoryOption {
+	return func(o *repoOptions) {
+		o.replicas = rs
+	}
+}
+
+func newRepoOptions(opts []RepositoryOption) repoOptions {
+	var o repoOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// query returns the connection for op, bound to ctx (and the transaction in
+// it, if any) with the operation's timeout applied. The returned context
+// must be passed to queryError and the cancel func called when done.
+func (o repoOptions) query(ctx context.Context, db *gorm.DB, op string) (*gorm.DB, context.Context, context.CancelFunc) {
+	cancel := context.CancelFunc(func() {})
+	if d := o.timeouts.For(op); d > 0 {
+		ctx, cancel = context.WithTimeout(ctx, d)
+	}
+	return conn(ctx, db), ctx, cancel
+}
+
+// read is like query for a read-only operation on keys: it is served by a
+// replica unless ctx carries a transaction or keys were written within the
+// read-your-writes window.
+func (o repoOptions) read(ctx context.Context, db *gorm.DB, op string, keys ...string) (*gorm.DB, context.Context, context.CancelFunc) {
+	if o.replicas != nil {
+		if _, inTx := ctx.Value(txKey{}).(*gorm.DB); !inTx {
+			db = o.replicas.Reader(ctx, keys...)
+		}
+	}
+	return o.query(ctx, db, op)
+}
+
+// written records a successful write of keys for read-your-writes routing.
+func (o repoOptions) written(ctx context.Context, keys ...string) {
+	if o.replicas != nil {
+		o.replicas.MarkWritten(ctx, keys...)
+	}
+}
+
+// queryError translates a deadline hit during op into repository.ErrTimeout.
+// Not every driver wraps the context error, so the context is checked too.
+func queryError(ctx context.Context, op string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %s: %v", repository.ErrTimeout, op, err)
+	}
+	return err
+}
@@ -0,0 +1,90 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FormalYou/clean-architecture-blog/domain"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/repository"
+)
+
+func TestQueryTimeouts_For(t *testing.T) {
+	timeouts := QueryTimeouts{
+		Default:    time.Second,
+		Operations: map[string]time.Duration{"user.find_by_email": 50 * time.Millisecond, "tag.find_all": 0},
+	}
+	assert.Equal(t, 50*time.Millisecond, timeouts.For("user.find_by_email"))
+	assert.Equal(t, time.Duration(0), timeouts.For("tag.find_all"))
+	assert.Equal(t, time.Second, timeouts.For("article.get_all"))
+}
+
+func TestRepositories_QueryTimeout(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	// A timeout this short has always expired by the time the query runs.
+	expired := WithQueryTimeouts(QueryTimeouts{
+		Default:    time.Minute,
+		Operations: map[string]time.Duration{"user.find_by_email": time.Nanosecond},
+	})
+	userRepo := NewGormUserRepository(db, expired)
+
+	user := &domain.User{Username: "slow", Email: "slow@example.com", PasswordHash: "x"}
+	require.NoError(t, userRepo.Create(ctx, user), "the default timeout applies to other operations")
+	assert.NotZero(t, user.ID)
+
+	_, err := userRepo.FindByEmail(ctx, "slow@example.com")
+	assert.ErrorIs(t, err, repository.ErrTimeout)
+
+	found, err := userRepo.GetByUsername(ctx, "slow")
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, found.ID)
+
+	// A request context that is already past its deadline is reported the same way.
+	deadlineCtx, cancel := context.WithDeadline(ctx, time.Now().Add(-time.Second))
+	defer cancel()
+	_, err = NewGormTagRepository(db).FindAll(deadlineCtx)
+	assert.ErrorIs(t, err, repository.ErrTimeout)
+
+	// Cancellation is not a timeout.
+	canceledCtx, cancelNow := context.WithCancel(ctx)
+	cancelNow()
+	_, err = NewGormCommentRepository(db).FindByArticleID(canceledCtx, 1)
+	require.Error(t, err)
+	assert.False(t, errors.Is(err, repository.ErrTimeout))
+}
+
+func TestRepositories_NotFoundAndTransactions(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	txManager := NewTxManager(db, 0)
+	userRepo := NewGormUserRepository(db)
+	tagRepo := NewGormTagRepository(db)
+
+	_, err := userRepo.FindByID(ctx, 42)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	_, err = tagRepo.FindByName(ctx, "missing")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	boom := errors.New("boom")
+	err = txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := userRepo.Create(ctx, &domain.User{Username: "tx", Email: "tx@example.com", PasswordHash: "x"}); err != nil {
+			return err
+		}
+		if err := tagRepo.Save(ctx, &domain.Tag{Name: "tx"}); err != nil {
+			return err
+		}
+		return boom
+	})
+	assert.ErrorIs(t, err, boom)
+
+	_, err = userRepo.FindByEmail(ctx, "tx@example.com")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	_, err = tagRepo.FindByName(ctx, "tx")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
@@ -0,0 +1,238 @@
+package gorm
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Replica is a read-only copy of the primary database.
+type Replica struct {
+	Name string
+	DB   *gorm.DB
+}
+
+// ReplicaOptions configures a ReplicaSet.
+type ReplicaOptions struct {
+	// CheckInterval is how often replicas are pinged; 0 disables probing.
+	CheckInterval time.Duration
+	// MaxFailures is the number of consecutive failed pings after which a
+	// replica stops receiving reads. It is readmitted after one success.
+	MaxFailures int
+	// ReadYourWrites is how long reads of a just-written key go to the
+	// primary, so the writer sees its change despite replication lag.
+	ReadYourWrites time.Duration
+	// Writes, if set, shares the read-your-writes window with the other
+	// instances. Without it only reads served by the writing instance are
+	// routed to the primary.
+	Writes WriteTracker
+	// OnStateChange, if set, is called when a replica is ejected or readmitted.
+	OnStateChange func(name string, healthy bool, err error)
+}
+
+// WriteTracker records written keys where every instance can see them.
+type WriteTracker interface {
+	// MarkWritten records a write of keys for window.
+	MarkWritten(ctx context.Context, window time.Duration, keys ...string) error
+	// RecentlyWritten reports whether any of keys is within its window.
+	RecentlyWritten(ctx context.Context, keys ...string) (bool, error)
+}
+
+type replica struct {
+	Replica
+	healthy  atomic.Bool
+	failures int // only touched by the probing goroutine
+}
+
+// ReplicaSet routes reads to healthy replicas in round-robin order and
+// writes to the primary. Recently written keys are read from the primary
+// for the read-your-writes window, tracked in process and, with
+// ReplicaOptions.Writes, across instances.
+type ReplicaSet struct {
+	primary  *gorm.DB
+	replicas []*replica
+	opts     ReplicaOptions
+	next     atomic.Uint64
+
+	mu         sync.Mutex
+	written    map[string]time.Time // key -> time of the last write
+	lastPruned time.Time
+	now        func() time.Time
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewReplicaSet creates a ReplicaSet. Every replica starts out healthy.
+func NewReplicaSet(primary *gorm.DB, replicas []Replica, opts ReplicaOptions) *ReplicaSet {
+	if opts.MaxFailures <= 0 {
+		opts.MaxFailures = 1
+	}
+	rs := &ReplicaSet{primary: primary, opts: opts, written: map[string]time.Time{}, now: time.Now}
+	for _, r := range replicas {
+		rep := &replica{Replica: r}
+		rep.healthy.Store(true)
+		rs.replicas = append(rs.replicas, rep)
+	}
+	return rs
+}
+
+// Start probes the replicas every CheckInterval until Close is called.
+func (rs *ReplicaSet) Start() {
+	if rs.opts.CheckInterval <= 0 || len(rs.replicas) == 0 || rs.stop != nil {
+		return
+	}
+	rs.stop = make(chan struct{})
+	rs.done = make(chan struct{})
+	go func() {
+		defer close(rs.done)
+		ticker := time.NewTicker(rs.opts.CheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-rs.stop:
+				return
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), rs.opts.CheckInterval)
+				rs.probe(ctx)
+				cancel()
+			}
+		}
+	}()
+}
+
+// Close stops probing and closes the replica connections.
+func (rs *ReplicaSet) Close() error {
+	if rs.stop != nil {
+		close(rs.stop)
+		<-rs.done
+		rs.stop = nil
+	}
+	var firstErr error
+	for _, r := range rs.replicas {
+		sqlDB, err := r.DB.DB()
+		if err == nil {
+			err = sqlDB.Close()
+		}
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Primary returns the primary database.
+func (rs *ReplicaSet) Primary() *gorm.DB {
+	return rs.primary
+}
+
+// Reader returns the database to read from. Reads of keys written within
+// the read-your-writes window, and reads when no replica is healthy, go to
+// the primary.
+func (rs *ReplicaSet) Reader(ctx context.Context, keys ...string) *gorm.DB {
+	if len(rs.replicas) == 0 || rs.recentlyWritten(ctx, keys) {
+		return rs.primary
+	}
+	n := len(rs.replicas)
+	start := rs.next.Add(1)
+	for i := 0; i < n; i++ {
+		r := rs.replicas[(start+uint64(i))%uint64(n)]
+		if r.healthy.Load() {
+			return r.DB
+		}
+	}
+	return rs.primary
+}
+
+// MarkWritten records a write of keys for the read-your-writes window.
+func (rs *ReplicaSet) MarkWritten(ctx context.Context, keys ...string) {
+	if rs.opts.ReadYourWrites <= 0 || len(rs.replicas) == 0 || len(keys) == 0 {
+		return
+	}
+	now := rs.now()
+	rs.mu.Lock()
+	for _, key := range keys {
+		rs.written[key] = now
+	}
+	// Keys that are never read again would otherwise stay forever.
+	if now.Sub(rs.lastPruned) >= rs.opts.ReadYourWrites {
+		for key, at := range rs.written {
+			if now.Sub(at) >= rs.opts.ReadYourWrites {
+				delete(rs.written, key)
+			}
+		}
+		rs.lastPruned = now
+	}
+	rs.mu.Unlock()
+
+	// The write is already recorded locally; should the shared tracker be
+	// unavailable, other instances may serve the key from a replica.
+	if rs.opts.Writes != nil {
+		_ = rs.opts.Writes.MarkWritten(ctx, rs.opts.ReadYourWrites, keys...)
+	}
+}
+
+func (rs *ReplicaSet) recentlyWritten(ctx context.Context, keys []string) bool {
+	if rs.opts.ReadYourWrites <= 0 || len(keys) == 0 {
+		return false
+	}
+	now := rs.now()
+	rs.mu.Lock()
+	for _, key := range keys {
+		if at, ok := rs.written[key]; ok && now.Sub(at) < rs.opts.ReadYourWrites {
+			rs.mu.Unlock()
+			return true
+		}
+	}
+	rs.mu.Unlock()
+
+	if rs.opts.Writes == nil {
+		return false
+	}
+	written, err := rs.opts.Writes.RecentlyWritten(ctx, keys...)
+	return err == nil && written
+}
+
+// Healthy reports the health of every replica by name.
+func (rs *ReplicaSet) Healthy() map[string]bool {
+	states := make(map[string]bool, len(rs.replicas))
+	for _, r := range rs.replicas {
+		states[r.Name] = r.healthy.Load()
+	}
+	return states
+}
+
+// probe pings every replica once and ejects or readmits it.
+func (rs *ReplicaSet) probe(ctx context.Context) {
+	for _, r := range rs.replicas {
+		err := ping(ctx, r.DB)
+		if err == nil {
+			r.failures = 0
+			if !r.healthy.Swap(true) {
+				rs.stateChanged(r.Name, true, nil)
+			}
+			continue
+		}
+		r.failures++
+		if r.failures >= rs.opts.MaxFailures && r.healthy.Swap(false) {
+			rs.stateChanged(r.Name, false, err)
+		}
+	}
+}
+
+func (rs *ReplicaSet) stateChanged(name string, healthy bool, err error) {
+	if rs.opts.OnStateChange != nil {
+		rs.opts.OnStateChange(name, healthy, err)
+	}
+}
+
+func ping(ctx context.Context, db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
@@ -0,0 +1,188 @@
+package gorm
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FormalYou/clean-architecture-blog/domain"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/repository"
+	"github.com/FormalYou/clean-architecture-blog/internal/infrastructure/cache"
+)
+
+// Each database is a separate SQLite file with no replication between them,
+// so which one served a read is visible from the data it returns.
+
+func seedArticle(t *testing.T, repo repository.ArticleRepository, title string) *domain.Article {
+	t.Helper()
+	article := &domain.Article{Title: title, Content: "c", AuthorID: 1}
+	require.NoError(t, repo.Create(context.Background(), article))
+	return article
+}
+
+func TestReplicaSet_RoutesReadsToReplicasRoundRobin(t *testing.T) {
+	primary, replica1, replica2 := newTestDB(t), newTestDB(t), newTestDB(t)
+	seedArticle(t, NewGormArticleRepository(replica1), "from replica-1")
+	seedArticle(t, NewGormArticleRepository(replica2), "from replica-2")
+
+	rs := NewReplicaSet(primary, []Replica{{Name: "replica-1", DB: replica1}, {Name: "replica-2", DB: replica2}}, ReplicaOptions{})
+	repo := NewGormArticleRepository(primary, WithReplicas(rs))
+	ctx := context.Background()
+
+	seen := map[string]int{}
+	for i := 0; i < 4; i++ {
+		article, err := repo.GetByID(ctx, 1)
+		require.NoError(t, err)
+		seen[article.Title]++
+	}
+	assert.Equal(t, map[string]int{"from replica-1": 2, "from replica-2": 2}, seen)
+}
+
+func TestReplicaSet_WritesGoToPrimary(t *testing.T) {
+	primary, replica := newTestDB(t), newTestDB(t)
+	rs := NewReplicaSet(primary, []Replica{{Name: "replica", DB: replica}}, ReplicaOptions{})
+	repo := NewGormArticleRepository(primary, WithReplicas(rs))
+	ctx := context.Background()
+
+	article := seedArticle(t, repo, "written")
+
+	_, err := NewGormArticleRepository(primary).GetByID(ctx, article.ID)
+	assert.NoError(t, err, "the write reached the primary")
+	_, err = repo.GetByID(ctx, article.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound, "without read-your-writes the lagging replica serves the read")
+
+	// Reads inside a transaction stay on the primary.
+	err = NewTxManager(primary, 0).WithinTx(ctx, func(ctx context.Context) error {
+		_, err := repo.GetByID(ctx, article.ID)
+		return err
+	})
+	assert.NoError(t, err)
+}
+
+func TestReplicaSet_ReadYourWrites(t *testing.T) {
+	primary, replica := newTestDB(t), newTestDB(t)
+	rs := NewReplicaSet(primary, []Replica{{Name: "replica", DB: replica}}, ReplicaOptions{ReadYourWrites: 5 * time.Second})
+	now := time.Now()
+	rs.now = func() time.Time { return now }
+	repo := NewGormArticleRepository(primary, WithReplicas(rs))
+	ctx := context.Background()
+
+	seedArticle(t, NewGormArticleRepository(replica), "stale")
+	seedArticle(t, NewGormArticleRepository(primary), "original")
+
+	require.NoError(t, repo.Update(ctx, &domain.Article{ID: 1, Title: "edited"}))
+	article, err := repo.GetByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "edited", article.Title, "the edit is read back from the primary")
+	all, err := repo.GetAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "edited", all[0].Title)
+
+	now = now.Add(6 * time.Second)
+	article, err = repo.GetByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "stale", article.Title, "after the window reads go back to the replica")
+}
+
+func TestReplicaSet_SharesReadYourWritesAcrossInstances(t *testing.T) {
+	primary, replica := newTestDB(t), newTestDB(t)
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	newInstance := func() repository.ArticleRepository {
+		rs := NewReplicaSet(primary, []Replica{{Name: "replica", DB: replica}}, ReplicaOptions{
+			ReadYourWrites: 5 * time.Second,
+			Writes:         cache.NewRedisWriteTracker(client),
+		})
+		return NewGormArticleRepository(primary, WithReplicas(rs))
+	}
+	writer, reader := newInstance(), newInstance()
+	ctx := context.Background()
+
+	seedArticle(t, NewGormArticleRepository(replica), "stale")
+	seedArticle(t, NewGormArticleRepository(primary), "original")
+
+	require.NoError(t, writer.Update(ctx, &domain.Article{ID: 1, Title: "edited"}))
+	article, err := reader.GetByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "edited", article.Title, "another instance reads the edit from the primary")
+
+	mr.FastForward(6 * time.Second)
+	article, err = reader.GetByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "stale", article.Title)
+
+	// Without Redis each instance still covers its own writes.
+	require.NoError(t, writer.Update(ctx, &domain.Article{ID: 1, Title: "edited again"}))
+	mr.Close()
+	article, err = writer.GetByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "edited again", article.Title)
+}
+
+func TestReplicaSet_PrunesExpiredWrites(t *testing.T) {
+	rs := NewReplicaSet(newTestDB(t), []Replica{{Name: "replica", DB: newTestDB(t)}}, ReplicaOptions{ReadYourWrites: time.Second})
+	now := time.Now()
+	rs.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	rs.MarkWritten(ctx, "article:1", "article:2")
+	now = now.Add(2 * time.Second)
+	rs.MarkWritten(ctx, "article:3")
+
+	assert.Equal(t, []string{"article:3"}, slices.Collect(maps.Keys(rs.written)), "keys never read again are dropped")
+}
+
+func TestReplicaSet_EjectsAndReadmitsUnhealthyReplicas(t *testing.T) {
+	primary, healthy, failing := newTestDB(t), newTestDB(t), newTestDB(t)
+	var changes []string
+	rs := NewReplicaSet(primary, []Replica{{Name: "healthy", DB: healthy}, {Name: "failing", DB: failing}}, ReplicaOptions{
+		MaxFailures: 2,
+		OnStateChange: func(name string, ok bool, err error) {
+			changes = append(changes, name+map[bool]string{true: " up", false: " down"}[ok])
+		},
+	})
+	ctx := context.Background()
+
+	sqlDB, err := failing.DB()
+	require.NoError(t, err)
+	require.NoError(t, sqlDB.Close())
+
+	rs.probe(ctx)
+	assert.True(t, rs.Healthy()["failing"], "one failure is below the threshold")
+	rs.probe(ctx)
+	assert.Equal(t, map[string]bool{"healthy": true, "failing": false}, rs.Healthy())
+	assert.Equal(t, []string{"failing down"}, changes)
+
+	for i := 0; i < 4; i++ {
+		assert.Same(t, healthy, rs.Reader(ctx))
+	}
+
+	// With every replica ejected, reads fall back to the primary.
+	rs.replicas[0].healthy.Store(false)
+	assert.Same(t, primary, rs.Reader(ctx))
+
+	// A replica that answers again is readmitted.
+	rs.replicas[1].DB = newTestDB(t)
+	rs.probe(ctx)
+	assert.True(t, rs.Healthy()["failing"])
+	assert.Equal(t, []string{"failing down", "healthy up", "failing up"}, changes)
+}
+
+func TestReplicaSet_StartAndClose(t *testing.T) {
+	primary, replica := newTestDB(t), newTestDB(t)
+	rs := NewReplicaSet(primary, []Replica{{Name: "replica", DB: replica}}, ReplicaOptions{CheckInterval: time.Millisecond})
+	rs.Start()
+	time.Sleep(5 * time.Millisecond)
+	require.NoError(t, rs.Close())
+
+	_, err := NewGormArticleRepository(replica).GetAll(context.Background())
+	assert.Error(t, err, "Close closes the replica connections")
+}
@@ -0,0 +1,85 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+
+	"github.com/FormalYou/clean-architecture-blog/internal/application/repository"
+)
+
+// DefaultTxMaxRetries is used when NewTxManager is given a non-positive retry count.
+const DefaultTxMaxRetries = 3
+
+// txBackoff is the delay before the first retry; it doubles on every attempt.
+const txBackoff = 20 * time.Millisecond
+
+// txKey is the context key under which the current transaction is stored.
+type txKey struct{}
+
+// GormTxManager 是 TxManager 的 GORM 实现
+type GormTxManager struct {
+	db         *gorm.DB
+	maxRetries int
+}
+
+// NewTxManager 创建一个新的 GormTxManager。maxRetries 为死锁或序列化失败时的最大重试次数。
+func NewTxManager(db *gorm.DB, maxRetries int) repository.TxManager {
+	if maxRetries <= 0 {
+		maxRetries = DefaultTxMaxRetries
+	}
+	return &GormTxManager{db: db, maxRetries: maxRetries}
+}
+
+// WithinTx 在事务中执行 fn，遇到死锁或序列化失败时整体重试。
+func (m *GormTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		// Already inside a transaction: join it. Only the outermost call retries.
+		return fn(ctx)
+	}
+
+	backoff := txBackoff
+	for attempt := 0; ; attempt++ {
+		err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			return fn(context.WithValue(ctx, txKey{}, tx))
+		})
+		if err == nil || attempt >= m.maxRetries || !isRetryable(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.Join(err, ctx.Err())
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// conn returns the transaction stored in ctx, or db bound to ctx otherwise.
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
+
+// isRetryable reports whether err is a deadlock or serialization failure,
+// after which the whole transaction can safely be run again.
+func isRetryable(err error) bool {
+	var myErr *mysqldriver.MySQLError
+	if errors.As(err, &myErr) {
+		// 1213: deadlock found; 1205: lock wait timeout exceeded.
+		return myErr.Number == 1213 || myErr.Number == 1205
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		// 40001: serialization_failure; 40P01: deadlock_detected.
+		return pgErr.Code == "40001" || pgErr.Code == "40P01"
+	}
+	return false
+}
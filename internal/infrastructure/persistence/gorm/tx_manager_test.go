@@ -0,0 +1,144 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/FormalYou/clean-architecture-blog/domain"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/repository"
+	"github.com/FormalYou/clean-architecture-blog/internal/infrastructure/persistence/migrations"
+)
+
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := NewDB(DSNConfig{Driver: DriverSQLite, DBName: filepath.Join(t.TempDir(), "test.db")})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	})
+	migrator, err := migrations.New(db, DriverSQLite)
+	require.NoError(t, err)
+	_, err = migrator.Up(context.Background())
+	require.NoError(t, err)
+	return db
+}
+
+func TestTxManager_CommitAndRollback(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	txManager := NewTxManager(db, 0)
+	repo := NewGormArticleRepository(db)
+
+	committed := &domain.Article{Title: "committed", AuthorID: 1, Tags: []domain.Tag{{Name: "go"}}}
+	err := txManager.WithinTx(ctx, func(ctx context.Context) error {
+		return repo.Create(ctx, committed)
+	})
+	require.NoError(t, err)
+
+	// Both repository calls run on the transaction from ctx; with SQLite's
+	// single connection a call outside it would block until the timeout.
+	boom := errors.New("boom")
+	rolledBack := &domain.Article{Title: "rolled back", AuthorID: 1, Tags: []domain.Tag{{Name: "sql"}}}
+	err = txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := repo.Create(ctx, rolledBack); err != nil {
+			return err
+		}
+		if err := repo.Delete(ctx, committed.ID); err != nil {
+			return err
+		}
+		return boom
+	})
+	assert.ErrorIs(t, err, boom)
+
+	articles, err := repo.GetAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, articles, 1)
+	assert.Equal(t, "committed", articles[0].Title)
+	_, err = repo.GetByID(ctx, rolledBack.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	var tagCount int64
+	require.NoError(t, db.Model(&TagModel{}).Where("name = ?", "sql").Count(&tagCount).Error)
+	assert.Zero(t, tagCount)
+}
+
+func TestTxManager_NestedCallsJoinOuterTransaction(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	txManager := NewTxManager(db, 0)
+	repo := NewGormArticleRepository(db)
+
+	boom := errors.New("boom")
+	err := txManager.WithinTx(ctx, func(ctx context.Context) error {
+		inner := txManager.WithinTx(ctx, func(ctx context.Context) error {
+			return repo.Create(ctx, &domain.Article{Title: "inner", AuthorID: 1})
+		})
+		require.NoError(t, inner)
+		return boom
+	})
+	assert.ErrorIs(t, err, boom)
+
+	articles, err := repo.GetAll(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, articles)
+}
+
+func TestTxManager_Retries(t *testing.T) {
+	db := newTestDB(t)
+
+	testCases := []struct {
+		name          string
+		err           error
+		maxRetries    int
+		expectedCalls int
+	}{
+		{name: "MySQL deadlock", err: &mysqldriver.MySQLError{Number: 1213}, maxRetries: 3, expectedCalls: 2},
+		{name: "MySQL lock wait timeout", err: &mysqldriver.MySQLError{Number: 1205}, maxRetries: 3, expectedCalls: 2},
+		{name: "Postgres serialization failure", err: &pgconn.PgError{Code: "40001"}, maxRetries: 3, expectedCalls: 2},
+		{name: "Postgres deadlock", err: &pgconn.PgError{Code: "40P01"}, maxRetries: 3, expectedCalls: 2},
+		{name: "Other MySQL error", err: &mysqldriver.MySQLError{Number: 1062}, maxRetries: 3, expectedCalls: 1},
+		{name: "Plain error", err: errors.New("boom"), maxRetries: 3, expectedCalls: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			txManager := NewTxManager(db, tc.maxRetries)
+			calls := 0
+			err := txManager.WithinTx(context.Background(), func(ctx context.Context) error {
+				calls++
+				if calls == 1 {
+					// Wrapped errors, e.g. from a usecase, are still recognised.
+					return errors.Join(errors.New("wrapped"), tc.err)
+				}
+				return nil
+			})
+			assert.Equal(t, tc.expectedCalls, calls)
+			if tc.expectedCalls == 1 {
+				assert.ErrorIs(t, err, tc.err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("Gives up after max retries", func(t *testing.T) {
+		txManager := NewTxManager(db, 2)
+		calls := 0
+		deadlock := &mysqldriver.MySQLError{Number: 1213}
+		err := txManager.WithinTx(context.Background(), func(ctx context.Context) error {
+			calls++
+			return deadlock
+		})
+		assert.ErrorIs(t, err, deadlock)
+		assert.Equal(t, 3, calls)
+	})
+}
@@ -0,0 +1,149 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/FormalYou/clean-architecture-blog/domain"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/repository"
+)
+
+// ArticleRepository 是 ArticleRepository 的内存实现
+type ArticleRepository struct {
+	store *Store
+}
+
+// NewArticleRepository 创建一个新的内存 ArticleRepository
+func NewArticleRepository(store *Store) repository.ArticleRepository {
+	return &ArticleRepository{store: store}
+}
+
+// Create 保存文章，并按名称复用已有标签
+func (r *ArticleRepository) Create(ctx context.Context, article *domain.Article) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range article.Tags {
+		tag := s.tagByName(article.Tags[i].Name)
+		if tag == nil {
+			s.nextTagID++
+			tag = &domain.Tag{ID: s.nextTagID, Name: article.Tags[i].Name}
+			s.tags[tag.ID] = tag
+		}
+		article.Tags[i].ID = tag.ID
+	}
+
+	s.nextArticleID++
+	article.ID = s.nextArticleID
+	article.Version = 1
+	article.CreatedAt = time.Now()
+	article.UpdatedAt = article.CreatedAt
+	s.articles[article.ID] = copyArticle(article)
+	return nil
+}
+
+// GetByID 通过 ID 获取文章
+func (r *ArticleRepository) GetByID(ctx context.Context, id int64) (*domain.Article, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	article, ok := s.articles[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return r.withTags(article), nil
+}
+
+// GetAll 按 ID 顺序获取所有文章
+func (r *ArticleRepository) GetAll(ctx context.Context) ([]*domain.Article, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var articles []*domain.Article
+	for _, article := range s.articles {
+		articles = append(articles, r.withTags(article))
+	}
+	sort.Slice(articles, func(i, j int) bool { return articles[i].ID < articles[j].ID })
+	return articles, nil
+}
+
+// List 按 ID 顺序跳过 offset 篇，返回至多 limit 篇文章
+func (r *ArticleRepository) List(ctx context.Context, limit, offset int) ([]*domain.Article, error) {
+	all, err := r.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if offset > len(all) {
+		offset = len(all)
+	}
+	all = all[offset:]
+	if limit < len(all) {
+		all = all[:limit]
+	}
+	return all, nil
+}
+
+// Update 更新文章的非零字段，版本号加一并刷新更新时间，标签保持不变
+func (r *ArticleRepository) Update(ctx context.Context, article *domain.Article) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.articles[article.ID]
+	if !ok {
+		return nil
+	}
+	if article.Title != "" {
+		existing.Title = article.Title
+	}
+	if article.Content != "" {
+		existing.Content = article.Content
+	}
+	if article.AuthorID != 0 {
+		existing.AuthorID = article.AuthorID
+	}
+	existing.Version++
+	existing.UpdatedAt = time.Now()
+	return nil
+}
+
+// Delete 删除文章，标签保留
+func (r *ArticleRepository) Delete(ctx context.Context, id int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.articles, id)
+	return nil
+}
+
+// withTags returns a copy of article with its tags read from the tag table,
+// so renamed tags are reflected. The caller holds the store lock.
+func (r *ArticleRepository) withTags(article *domain.Article) *domain.Article {
+	c := copyArticle(article)
+	for i := range c.Tags {
+		if tag, ok := r.store.tags[c.Tags[i].ID]; ok {
+			c.Tags[i].Name = tag.Name
+		}
+	}
+	return c
+}
@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/FormalYou/clean-architecture-blog/domain"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/repository"
+)
+
+// CommentRepository 是 CommentRepository 的内存实现
+type CommentRepository struct {
+	store *Store
+}
+
+// NewCommentRepository 创建一个新的内存 CommentRepository
+func NewCommentRepository(store *Store) repository.CommentRepository {
+	return &CommentRepository{store: store}
+}
+
+// FindByArticleID 按 ID 顺序获取文章的评论
+func (r *CommentRepository) FindByArticleID(ctx context.Context, articleID uint) ([]*domain.Comment, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var comments []*domain.Comment
+	for _, comment := range s.comments {
+		if comment.ArticleID == int64(articleID) {
+			comments = append(comments, copyComment(comment))
+		}
+	}
+	sort.Slice(comments, func(i, j int) bool { return comments[i].ID < comments[j].ID })
+	return comments, nil
+}
+
+// FindByArticleIDs 获取多篇文章的评论，按文章 ID 与评论 ID 排序，limit 为正时每篇文章最多 limit 条
+func (r *CommentRepository) FindByArticleIDs(ctx context.Context, articleIDs []int64, limit int) ([]*domain.Comment, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	wanted := make(map[int64]bool, len(articleIDs))
+	for _, id := range articleIDs {
+		wanted[id] = true
+	}
+	var comments []*domain.Comment
+	for _, comment := range s.comments {
+		if wanted[comment.ArticleID] {
+			comments = append(comments, copyComment(comment))
+		}
+	}
+	sort.Slice(comments, func(i, j int) bool {
+		if comments[i].ArticleID != comments[j].ArticleID {
+			return comments[i].ArticleID < comments[j].ArticleID
+		}
+		return comments[i].ID < comments[j].ID
+	})
+	if limit > 0 {
+		kept := comments[:0]
+		perArticle := map[int64]int{}
+		for _, comment := range comments {
+			if perArticle[comment.ArticleID] < limit {
+				perArticle[comment.ArticleID]++
+				kept = append(kept, comment)
+			}
+		}
+		comments = kept
+	}
+	return comments, nil
+}
+
+// Save 创建或更新评论，首次保存时设置 CreatedAt
+func (r *CommentRepository) Save(ctx context.Context, comment *domain.Comment) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if comment.CreatedAt.IsZero() {
+		comment.CreatedAt = time.Now()
+	}
+	if comment.ID == 0 {
+		s.nextCommentID++
+		comment.ID = s.nextCommentID
+	} else if comment.ID > s.nextCommentID {
+		s.nextCommentID = comment.ID
+	}
+	s.comments[comment.ID] = copyComment(comment)
+	return nil
+}
@@ -0,0 +1,62 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FormalYou/clean-architecture-blog/domain"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/repository/repositorytest"
+)
+
+func newRepositories(t *testing.T) repositorytest.Repositories {
+	store := NewStore()
+	return repositorytest.Repositories{
+		Articles: NewArticleRepository(store),
+		Users:    NewUserRepository(store),
+		Tags:     NewTagRepository(store),
+		Comments: NewCommentRepository(store),
+	}
+}
+
+func TestConformance(t *testing.T) {
+	repositorytest.Run(t, newRepositories)
+}
+
+func TestConcurrentAccess(t *testing.T) {
+	repos := newRepositories(t)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			article := &domain.Article{Title: fmt.Sprint(i), Content: "c", AuthorID: 1, Tags: []domain.Tag{{Name: "shared"}}}
+			assert.NoError(t, repos.Articles.Create(ctx, article))
+			_, err := repos.Articles.GetAll(ctx)
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	articles, err := repos.Articles.GetAll(ctx)
+	require.NoError(t, err)
+	assert.Len(t, articles, 50)
+	tags, err := repos.Tags.FindAll(ctx)
+	require.NoError(t, err)
+	assert.Len(t, tags, 1)
+}
+
+func TestCanceledContext(t *testing.T) {
+	repos := newRepositories(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := repos.Users.FindByEmail(ctx, "a@example.com")
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,67 @@
+// Package memory provides thread-safe in-memory repositories for running the
+// application without a database. Data lives only as long as the process.
+package memory
+
+import (
+	"sync"
+
+	"github.com/FormalYou/clean-architecture-blog/domain"
+)
+
+// Store 保存所有内存仓库共享的数据，使文章与标签等跨仓库的关系保持一致。
+type Store struct {
+	mu sync.RWMutex
+
+	articles map[int64]*domain.Article
+	users    map[int64]*domain.User
+	tags     map[int64]*domain.Tag
+	comments map[int64]*domain.Comment
+
+	nextArticleID int64
+	nextUserID    int64
+	nextTagID     int64
+	nextCommentID int64
+}
+
+// NewStore 创建一个空的内存存储
+func NewStore() *Store {
+	return &Store{
+		articles: map[int64]*domain.Article{},
+		users:    map[int64]*domain.User{},
+		tags:     map[int64]*domain.Tag{},
+		comments: map[int64]*domain.Comment{},
+	}
+}
+
+// tagByName returns the stored tag with name, if any. The caller holds mu.
+func (s *Store) tagByName(name string) *domain.Tag {
+	for _, tag := range s.tags {
+		if tag.Name == name {
+			return tag
+		}
+	}
+	return nil
+}
+
+func copyArticle(a *domain.Article) *domain.Article {
+	c := *a
+	if a.Tags != nil {
+		c.Tags = append([]domain.Tag(nil), a.Tags...)
+	}
+	return &c
+}
+
+func copyUser(u *domain.User) *domain.User {
+	c := *u
+	return &c
+}
+
+func copyTag(t *domain.Tag) *domain.Tag {
+	c := *t
+	return &c
+}
+
+func copyComment(cm *domain.Comment) *domain.Comment {
+	c := *cm
+	return &c
+}
@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/FormalYou/clean-architecture-blog/domain"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/repository"
+)
+
+// TagRepository 是 TagRepository 的内存实现
+type TagRepository struct {
+	store *Store
+}
+
+// NewTagRepository 创建一个新的内存 TagRepository
+func NewTagRepository(store *Store) repository.TagRepository {
+	return &TagRepository{store: store}
+}
+
+// FindAll 按 ID 顺序获取所有标签
+func (r *TagRepository) FindAll(ctx context.Context) ([]*domain.Tag, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var tags []*domain.Tag
+	for _, tag := range s.tags {
+		tags = append(tags, copyTag(tag))
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].ID < tags[j].ID })
+	return tags, nil
+}
+
+// FindByName 通过名称获取标签
+func (r *TagRepository) FindByName(ctx context.Context, name string) (*domain.Tag, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tag := s.tagByName(name)
+	if tag == nil {
+		return nil, repository.ErrNotFound
+	}
+	return copyTag(tag), nil
+}
+
+// Save 创建或更新标签，名称必须唯一
+func (r *TagRepository) Save(ctx context.Context, tag *domain.Tag) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if other := s.tagByName(tag.Name); other != nil && other.ID != tag.ID {
+		return ErrDuplicateKey
+	}
+	if tag.ID == 0 {
+		s.nextTagID++
+		tag.ID = s.nextTagID
+	} else if tag.ID > s.nextTagID {
+		s.nextTagID = tag.ID
+	}
+	s.tags[tag.ID] = copyTag(tag)
+	return nil
+}
@@ -0,0 +1,21 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/FormalYou/clean-architecture-blog/internal/application/repository"
+)
+
+// TxManager 是 TxManager 的内存实现。每个仓库操作本身是原子的，但内存存储
+// 不支持回滚：fn 返回错误时，已经执行的写操作不会被撤销。仅用于开发模式。
+type TxManager struct{}
+
+// NewTxManager 创建一个新的内存 TxManager
+func NewTxManager() repository.TxManager {
+	return TxManager{}
+}
+
+// WithinTx 直接执行 fn
+func (TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
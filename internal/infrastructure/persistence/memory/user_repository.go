@@ -0,0 +1,126 @@
+package memory
+
+import (
+	"context"
+	"errors"
+
+	"github.com/FormalYou/clean-architecture-blog/domain"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/repository"
+)
+
+// ErrDuplicateKey mirrors a unique constraint violation in the database.
+var ErrDuplicateKey = errors.New("duplicate key")
+
+// UserRepository 是 UserRepository 的内存实现
+type UserRepository struct {
+	store *Store
+}
+
+// NewUserRepository 创建一个新的内存 UserRepository
+func NewUserRepository(store *Store) repository.UserRepository {
+	return &UserRepository{store: store}
+}
+
+// FindByID 通过 ID 获取用户
+func (r *UserRepository) FindByID(ctx context.Context, id uint) (*domain.User, error) {
+	return r.find(ctx, func(u *domain.User) bool { return u.ID == int64(id) })
+}
+
+// FindByIDs 获取多个用户，不存在的 ID 被忽略
+func (r *UserRepository) FindByIDs(ctx context.Context, ids []int64) ([]*domain.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var users []*domain.User
+	seen := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		if user, ok := s.users[id]; ok && !seen[id] {
+			seen[id] = true
+			users = append(users, copyUser(user))
+		}
+	}
+	return users, nil
+}
+
+// FindByEmail 通过 Email 获取用户
+func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
+	return r.find(ctx, func(u *domain.User) bool { return u.Email == email })
+}
+
+// GetByUsername 通过用户名获取用户
+func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*domain.User, error) {
+	return r.find(ctx, func(u *domain.User) bool { return u.Username == username })
+}
+
+func (r *UserRepository) find(ctx context.Context, match func(*domain.User) bool) (*domain.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, user := range s.users {
+		if match(user) {
+			return copyUser(user), nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+// Save 创建或更新用户
+func (r *UserRepository) Save(ctx context.Context, user *domain.User) error {
+	if user.ID == 0 {
+		return r.Create(ctx, user)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := r.checkUnique(user); err != nil {
+		return err
+	}
+	s.users[user.ID] = copyUser(user)
+	if user.ID > s.nextUserID {
+		s.nextUserID = user.ID
+	}
+	return nil
+}
+
+// Create 创建一个新用户，用户名和 Email 必须唯一
+func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := r.checkUnique(user); err != nil {
+		return err
+	}
+	s.nextUserID++
+	user.ID = s.nextUserID
+	s.users[user.ID] = copyUser(user)
+	return nil
+}
+
+// checkUnique enforces the unique username and email. The caller holds the lock.
+func (r *UserRepository) checkUnique(user *domain.User) error {
+	for _, other := range r.store.users {
+		if other.ID == user.ID {
+			continue
+		}
+		if other.Username == user.Username || other.Email == user.Email {
+			return ErrDuplicateKey
+		}
+	}
+	return nil
+}
@@ -0,0 +1,413 @@
+// Package migrations applies the versioned SQL schema migrations embedded in
+// the binary. Each driver has its own directory of NNNN_name.up.sql and
+// NNNN_name.down.sql files; applied versions are recorded, with a checksum of
+// the up script, in the schema_migrations table.
+package migrations
+
+import (
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:embed mysql/*.sql postgres/*.sql sqlite/*.sql
+var embedded embed.FS
+
+// Drivers lists the drivers that have a migration directory.
+var Drivers = []string{"mysql", "postgres", "sqlite"}
+
+// lockName identifies the advisory lock held while migrating.
+const lockName = "clean_architecture_blog_migrate"
+
+// ErrChecksumMismatch is returned when an applied migration file was edited.
+var ErrChecksumMismatch = errors.New("migration checksum mismatch")
+
+var fileRegex = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Migration is a single versioned schema change.
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+// Status describes a migration and whether it has been applied.
+type Status struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// schemaMigration is the bookkeeping row for an applied migration.
+type schemaMigration struct {
+	Version   int64  `gorm:"primaryKey;autoIncrement:false"`
+	Name      string `gorm:"size:255;not null"`
+	Checksum  string `gorm:"size:64;not null"`
+	AppliedAt time.Time
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Migrator applies and rolls back migrations for one database.
+type Migrator struct {
+	db         *gorm.DB
+	driver     string
+	migrations []Migration
+}
+
+// New creates a Migrator using the migrations embedded for driver.
+func New(db *gorm.DB, driver string) (*Migrator, error) {
+	if driver == "" {
+		driver = "mysql"
+	}
+	sub, err := fs.Sub(embedded, driver)
+	if err != nil {
+		return nil, err
+	}
+	return NewFromFS(db, driver, sub)
+}
+
+// NewFromFS creates a Migrator reading migrations from the root of fsys.
+func NewFromFS(db *gorm.DB, driver string, fsys fs.FS) (*Migrator, error) {
+	migrations, err := load(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, driver: driver, migrations: migrations}, nil
+}
+
+// load reads and pairs up/down files, ordered by version.
+func load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		match := fileRegex.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		version, _ := strconv.ParseInt(match[1], 10, 64)
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has conflicting names %q and %q", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(content)
+			sum := sha256.Sum256(content)
+			m.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up script", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Up applies every pending migration in order and returns the ones applied.
+func (m *Migrator) Up(ctx context.Context) (applied []Migration, err error) {
+	err = m.locked(ctx, func(conn *gorm.DB) error {
+		// Only migrating creates the bookkeeping table; checking the
+		// schema at startup must work with a read-only database user.
+		if err := conn.AutoMigrate(&schemaMigration{}); err != nil {
+			return err
+		}
+		pending, err := m.pending(conn)
+		if err != nil {
+			return err
+		}
+		for _, mig := range pending {
+			if err := m.apply(conn, mig); err != nil {
+				return fmt.Errorf("applying %d_%s: %w", mig.Version, mig.Name, err)
+			}
+			applied = append(applied, mig)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down rolls back the most recent steps migrations and returns them.
+func (m *Migrator) Down(ctx context.Context, steps int) (reverted []Migration, err error) {
+	err = m.locked(ctx, func(conn *gorm.DB) error {
+		if !conn.Migrator().HasTable(&schemaMigration{}) {
+			return nil
+		}
+		var rows []schemaMigration
+		if err := conn.Order("version DESC").Limit(steps).Find(&rows).Error; err != nil {
+			return err
+		}
+		for _, row := range rows {
+			mig, ok := m.find(row.Version)
+			if !ok {
+				return fmt.Errorf("migration %d is applied but has no file", row.Version)
+			}
+			if err := m.revert(conn, mig); err != nil {
+				return fmt.Errorf("reverting %d_%s: %w", mig.Version, mig.Name, err)
+			}
+			reverted = append(reverted, mig)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Status lists every known migration and whether it is applied.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	conn := m.db.WithContext(ctx)
+	applied, err := m.applied(conn)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		s := Status{Version: mig.Version, Name: mig.Name}
+		if row, ok := applied[mig.Version]; ok {
+			s.Applied = true
+			s.AppliedAt = row.AppliedAt
+		}
+		statuses = append(statuses, s)
+	}
+	return statuses, nil
+}
+
+// Pending returns the migrations that have not been applied yet. It fails
+// if an applied migration's file no longer matches its recorded checksum.
+// Like Status it only reads: without a schema_migrations table every
+// migration is pending.
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	return m.pending(m.db.WithContext(ctx))
+}
+
+func (m *Migrator) pending(conn *gorm.DB) ([]Migration, error) {
+	applied, err := m.applied(conn)
+	if err != nil {
+		return nil, err
+	}
+	var pending []Migration
+	for _, mig := range m.migrations {
+		row, ok := applied[mig.Version]
+		if !ok {
+			pending = append(pending, mig)
+			continue
+		}
+		if row.Checksum != mig.Checksum {
+			return nil, fmt.Errorf("%w: %d_%s was modified after it was applied", ErrChecksumMismatch, mig.Version, mig.Name)
+		}
+	}
+	return pending, nil
+}
+
+func (m *Migrator) applied(conn *gorm.DB) (map[int64]schemaMigration, error) {
+	if !conn.Migrator().HasTable(&schemaMigration{}) {
+		return map[int64]schemaMigration{}, nil
+	}
+	var rows []schemaMigration
+	if err := conn.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	applied := make(map[int64]schemaMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+func (m *Migrator) find(version int64) (Migration, bool) {
+	for _, mig := range m.migrations {
+		if mig.Version == version {
+			return mig, true
+		}
+	}
+	return Migration{}, false
+}
+
+func (m *Migrator) apply(conn *gorm.DB, mig Migration) error {
+	return conn.Transaction(func(tx *gorm.DB) error {
+		if err := execScript(tx, mig.Up); err != nil {
+			return err
+		}
+		return tx.Create(&schemaMigration{
+			Version:   mig.Version,
+			Name:      mig.Name,
+			Checksum:  mig.Checksum,
+			AppliedAt: time.Now(),
+		}).Error
+	})
+}
+
+func (m *Migrator) revert(conn *gorm.DB, mig Migration) error {
+	return conn.Transaction(func(tx *gorm.DB) error {
+		if err := execScript(tx, mig.Down); err != nil {
+			return err
+		}
+		return tx.Where("version = ?", mig.Version).Delete(&schemaMigration{}).Error
+	})
+}
+
+// execScript runs each statement of a script separately, since not every
+// driver accepts several statements in one call.
+func execScript(tx *gorm.DB, script string) error {
+	for _, stmt := range splitStatements(script) {
+		if err := tx.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitStatements splits a script on semicolons that end a line and drops
+// comment-only lines. Migrations must not put a statement-ending semicolon
+// inside a string literal.
+func splitStatements(script string) []string {
+	var stmts []string
+	var current strings.Builder
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			stmts = append(stmts, strings.TrimSpace(current.String()))
+			current.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		stmts = append(stmts, rest)
+	}
+	return stmts
+}
+
+// locked runs fn on a single connection while holding the advisory lock, so
+// that concurrently starting instances don't migrate at the same time.
+func (m *Migrator) locked(ctx context.Context, fn func(conn *gorm.DB) error) error {
+	return m.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		if err := m.lock(conn); err != nil {
+			return err
+		}
+		defer m.unlock(conn)
+		return fn(conn)
+	})
+}
+
+func (m *Migrator) lock(conn *gorm.DB) error {
+	switch m.driver {
+	case "mysql":
+		var ok int
+		if err := conn.Raw("SELECT GET_LOCK(?, ?)", lockName, 60).Scan(&ok).Error; err != nil {
+			return err
+		}
+		if ok != 1 {
+			return errors.New("timed out waiting for the migration lock")
+		}
+		return nil
+	case "postgres":
+		return conn.Exec("SELECT pg_advisory_lock(?)", lockKey()).Error
+	default:
+		// SQLite serialises writers itself.
+		return nil
+	}
+}
+
+func (m *Migrator) unlock(conn *gorm.DB) {
+	switch m.driver {
+	case "mysql":
+		conn.Exec("SELECT RELEASE_LOCK(?)", lockName)
+	case "postgres":
+		conn.Exec("SELECT pg_advisory_unlock(?)", lockKey())
+	}
+}
+
+// lockKey derives the numeric PostgreSQL advisory lock key from lockName.
+func lockKey() int64 {
+	h := fnv.New64a()
+	h.Write([]byte(lockName))
+	return int64(h.Sum64())
+}
+
+// Create writes empty up/down files for a new migration in every driver
+// directory under dir and returns their paths.
+func Create(dir, name string) ([]string, error) {
+	name = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(name), "-", "_"))
+	if !regexp.MustCompile(`^[a-z0-9_]+$`).MatchString(name) {
+		return nil, fmt.Errorf("invalid migration name %q: use letters, digits and underscores", name)
+	}
+
+	var next int64 = 1
+	for _, driver := range Drivers {
+		migrations, err := load(os.DirFS(filepath.Join(dir, driver)))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		if n := len(migrations); n > 0 && migrations[n-1].Version >= next {
+			next = migrations[n-1].Version + 1
+		}
+	}
+
+	var created []string
+	for _, driver := range Drivers {
+		if err := os.MkdirAll(filepath.Join(dir, driver), 0o755); err != nil {
+			return created, err
+		}
+		for _, direction := range []string{"up", "down"} {
+			file := filepath.Join(dir, driver, fmt.Sprintf("%04d_%s.%s.sql", next, name, direction))
+			if err := writeNewFile(file, fmt.Sprintf("-- %s migration %04d_%s (%s)\n", direction, next, name, driver)); err != nil {
+				return created, err
+			}
+			created = append(created, file)
+		}
+	}
+	return created, nil
+}
+
+// writeNewFile creates file with content, refusing to overwrite it.
+func writeNewFile(file, content string) error {
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(content); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,161 @@
+package migrations
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	gorm_infra "github.com/FormalYou/clean-architecture-blog/internal/infrastructure/persistence/gorm"
+)
+
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm_infra.NewDB(gorm_infra.DSNConfig{Driver: gorm_infra.DriverSQLite, DBName: filepath.Join(t.TempDir(), "test.db")})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	})
+	return db
+}
+
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		"0001_create_a.up.sql":   {Data: []byte("-- table a\nCREATE TABLE a (id INTEGER PRIMARY KEY);\n")},
+		"0001_create_a.down.sql": {Data: []byte("DROP TABLE a;\n")},
+		"0002_create_b.up.sql":   {Data: []byte("CREATE TABLE b (id INTEGER PRIMARY KEY);\nINSERT INTO b (id) VALUES (1);\n")},
+		"0002_create_b.down.sql": {Data: []byte("DROP TABLE b;\n")},
+		"README.md":              {Data: []byte("ignored")},
+	}
+}
+
+func TestMigrator_UpStatusDown(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	m, err := NewFromFS(db, "sqlite", testFS())
+	require.NoError(t, err)
+
+	pending, err := m.Pending(ctx)
+	require.NoError(t, err)
+	assert.Len(t, pending, 2)
+	statuses, err := m.Status(ctx)
+	require.NoError(t, err)
+	assert.Len(t, statuses, 2)
+	assert.False(t, db.Migrator().HasTable("schema_migrations"), "checking the schema runs no DDL")
+	reverted, err := m.Down(ctx, 1)
+	require.NoError(t, err)
+	assert.Empty(t, reverted, "nothing to roll back before the first migration")
+
+	applied, err := m.Up(ctx)
+	require.NoError(t, err)
+	require.Len(t, applied, 2)
+	assert.Equal(t, int64(1), applied[0].Version)
+	assert.Equal(t, "create_b", applied[1].Name)
+	assert.True(t, db.Migrator().HasTable("a"))
+	assert.True(t, db.Migrator().HasTable("b"))
+
+	// Running again is a no-op.
+	applied, err = m.Up(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+
+	statuses, err = m.Status(ctx)
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	for _, s := range statuses {
+		assert.True(t, s.Applied)
+		assert.False(t, s.AppliedAt.IsZero())
+	}
+
+	reverted, err = m.Down(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, reverted, 1)
+	assert.Equal(t, int64(2), reverted[0].Version)
+	assert.False(t, db.Migrator().HasTable("b"))
+	assert.True(t, db.Migrator().HasTable("a"))
+
+	pending, err = m.Pending(ctx)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, int64(2), pending[0].Version)
+}
+
+func TestMigrator_ChecksumMismatch(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	fsys := testFS()
+	m, err := NewFromFS(db, "sqlite", fsys)
+	require.NoError(t, err)
+	_, err = m.Up(ctx)
+	require.NoError(t, err)
+
+	fsys["0001_create_a.up.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE a (id INTEGER PRIMARY KEY, name TEXT);\n")}
+	m, err = NewFromFS(db, "sqlite", fsys)
+	require.NoError(t, err)
+
+	_, err = m.Pending(ctx)
+	assert.ErrorIs(t, err, ErrChecksumMismatch)
+	_, err = m.Up(ctx)
+	assert.ErrorIs(t, err, ErrChecksumMismatch)
+}
+
+func TestMigrator_FailedMigrationIsRolledBack(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	fsys := testFS()
+	fsys["0003_broken.up.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE c (id INTEGER);\nNOT VALID SQL;\n")}
+	m, err := NewFromFS(db, "sqlite", fsys)
+	require.NoError(t, err)
+
+	applied, err := m.Up(ctx)
+	require.Error(t, err)
+	assert.Len(t, applied, 2)
+	assert.False(t, db.Migrator().HasTable("c"))
+
+	pending, err := m.Pending(ctx)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, "broken", pending[0].Name)
+}
+
+func TestEmbeddedMigrations_SQLite(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	for _, driver := range Drivers {
+		_, err := New(db, driver)
+		require.NoError(t, err, driver)
+	}
+
+	m, err := New(db, "sqlite")
+	require.NoError(t, err)
+	_, err = m.Up(ctx)
+	require.NoError(t, err)
+	for _, table := range []string{"user_models", "article_models", "tag_models", "article_tags", "comment_models"} {
+		assert.True(t, db.Migrator().HasTable(table), table)
+	}
+
+	_, err = m.Down(ctx, len(m.migrations))
+	require.NoError(t, err)
+	assert.False(t, db.Migrator().HasTable("article_models"))
+}
+
+func TestCreate(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "sqlite"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sqlite", "0007_old.up.sql"), []byte("SELECT 1;"), 0o644))
+
+	files, err := Create(dir, "Add-Index")
+	require.NoError(t, err)
+	assert.Len(t, files, 6)
+	assert.FileExists(t, filepath.Join(dir, "postgres", "0008_add_index.up.sql"))
+	assert.FileExists(t, filepath.Join(dir, "sqlite", "0008_add_index.down.sql"))
+
+	_, err = Create(dir, "bad name!")
+	assert.Error(t, err)
+}
@@ -0,0 +1,45 @@
+-- 初始表结构，与此前由 GORM AutoMigrate 创建的结构保持一致，
+-- 使用 IF NOT EXISTS 以便已有数据库直接纳入版本管理。
+CREATE TABLE IF NOT EXISTS user_models (
+    id            BIGINT AUTO_INCREMENT PRIMARY KEY,
+    username      VARCHAR(191) NOT NULL,
+    password_hash LONGTEXT     NOT NULL,
+    email         VARCHAR(191) NOT NULL,
+    nickname      LONGTEXT,
+    avatar        LONGTEXT,
+    created_at    DATETIME(3),
+    updated_at    DATETIME(3),
+    CONSTRAINT uni_user_models_username UNIQUE (username),
+    CONSTRAINT uni_user_models_email UNIQUE (email)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS article_models (
+    id         BIGINT AUTO_INCREMENT PRIMARY KEY,
+    title      LONGTEXT NOT NULL,
+    content    TEXT,
+    author_id  BIGINT   NOT NULL,
+    created_at DATETIME(3),
+    updated_at DATETIME(3)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS tag_models (
+    id   BIGINT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(191) NOT NULL,
+    CONSTRAINT uni_tag_models_name UNIQUE (name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS article_tags (
+    article_model_id BIGINT NOT NULL,
+    tag_model_id     BIGINT NOT NULL,
+    PRIMARY KEY (article_model_id, tag_model_id),
+    CONSTRAINT fk_article_tags_article_model FOREIGN KEY (article_model_id) REFERENCES article_models (id),
+    CONSTRAINT fk_article_tags_tag_model FOREIGN KEY (tag_model_id) REFERENCES tag_models (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS comment_models (
+    id         BIGINT AUTO_INCREMENT PRIMARY KEY,
+    article_id BIGINT NOT NULL,
+    user_id    BIGINT NOT NULL,
+    content    TEXT   NOT NULL,
+    created_at DATETIME(3)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
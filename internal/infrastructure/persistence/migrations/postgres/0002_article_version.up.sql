@@ -0,0 +1,2 @@
+-- 文章版本号，每次更新加一，用于计算 ETag。
+ALTER TABLE article_models ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS comment_models;
+DROP TABLE IF EXISTS article_tags;
+DROP TABLE IF EXISTS tag_models;
+DROP TABLE IF EXISTS article_models;
+DROP TABLE IF EXISTS user_models;
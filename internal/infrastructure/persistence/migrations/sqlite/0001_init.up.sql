@@ -0,0 +1,45 @@
+-- 初始表结构，与此前由 GORM AutoMigrate 创建的结构保持一致，
+-- 使用 IF NOT EXISTS 以便已有数据库直接纳入版本管理。
+CREATE TABLE IF NOT EXISTS user_models (
+    id            INTEGER PRIMARY KEY AUTOINCREMENT,
+    username      TEXT NOT NULL,
+    password_hash TEXT NOT NULL,
+    email         TEXT NOT NULL,
+    nickname      TEXT,
+    avatar        TEXT,
+    created_at    DATETIME,
+    updated_at    DATETIME,
+    CONSTRAINT uni_user_models_username UNIQUE (username),
+    CONSTRAINT uni_user_models_email UNIQUE (email)
+);
+
+CREATE TABLE IF NOT EXISTS article_models (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    title      TEXT    NOT NULL,
+    content    TEXT,
+    author_id  INTEGER NOT NULL,
+    created_at DATETIME,
+    updated_at DATETIME
+);
+
+CREATE TABLE IF NOT EXISTS tag_models (
+    id   INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    CONSTRAINT uni_tag_models_name UNIQUE (name)
+);
+
+CREATE TABLE IF NOT EXISTS article_tags (
+    article_model_id INTEGER NOT NULL,
+    tag_model_id     INTEGER NOT NULL,
+    PRIMARY KEY (article_model_id, tag_model_id),
+    CONSTRAINT fk_article_tags_article_model FOREIGN KEY (article_model_id) REFERENCES article_models (id),
+    CONSTRAINT fk_article_tags_tag_model FOREIGN KEY (tag_model_id) REFERENCES tag_models (id)
+);
+
+CREATE TABLE IF NOT EXISTS comment_models (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    article_id INTEGER NOT NULL,
+    user_id    INTEGER NOT NULL,
+    content    TEXT    NOT NULL,
+    created_at DATETIME
+);
@@ -0,0 +1,27 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/FormalYou/clean-architecture-blog/internal/application/contracts"
+)
+
+type fallbackLimiter struct {
+	primary  contracts.RateLimiter
+	fallback contracts.RateLimiter
+}
+
+// NewFallbackLimiter 在 primary 出错（如 Redis 熔断）时改用 fallback 计数，
+// 限流在降级期间按实例生效而不是完全失效
+func NewFallbackLimiter(primary, fallback contracts.RateLimiter) contracts.RateLimiter {
+	return &fallbackLimiter{primary: primary, fallback: fallback}
+}
+
+func (l *fallbackLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (contracts.RateLimit, error) {
+	result, err := l.primary.Allow(ctx, key, limit, window)
+	if err == nil {
+		return result, nil
+	}
+	return l.fallback.Allow(ctx, key, limit, window)
+}
@@ -0,0 +1,47 @@
+// Package ratelimit implements contracts.RateLimiter with the generic cell
+// rate algorithm (GCRA), a token bucket that stores a single timestamp per
+// key: the theoretical arrival time (TAT) at which the bucket is full again.
+// Every admitted request pushes the TAT one emission interval
+// (window / limit) further; a request is rejected while that would put the
+// TAT more than one window ahead of now.
+package ratelimit
+
+import (
+	"time"
+
+	"github.com/FormalYou/clean-architecture-blog/internal/application/contracts"
+)
+
+// interval is the time one request "costs" when limit requests are allowed
+// per window.
+func interval(limit int, window time.Duration) time.Duration {
+	if d := window / time.Duration(limit); d > 0 {
+		return d
+	}
+	return 1
+}
+
+// gcra decides a request arriving at now against the stored tat and returns
+// the decision together with the TAT to store; the stored value must not
+// change when the request is rejected.
+func gcra(now, tat time.Time, limit int, window time.Duration) (contracts.RateLimit, time.Time) {
+	step := interval(limit, window)
+	if tat.Before(now) {
+		tat = now
+	}
+	next := tat.Add(step)
+	allowAt := next.Add(-window)
+	if now.Before(allowAt) {
+		return contracts.RateLimit{
+			Limit:      limit,
+			Reset:      tat.Sub(now),
+			RetryAfter: allowAt.Sub(now),
+		}, tat
+	}
+	return contracts.RateLimit{
+		Allowed:   true,
+		Limit:     limit,
+		Remaining: int(now.Sub(allowAt) / step),
+		Reset:     next.Sub(now),
+	}, next
+}
@@ -0,0 +1,57 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/FormalYou/clean-architecture-blog/internal/application/contracts"
+)
+
+// sweepInterval is how often the memory limiter forgets keys whose bucket
+// has refilled completely.
+const sweepInterval = time.Minute
+
+// MemoryLimiter keeps the buckets in process memory, so every instance
+// enforces its own limits.
+type MemoryLimiter struct {
+	mu        sync.Mutex
+	tats      map[string]time.Time
+	nextSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryLimiter 创建进程内限流器，用于内存存储模式和 Redis 不可用时的降级
+func NewMemoryLimiter() *MemoryLimiter {
+	return newMemoryLimiter(time.Now)
+}
+
+func newMemoryLimiter(now func() time.Time) *MemoryLimiter {
+	return &MemoryLimiter{tats: make(map[string]time.Time), now: now}
+}
+
+func (l *MemoryLimiter) Allow(_ context.Context, key string, limit int, window time.Duration) (contracts.RateLimit, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.After(l.nextSweep) {
+		for k, tat := range l.tats {
+			if !tat.After(now) {
+				delete(l.tats, k)
+			}
+		}
+		l.nextSweep = now.Add(sweepInterval)
+	}
+
+	result, tat := gcra(now, l.tats[key], limit, window)
+	l.tats[key] = tat
+	return result, nil
+}
+
+// Len returns the number of tracked keys.
+func (l *MemoryLimiter) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.tats)
+}
@@ -0,0 +1,139 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FormalYou/clean-architecture-blog/internal/application/contracts"
+)
+
+// runLimiter checks the behaviour every limiter shares: limit requests per
+// window with the quota refilling one request per window/limit.
+func runLimiter(t *testing.T, newLimiter func(t *testing.T) (contracts.RateLimiter, func(time.Duration))) {
+	ctx := context.Background()
+
+	t.Run("Burst up to the limit", func(t *testing.T) {
+		limiter, _ := newLimiter(t)
+		for i := 0; i < 3; i++ {
+			res, err := limiter.Allow(ctx, "burst", 3, 30*time.Second)
+			require.NoError(t, err)
+			assert.True(t, res.Allowed, "request %d", i)
+			assert.Equal(t, 3, res.Limit)
+			assert.Equal(t, 2-i, res.Remaining)
+			assert.Equal(t, time.Duration(i+1)*10*time.Second, res.Reset)
+		}
+
+		res, err := limiter.Allow(ctx, "burst", 3, 30*time.Second)
+		require.NoError(t, err)
+		assert.False(t, res.Allowed)
+		assert.Equal(t, 0, res.Remaining)
+		assert.Equal(t, 10*time.Second, res.RetryAfter)
+		assert.Equal(t, 30*time.Second, res.Reset)
+	})
+
+	t.Run("Refills over time", func(t *testing.T) {
+		limiter, advance := newLimiter(t)
+		for i := 0; i < 2; i++ {
+			res, err := limiter.Allow(ctx, "refill", 2, 10*time.Second)
+			require.NoError(t, err)
+			require.True(t, res.Allowed)
+		}
+		res, _ := limiter.Allow(ctx, "refill", 2, 10*time.Second)
+		require.False(t, res.Allowed)
+
+		advance(4 * time.Second)
+		res, _ = limiter.Allow(ctx, "refill", 2, 10*time.Second)
+		assert.False(t, res.Allowed, "rejected requests must not consume quota")
+		assert.Equal(t, time.Second, res.RetryAfter)
+
+		advance(time.Second)
+		res, _ = limiter.Allow(ctx, "refill", 2, 10*time.Second)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, 0, res.Remaining)
+
+		advance(time.Minute)
+		res, _ = limiter.Allow(ctx, "refill", 2, 10*time.Second)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, 1, res.Remaining, "the bucket never holds more than limit")
+	})
+
+	t.Run("Keys are independent", func(t *testing.T) {
+		limiter, _ := newLimiter(t)
+		res, _ := limiter.Allow(ctx, "a", 1, time.Minute)
+		require.True(t, res.Allowed)
+		res, _ = limiter.Allow(ctx, "a", 1, time.Minute)
+		require.False(t, res.Allowed)
+
+		res, _ = limiter.Allow(ctx, "b", 1, time.Minute)
+		assert.True(t, res.Allowed)
+	})
+}
+
+func TestMemoryLimiter(t *testing.T) {
+	runLimiter(t, func(t *testing.T) (contracts.RateLimiter, func(time.Duration)) {
+		now := time.Now()
+		limiter := newMemoryLimiter(func() time.Time { return now })
+		return limiter, func(d time.Duration) { now = now.Add(d) }
+	})
+}
+
+func TestMemoryLimiter_ForgetsFullBuckets(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	limiter := newMemoryLimiter(func() time.Time { return now })
+
+	_, _ = limiter.Allow(ctx, "a", 10, time.Second)
+	_, _ = limiter.Allow(ctx, "b", 10, time.Hour)
+	require.Equal(t, 2, limiter.Len())
+
+	now = now.Add(2 * sweepInterval)
+	_, _ = limiter.Allow(ctx, "c", 10, time.Second)
+	assert.Equal(t, 2, limiter.Len(), "a refilled and is forgotten; b is still draining")
+}
+
+func TestRedisLimiter(t *testing.T) {
+	runLimiter(t, func(t *testing.T) (contracts.RateLimiter, func(time.Duration)) {
+		mr := miniredis.RunT(t)
+		client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+		t.Cleanup(func() { _ = client.Close() })
+
+		now := time.Now()
+		limiter := &redisLimiter{redisClient: client, now: func() time.Time { return now }}
+		return limiter, func(d time.Duration) {
+			now = now.Add(d)
+			mr.FastForward(d)
+		}
+	})
+}
+
+func TestFallbackLimiter(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { _ = client.Close() })
+
+	fallback := NewMemoryLimiter()
+	limiter := NewFallbackLimiter(NewRedisLimiter(client), fallback)
+
+	res, err := limiter.Allow(ctx, "k", 1, time.Minute)
+	require.NoError(t, err)
+	require.True(t, res.Allowed)
+	assert.True(t, mr.Exists(keyPrefix+"k"))
+	assert.Zero(t, fallback.Len())
+
+	mr.Close()
+	res, err = limiter.Allow(ctx, "k", 1, time.Minute)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed, "the fallback starts with a fresh quota")
+	assert.Equal(t, 1, fallback.Len())
+
+	res, err = limiter.Allow(ctx, "k", 1, time.Minute)
+	require.NoError(t, err)
+	assert.False(t, res.Allowed)
+}
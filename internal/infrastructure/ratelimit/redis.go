@@ -0,0 +1,61 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/FormalYou/clean-architecture-blog/internal/application/contracts"
+)
+
+// keyPrefix namespaces the limiter's keys in Redis.
+const keyPrefix = "ratelimit:"
+
+// gcraScript is gcra in Lua, working in milliseconds so that every instance
+// shares one bucket per key. The caller's clock is used rather than TIME so
+// the script stays deterministic for replication.
+var gcraScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local step = tonumber(ARGV[3])
+local tat = tonumber(redis.call("GET", KEYS[1])) or now
+if tat < now then
+	tat = now
+end
+local next = tat + step
+local allow_at = next - window
+if now < allow_at then
+	return {0, 0, tat - now, allow_at - now}
+end
+redis.call("SET", KEYS[1], next, "PX", next - now)
+return {1, math.floor((now - allow_at) / step), next - now, 0}`)
+
+type redisLimiter struct {
+	redisClient *redis.Client
+	now         func() time.Time
+}
+
+// NewRedisLimiter 创建基于 Redis 的限流器，所有实例共享同一份配额
+func NewRedisLimiter(redisClient *redis.Client) contracts.RateLimiter {
+	return &redisLimiter{redisClient: redisClient, now: time.Now}
+}
+
+func (l *redisLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (contracts.RateLimit, error) {
+	step := interval(limit, window).Milliseconds()
+	if step < 1 {
+		step = 1
+	}
+	values, err := gcraScript.Run(ctx, l.redisClient, []string{keyPrefix + key},
+		l.now().UnixMilli(), window.Milliseconds(), step).Int64Slice()
+	if err != nil {
+		return contracts.RateLimit{}, err
+	}
+	return contracts.RateLimit{
+		Allowed:    values[0] == 1,
+		Limit:      limit,
+		Remaining:  int(values[1]),
+		Reset:      time.Duration(values[2]) * time.Millisecond,
+		RetryAfter: time.Duration(values[3]) * time.Millisecond,
+	}, nil
+}
@@ -0,0 +1,36 @@
+package tracing
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/extra/redisotel/v9"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/otel"
+	"gorm.io/gorm"
+	gormtracing "gorm.io/plugin/opentelemetry/tracing"
+)
+
+// GinMiddleware starts a server span for every request and extracts the
+// incoming W3C traceparent header. Health probes are not traced.
+func GinMiddleware(serviceName string) gin.HandlerFunc {
+	return otelgin.Middleware(serviceName,
+		otelgin.WithTracerProvider(provider),
+		otelgin.WithPropagators(otel.GetTextMapPropagator()),
+		otelgin.WithGinFilter(func(c *gin.Context) bool {
+			return c.FullPath() != "/healthz" && c.FullPath() != "/readyz"
+		}),
+	)
+}
+
+// InstrumentGorm registers a GORM plugin that creates a span per query.
+func InstrumentGorm(db *gorm.DB) error {
+	return db.Use(gormtracing.NewPlugin(
+		gormtracing.WithTracerProvider(provider),
+		gormtracing.WithoutMetrics(),
+	))
+}
+
+// InstrumentRedis adds a hook that creates a span per Redis command.
+func InstrumentRedis(rdb *redis.Client) error {
+	return redisotel.InstrumentTracing(rdb, redisotel.WithTracerProvider(provider))
+}
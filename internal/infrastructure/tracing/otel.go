@@ -0,0 +1,137 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// Supported exporter names.
+const (
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+	ExporterMemory = "memory"
+)
+
+// InstrumentationName is the name used for tracers created by this application.
+const InstrumentationName = "github.com/FormalYou/clean-architecture-blog"
+
+// Config holds the tracing settings.
+type Config struct {
+	Enabled      bool
+	ServiceName  string
+	Exporter     string
+	OTLPEndpoint string
+	Insecure     bool
+	SampleRatio  float64
+}
+
+var (
+	provider trace.TracerProvider = noop.NewTracerProvider()
+	sdk      *sdktrace.TracerProvider
+	// MemoryExporter holds finished spans when the "memory" exporter is selected.
+	MemoryExporter *tracetest.InMemoryExporter
+)
+
+// Init configures the global tracer provider and the W3C trace context propagator.
+// When tracing is disabled a no-op provider is installed.
+func Init(ctx context.Context, cfg Config) error {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if !cfg.Enabled {
+		provider = noop.NewTracerProvider()
+		otel.SetTracerProvider(provider)
+		return nil
+	}
+
+	exporter, err := newExporter(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	serviceName := cfg.ServiceName
+	if serviceName == "" {
+		serviceName = "clean-architecture-blog"
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(serviceName),
+	))
+	if err != nil {
+		return err
+	}
+
+	ratio := cfg.SampleRatio
+	if ratio <= 0 || ratio > 1 {
+		ratio = 1
+	}
+
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	}
+	if cfg.Exporter == ExporterMemory {
+		// Export synchronously so tests can assert on spans as soon as they end.
+		opts = append(opts, sdktrace.WithSyncer(exporter))
+	} else {
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+
+	sdk = sdktrace.NewTracerProvider(opts...)
+	provider = sdk
+	otel.SetTracerProvider(provider)
+	return nil
+}
+
+func newExporter(ctx context.Context, cfg Config) (sdktrace.SpanExporter, error) {
+	switch cfg.Exporter {
+	case "", ExporterOTLP:
+		opts := []otlptracehttp.Option{}
+		if cfg.OTLPEndpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(cfg.OTLPEndpoint))
+		}
+		if cfg.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		return otlptracehttp.New(ctx, opts...)
+	case ExporterStdout:
+		return stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case ExporterMemory:
+		MemoryExporter = tracetest.NewInMemoryExporter()
+		return MemoryExporter, nil
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter: %q", cfg.Exporter)
+	}
+}
+
+// TracerProvider returns the configured tracer provider.
+func TracerProvider() trace.TracerProvider {
+	return provider
+}
+
+// Tracer returns the application tracer.
+func Tracer() trace.Tracer {
+	return provider.Tracer(InstrumentationName)
+}
+
+// Shutdown flushes any pending spans and stops the exporter.
+func Shutdown(ctx context.Context) error {
+	if sdk == nil {
+		return nil
+	}
+	return sdk.Shutdown(ctx)
+}
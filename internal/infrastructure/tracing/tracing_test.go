@@ -0,0 +1,87 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/FormalYou/clean-architecture-blog/domain"
+	mock_usecase "github.com/FormalYou/clean-architecture-blog/internal/application/usecase/mocks"
+	"github.com/FormalYou/clean-architecture-blog/internal/infrastructure/log"
+)
+
+func TestTracing_PropagatesTraceparentToUsecaseSpans(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	require.NoError(t, Init(context.Background(), Config{Enabled: true, Exporter: ExporterMemory}))
+	defer Shutdown(context.Background())
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockArticleUsecase := mock_usecase.NewMockArticleUsecaseInterface(ctrl)
+	mockArticleUsecase.EXPECT().GetArticleByID(gomock.Any(), int64(1)).Return(&domain.Article{ID: 1}, nil)
+	articleUsecase := TraceArticleUsecase(mockArticleUsecase)
+
+	var traceID string
+	router := gin.New()
+	router.Use(GinMiddleware("test"))
+	router.GET("/articles/:id", func(c *gin.Context) {
+		for _, f := range log.TraceFields(c.Request.Context()) {
+			if f.Key == "trace_id" {
+				traceID = f.String
+			}
+		}
+		_, _ = articleUsecase.GetArticleByID(c.Request.Context(), 1)
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/articles/1", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", traceID)
+
+	spans := MemoryExporter.GetSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "ArticleUsecase.GetArticleByID", spans[0].Name)
+	assert.Equal(t, "GET /articles/:id", spans[1].Name)
+	for _, span := range spans {
+		assert.Equal(t, traceID, span.SpanContext.TraceID().String())
+	}
+	assert.Equal(t, spans[1].SpanContext.SpanID(), spans[0].Parent.SpanID())
+}
+
+func TestGRPCUnaryInterceptor_ContinuesTraceparent(t *testing.T) {
+	require.NoError(t, Init(context.Background(), Config{Enabled: true, Exporter: ExporterMemory}))
+	defer Shutdown(context.Background())
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		"traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"))
+	info := &grpc.UnaryServerInfo{FullMethod: "/blog.v1.ArticleService/GetArticle"}
+	_, err := GRPCUnaryInterceptor()(ctx, nil, info, func(ctx context.Context, _ interface{}) (interface{}, error) {
+		return nil, status.Error(codes.NotFound, "article not found")
+	})
+	require.Error(t, err)
+
+	spans := MemoryExporter.GetSpans()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "blog.v1.ArticleService/GetArticle", span.Name)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", span.Parent.SpanID().String())
+	assert.Contains(t, span.Attributes, attribute.String("rpc.method", "GetArticle"))
+	assert.Contains(t, span.Attributes, attribute.Int("rpc.grpc.status_code", int(codes.NotFound)))
+	assert.Equal(t, otelcodes.Unset, span.Status.Code, "client errors do not fail the span")
+}
@@ -0,0 +1,118 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/FormalYou/clean-architecture-blog/domain"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/usecase"
+)
+
+// startSpan starts a span named after the usecase method.
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// endSpan records the error, if any, and ends the span.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// tracedArticleUsecase wraps an ArticleUsecaseInterface with a span per method.
+type tracedArticleUsecase struct {
+	next usecase.ArticleUsecaseInterface
+}
+
+// TraceArticleUsecase decorates the article usecase with tracing spans.
+func TraceArticleUsecase(next usecase.ArticleUsecaseInterface) usecase.ArticleUsecaseInterface {
+	return &tracedArticleUsecase{next: next}
+}
+
+func (t *tracedArticleUsecase) CreateArticle(ctx context.Context, article *domain.Article) (err error) {
+	ctx, span := startSpan(ctx, "ArticleUsecase.CreateArticle")
+	defer func() { endSpan(span, err) }()
+	err = t.next.CreateArticle(ctx, article)
+	span.SetAttributes(attribute.Int64("article.id", article.ID))
+	return err
+}
+
+func (t *tracedArticleUsecase) GetArticleByID(ctx context.Context, id int64) (_ *domain.Article, err error) {
+	ctx, span := startSpan(ctx, "ArticleUsecase.GetArticleByID", attribute.Int64("article.id", id))
+	defer func() { endSpan(span, err) }()
+	return t.next.GetArticleByID(ctx, id)
+}
+
+func (t *tracedArticleUsecase) GetAllArticles(ctx context.Context) (_ []*domain.Article, err error) {
+	ctx, span := startSpan(ctx, "ArticleUsecase.GetAllArticles")
+	defer func() { endSpan(span, err) }()
+	return t.next.GetAllArticles(ctx)
+}
+
+func (t *tracedArticleUsecase) ListArticles(ctx context.Context, limit, offset int) (_ []*domain.Article, err error) {
+	ctx, span := startSpan(ctx, "ArticleUsecase.ListArticles", attribute.Int("limit", limit), attribute.Int("offset", offset))
+	defer func() { endSpan(span, err) }()
+	return t.next.ListArticles(ctx, limit, offset)
+}
+
+func (t *tracedArticleUsecase) UpdateArticle(ctx context.Context, article *domain.Article) (err error) {
+	ctx, span := startSpan(ctx, "ArticleUsecase.UpdateArticle", attribute.Int64("article.id", article.ID))
+	defer func() { endSpan(span, err) }()
+	return t.next.UpdateArticle(ctx, article)
+}
+
+func (t *tracedArticleUsecase) DeleteArticle(ctx context.Context, id int64) (err error) {
+	ctx, span := startSpan(ctx, "ArticleUsecase.DeleteArticle", attribute.Int64("article.id", id))
+	defer func() { endSpan(span, err) }()
+	return t.next.DeleteArticle(ctx, id)
+}
+
+// tracedUserUsecase wraps a UserUsecaseInterface with a span per method.
+type tracedUserUsecase struct {
+	next usecase.UserUsecaseInterface
+}
+
+// TraceUserUsecase decorates the user usecase with tracing spans.
+func TraceUserUsecase(next usecase.UserUsecaseInterface) usecase.UserUsecaseInterface {
+	return &tracedUserUsecase{next: next}
+}
+
+func (t *tracedUserUsecase) Register(ctx context.Context, user *domain.User) (err error) {
+	ctx, span := startSpan(ctx, "UserUsecase.Register")
+	defer func() { endSpan(span, err) }()
+	return t.next.Register(ctx, user)
+}
+
+func (t *tracedUserUsecase) Login(ctx context.Context, email, password string) (_ string, err error) {
+	ctx, span := startSpan(ctx, "UserUsecase.Login")
+	defer func() { endSpan(span, err) }()
+	return t.next.Login(ctx, email, password)
+}
+
+func (t *tracedUserUsecase) GetUsersByIDs(ctx context.Context, ids []int64) (_ map[int64]*domain.User, err error) {
+	ctx, span := startSpan(ctx, "UserUsecase.GetUsersByIDs", attribute.Int("user.count", len(ids)))
+	defer func() { endSpan(span, err) }()
+	return t.next.GetUsersByIDs(ctx, ids)
+}
+
+// tracedCommentUsecase wraps a CommentUsecaseInterface with a span per method.
+type tracedCommentUsecase struct {
+	next usecase.CommentUsecaseInterface
+}
+
+// TraceCommentUsecase decorates the comment usecase with tracing spans.
+func TraceCommentUsecase(next usecase.CommentUsecaseInterface) usecase.CommentUsecaseInterface {
+	return &tracedCommentUsecase{next: next}
+}
+
+func (t *tracedCommentUsecase) GetCommentsByArticleIDs(ctx context.Context, articleIDs []int64, limit int) (_ map[int64][]*domain.Comment, err error) {
+	ctx, span := startSpan(ctx, "CommentUsecase.GetCommentsByArticleIDs", attribute.Int("article.count", len(articleIDs)), attribute.Int("comment.limit", limit))
+	defer func() { endSpan(span, err) }()
+	return t.next.GetCommentsByArticleIDs(ctx, articleIDs, limit)
+}
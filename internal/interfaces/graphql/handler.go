@@ -0,0 +1,246 @@
+// Package graphql serves the blog's read models and article mutations as a
+// GraphQL API on top of the same usecases as the REST handlers.
+package graphql
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/location"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"go.uber.org/zap"
+
+	"github.com/FormalYou/clean-architecture-blog/domain"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/usecase"
+	"github.com/FormalYou/clean-architecture-blog/internal/errorx"
+)
+
+// maxRequestBytes bounds the body of a POST request.
+const maxRequestBytes = 1 << 20
+
+// requestKey stores the parsed request in the gin context, so Limit and
+// Serve parse it once.
+const requestKey = "graphql.request"
+
+// Handler serves GraphQL over HTTP: POST with a JSON body of query,
+// operationName and variables, or GET with the same as query parameters for
+// queries only. Requests that are not GraphQL requests at all get the usual
+// error response; errors of a GraphQL request, including rejected ones, are
+// answered with 200 and the errors list, as GraphQL clients expect.
+type Handler struct {
+	schema   graphql.Schema
+	users    usecase.UserUsecaseInterface
+	comments usecase.CommentUsecaseInterface
+	limits   Limits
+	logger   *zap.Logger
+}
+
+// NewHandler creates a Handler resolving articles, users and comments
+// through the usecases.
+func NewHandler(articles usecase.ArticleUsecaseInterface, users usecase.UserUsecaseInterface, comments usecase.CommentUsecaseInterface, limits Limits, logger *zap.Logger) (*Handler, error) {
+	schema, err := newSchema(&resolver{articles: articles, logger: logger})
+	if err != nil {
+		return nil, fmt.Errorf("build graphql schema: %w", err)
+	}
+	return &Handler{
+		schema:   schema,
+		users:    users,
+		comments: comments,
+		limits:   limits,
+		logger:   logger,
+	}, nil
+}
+
+// request is a GraphQL request and, once parsed, its document and the
+// operation to execute.
+type request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+
+	doc *ast.Document
+	op  *ast.OperationDefinition
+	// errs are the syntax errors of the query.
+	errs []gqlerrors.FormattedError
+}
+
+// Limit applies the rate limit middleware matching the operation: write for
+// mutations, read for everything else.
+func (h *Handler) Limit(read, write gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if req, err := h.request(c); err == nil && req.op != nil && req.op.Operation == ast.OperationTypeMutation {
+			write(c)
+			return
+		}
+		read(c)
+	}
+}
+
+// Serve executes the request.
+func (h *Handler) Serve(c *gin.Context) {
+	req, err := h.request(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if len(req.errs) > 0 {
+		c.JSON(http.StatusOK, &graphql.Result{Errors: req.errs})
+		return
+	}
+	if req.op != nil && req.op.Operation == ast.OperationTypeMutation && c.Request.Method != http.MethodPost {
+		_ = c.Error(errorx.New(errorx.CodeMethodNotAllowed, errors.New("mutations must be sent with POST")).
+			WithParams(map[string]string{"method": c.Request.Method}))
+		return
+	}
+
+	if validation := graphql.ValidateDocument(&h.schema, req.doc, graphql.SpecifiedRules); !validation.IsValid {
+		c.JSON(http.StatusOK, &graphql.Result{Errors: validation.Errors})
+		return
+	}
+	if req.op != nil {
+		if errs := h.checkLimits(req); len(errs) > 0 {
+			c.JSON(http.StatusOK, &graphql.Result{Errors: errs})
+			return
+		}
+	}
+
+	ctx := withLoaders(c.Request.Context(), newLoaders(h.users, h.comments))
+	ctx = withLocale(ctx, errorx.NegotiateLocale(c.GetHeader("Accept-Language")))
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        h.schema,
+		AST:           req.doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       ctx,
+	})
+	restoreExtensions(result.Errors)
+	c.JSON(http.StatusOK, result)
+}
+
+// request reads and parses the request once per gin context.
+func (h *Handler) request(c *gin.Context) (*request, error) {
+	if v, ok := c.Get(requestKey); ok {
+		return v.(*request), nil
+	}
+
+	req := &request{}
+	switch c.Request.Method {
+	case http.MethodGet:
+		req.Query = c.Query("query")
+		req.OperationName = c.Query("operationName")
+		if vars := c.Query("variables"); vars != "" {
+			if err := json.Unmarshal([]byte(vars), &req.Variables); err != nil {
+				return nil, invalidRequest("variables", "invalid_type", "variables must be a JSON object")
+			}
+		}
+	case http.MethodPost:
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxRequestBytes)
+		if err := json.NewDecoder(c.Request.Body).Decode(req); err != nil {
+			return nil, invalidRequest("body", domain.ReasonInvalidFormat, "body must be a JSON object with a query")
+		}
+	default:
+		return nil, errorx.New(errorx.CodeMethodNotAllowed, fmt.Errorf("method %s not allowed", c.Request.Method)).
+			WithParams(map[string]string{"method": c.Request.Method})
+	}
+	if req.Query == "" {
+		return nil, invalidRequest("query", domain.ReasonRequired, "query is required")
+	}
+
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{
+		Body: []byte(req.Query),
+		Name: "GraphQL request",
+	})})
+	if err != nil {
+		req.errs = gqlerrors.FormatErrors(err)
+	} else {
+		req.doc = doc
+		req.op = operation(doc, req.OperationName)
+	}
+	c.Set(requestKey, req)
+	return req, nil
+}
+
+// checkLimits rejects operations deeper or more complex than the limits.
+func (h *Handler) checkLimits(req *request) []gqlerrors.FormattedError {
+	c := measure(&h.schema, req.doc, req.op, req.Variables)
+	var errs []gqlerrors.FormattedError
+	if h.limits.MaxDepth > 0 && c.depth > h.limits.MaxDepth {
+		errs = append(errs, limitError(fmt.Sprintf("query depth %d exceeds the maximum of %d", c.depth, h.limits.MaxDepth)))
+	}
+	if h.limits.MaxComplexity > 0 && c.complexity > h.limits.MaxComplexity {
+		errs = append(errs, limitError(fmt.Sprintf("query complexity %d exceeds the maximum of %d", c.complexity, h.limits.MaxComplexity)))
+	}
+	if len(errs) > 0 {
+		h.logger.Warn("graphql query rejected", zap.Int("depth", c.depth), zap.Int("complexity", c.complexity))
+	}
+	return errs
+}
+
+// operation selects the operation to execute: the named one, or the only
+// one when no name is given. It returns nil when there is no such
+// operation; execution then reports the error.
+func operation(doc *ast.Document, name string) *ast.OperationDefinition {
+	var found *ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if name == "" {
+			if found != nil {
+				return nil
+			}
+			found = op
+		} else if op.Name != nil && op.Name.Value == name {
+			return op
+		}
+	}
+	return found
+}
+
+// invalidRequest is CodeInvalidParams with the problem as a detail on the
+// request field, so it is translated like the message.
+func invalidRequest(field, reason, message string) *errorx.DetailError {
+	return errorx.New(errorx.CodeInvalidParams, errors.New(message)).
+		WithDetails(errorx.Detail{Field: field, Reason: reason, Message: message})
+}
+
+func limitError(message string) gqlerrors.FormattedError {
+	return gqlerrors.FormattedError{
+		Message:    message,
+		Locations:  []location.SourceLocation{},
+		Extensions: map[string]interface{}{"code": errorx.CodeInvalidParams},
+	}
+}
+
+// restoreExtensions puts back the extensions of resolver errors raised in
+// thunks, which the executor formats without them.
+func restoreExtensions(errs []gqlerrors.FormattedError) {
+	for i := range errs {
+		if errs[i].Extensions != nil {
+			continue
+		}
+		var err error = errs[i]
+		for err != nil {
+			if extended, ok := err.(gqlerrors.ExtendedError); ok {
+				errs[i].Extensions = extended.Extensions()
+				break
+			}
+			switch e := err.(type) {
+			case gqlerrors.FormattedError:
+				err = e.OriginalError()
+			case *gqlerrors.Error:
+				err = e.OriginalError
+			default:
+				err = nil
+			}
+		}
+	}
+}
@@ -0,0 +1,377 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/FormalYou/clean-architecture-blog/domain"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/usecase/articleerr"
+	mock_usecase "github.com/FormalYou/clean-architecture-blog/internal/application/usecase/mocks"
+	"github.com/FormalYou/clean-architecture-blog/internal/errorx"
+	"github.com/FormalYou/clean-architecture-blog/internal/interfaces/http/handler/middleware"
+)
+
+type mocks struct {
+	articles *mock_usecase.MockArticleUsecaseInterface
+	users    *mock_usecase.MockUserUsecaseInterface
+	comments *mock_usecase.MockCommentUsecaseInterface
+}
+
+// response is a GraphQL response with the errors decoded loosely.
+type response struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Path       []interface{}          `json:"path"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+func setupRouter(t *testing.T, limits Limits) (*gin.Engine, *mocks) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	m := &mocks{
+		articles: mock_usecase.NewMockArticleUsecaseInterface(ctrl),
+		users:    mock_usecase.NewMockUserUsecaseInterface(ctrl),
+		comments: mock_usecase.NewMockCommentUsecaseInterface(ctrl),
+	}
+	h, err := NewHandler(m.articles, m.users, m.comments, limits, zap.NewNop())
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.Use(middleware.ErrorHandler(zap.NewNop()))
+	router.GET("/graphql", h.Serve)
+	router.POST("/graphql", h.Serve)
+	return router, m
+}
+
+func post(router *gin.Engine, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	return rr
+}
+
+func query(t *testing.T, router *gin.Engine, q string, variables map[string]interface{}) response {
+	t.Helper()
+	body, _ := json.Marshal(map[string]interface{}{"query": q, "variables": variables})
+	rr := post(router, string(body))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var resp response
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	return resp
+}
+
+func TestHandler_BatchesAuthorsAndComments(t *testing.T) {
+	router, m := setupRouter(t, Limits{})
+
+	created := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	m.articles.EXPECT().ListArticles(gomock.Any(), 10, 0).Return([]*domain.Article{
+		{ID: 1, Title: "One", AuthorID: 7, CreatedAt: created, Tags: []domain.Tag{{ID: 1, Name: "go"}}},
+		{ID: 2, Title: "Two", AuthorID: 8, CreatedAt: created},
+		{ID: 3, Title: "Three", AuthorID: 7, CreatedAt: created},
+	}, nil)
+	// Whether the comment authors join the article authors' lookup depends on
+	// the order the executor visits the fields in; either way every user is
+	// looked up once, in at most one call per level.
+	users := map[int64]*domain.User{
+		7: {ID: 7, Username: "alice"},
+		9: {ID: 9, Username: "carol"},
+	}
+	var requested []int64
+	m.users.EXPECT().GetUsersByIDs(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, ids []int64) (map[int64]*domain.User, error) {
+		requested = append(requested, ids...)
+		found := map[int64]*domain.User{}
+		for _, id := range ids {
+			if u, ok := users[id]; ok {
+				found[id] = u
+			}
+		}
+		return found, nil
+	}).MinTimes(1).MaxTimes(2)
+	m.comments.EXPECT().GetCommentsByArticleIDs(gomock.Any(), []int64{1, 2, 3}, 1).Return(map[int64][]*domain.Comment{
+		1: {{ID: 1, ArticleID: 1, UserID: 9, Content: "first"}},
+		2: nil,
+		3: nil,
+	}, nil)
+
+	resp := query(t, router, `{
+		articles {
+			id title
+			author { username }
+			tags { name }
+			comments(first: 1) { content author { username } }
+		}
+	}`, nil)
+
+	assert.Empty(t, resp.Errors)
+	assert.ElementsMatch(t, []int64{7, 8, 9}, requested)
+	require.IsType(t, []interface{}{}, resp.Data["articles"])
+	articles := resp.Data["articles"].([]interface{})
+	require.Len(t, articles, 3)
+	assert.Equal(t, map[string]interface{}{
+		"id":       "1",
+		"title":    "One",
+		"author":   map[string]interface{}{"username": "alice"},
+		"tags":     []interface{}{map[string]interface{}{"name": "go"}},
+		"comments": []interface{}{map[string]interface{}{"content": "first", "author": map[string]interface{}{"username": "carol"}}},
+	}, articles[0])
+	assert.Nil(t, articles[1].(map[string]interface{})["author"], "deleted author")
+	assert.Equal(t, []interface{}{}, articles[1].(map[string]interface{})["comments"])
+}
+
+func TestHandler_Queries(t *testing.T) {
+	testCases := []struct {
+		name         string
+		query        string
+		setupMocks   func(m *mocks)
+		expectedData map[string]interface{}
+		expectedCode float64
+	}{
+		{
+			name:  "Article",
+			query: `{ article(id: "1") { title version } }`,
+			setupMocks: func(m *mocks) {
+				m.articles.EXPECT().GetArticleByID(gomock.Any(), int64(1)).Return(&domain.Article{ID: 1, Title: "One", Version: 3}, nil)
+			},
+			expectedData: map[string]interface{}{"article": map[string]interface{}{"title": "One", "version": float64(3)}},
+		},
+		{
+			name:  "Article Not Found",
+			query: `{ article(id: "2") { title } }`,
+			setupMocks: func(m *mocks) {
+				m.articles.EXPECT().GetArticleByID(gomock.Any(), int64(2)).Return(nil, errorx.New(articleerr.CodeArticleNotFound, errors.New("not found")))
+			},
+			expectedData: map[string]interface{}{"article": nil},
+		},
+		{
+			name:         "Invalid ID",
+			query:        `{ article(id: "abc") { title } }`,
+			setupMocks:   func(m *mocks) {},
+			expectedData: map[string]interface{}{"article": nil},
+			expectedCode: errorx.CodeInvalidParams,
+		},
+		{
+			name:  "User",
+			query: `{ user(id: "7") { username nickname } }`,
+			setupMocks: func(m *mocks) {
+				m.users.EXPECT().GetUsersByIDs(gomock.Any(), []int64{7}).Return(map[int64]*domain.User{
+					7: {ID: 7, Username: "alice", Profile: domain.UserProfile{Nickname: "Alice"}},
+				}, nil)
+			},
+			expectedData: map[string]interface{}{"user": map[string]interface{}{"username": "alice", "nickname": "Alice"}},
+		},
+		{
+			name:  "Author Lookup Error",
+			query: `{ article(id: "1") { author { username } } }`,
+			setupMocks: func(m *mocks) {
+				m.articles.EXPECT().GetArticleByID(gomock.Any(), int64(1)).Return(&domain.Article{ID: 1, AuthorID: 7}, nil)
+				m.users.EXPECT().GetUsersByIDs(gomock.Any(), []int64{7}).Return(nil, errorx.New(errorx.CodeTimeout, errors.New("timeout")))
+			},
+			expectedData: map[string]interface{}{"article": map[string]interface{}{"author": nil}},
+			expectedCode: errorx.CodeTimeout,
+		},
+		{
+			name:  "Articles Page",
+			query: `{ articles(first: 2, offset: 1) { id } }`,
+			setupMocks: func(m *mocks) {
+				m.articles.EXPECT().ListArticles(gomock.Any(), 2, 1).Return([]*domain.Article{{ID: 2}, {ID: 3}}, nil)
+			},
+			expectedData: map[string]interface{}{"articles": []interface{}{
+				map[string]interface{}{"id": "2"},
+				map[string]interface{}{"id": "3"},
+			}},
+		},
+		{
+			name:         "Articles Negative Offset",
+			query:        `{ articles(offset: -1) { id } }`,
+			setupMocks:   func(m *mocks) {},
+			expectedData: nil,
+			expectedCode: errorx.CodeInvalidParams,
+		},
+		{
+			name:         "Articles Page Too Large",
+			query:        `{ articles(first: 101) { id } }`,
+			setupMocks:   func(m *mocks) {},
+			expectedData: nil,
+			expectedCode: errorx.CodeInvalidParams,
+		},
+		{
+			name:  "Empty Comments Page",
+			query: `{ article(id: "1") { comments(first: 0) { id } } }`,
+			setupMocks: func(m *mocks) {
+				m.articles.EXPECT().GetArticleByID(gomock.Any(), int64(1)).Return(&domain.Article{ID: 1}, nil)
+			},
+			expectedData: map[string]interface{}{"article": map[string]interface{}{"comments": []interface{}{}}},
+		},
+		{
+			name:  "Comments Page Too Large",
+			query: `{ article(id: "1") { comments(first: 101) { id } } }`,
+			setupMocks: func(m *mocks) {
+				m.articles.EXPECT().GetArticleByID(gomock.Any(), int64(1)).Return(&domain.Article{ID: 1}, nil)
+			},
+			expectedData: map[string]interface{}{"article": nil},
+			expectedCode: errorx.CodeInvalidParams,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			router, m := setupRouter(t, Limits{})
+			tc.setupMocks(m)
+
+			resp := query(t, router, tc.query, nil)
+			assert.Equal(t, tc.expectedData, resp.Data)
+			if tc.expectedCode == 0 {
+				assert.Empty(t, resp.Errors)
+				return
+			}
+			require.Len(t, resp.Errors, 1)
+			assert.Equal(t, tc.expectedCode, resp.Errors[0].Extensions["code"])
+		})
+	}
+}
+
+func TestHandler_Mutations(t *testing.T) {
+	t.Run("Create", func(t *testing.T) {
+		router, m := setupRouter(t, Limits{})
+		m.articles.EXPECT().CreateArticle(gomock.Any(), &domain.Article{
+			Title:   "Hello",
+			Content: "World",
+			Tags:    []domain.Tag{{Name: "go"}},
+		}).DoAndReturn(func(_ interface{}, a *domain.Article) error {
+			a.ID, a.AuthorID, a.Version = 5, 7, 1
+			return nil
+		})
+
+		resp := query(t, router, `mutation($input: CreateArticleInput!) { createArticle(input: $input) { id version } }`,
+			map[string]interface{}{"input": map[string]interface{}{"title": "Hello", "content": "World", "tags": []string{"go"}}})
+		assert.Empty(t, resp.Errors)
+		assert.Equal(t, map[string]interface{}{"createArticle": map[string]interface{}{"id": "5", "version": float64(1)}}, resp.Data)
+	})
+
+	t.Run("Create Invalid", func(t *testing.T) {
+		router, m := setupRouter(t, Limits{})
+		m.articles.EXPECT().CreateArticle(gomock.Any(), gomock.Any()).Return(errorx.New(errorx.CodeInvalidParams, errors.New("title is required")).
+			WithDetails(errorx.Detail{Field: "title", Reason: domain.ReasonRequired, Message: "title is required"}))
+
+		resp := query(t, router, `mutation { createArticle(input: {title: "", content: "x"}) { id } }`, nil)
+		assert.Nil(t, resp.Data)
+		require.Len(t, resp.Errors, 1)
+		assert.Equal(t, float64(errorx.CodeInvalidParams), resp.Errors[0].Extensions["code"])
+		assert.Equal(t, []interface{}{map[string]interface{}{"field": "title", "reason": "required", "message": "title is required"}},
+			resp.Errors[0].Extensions["details"])
+	})
+
+	t.Run("Update Reads Back", func(t *testing.T) {
+		router, m := setupRouter(t, Limits{})
+		gomock.InOrder(
+			m.articles.EXPECT().UpdateArticle(gomock.Any(), &domain.Article{ID: 5, Title: "New", Content: "Body"}).Return(nil),
+			m.articles.EXPECT().GetArticleByID(gomock.Any(), int64(5)).Return(&domain.Article{ID: 5, Title: "New", Version: 2}, nil),
+		)
+
+		resp := query(t, router, `mutation { updateArticle(id: "5", input: {title: "New", content: "Body"}) { title version } }`, nil)
+		assert.Empty(t, resp.Errors)
+		assert.Equal(t, map[string]interface{}{"updateArticle": map[string]interface{}{"title": "New", "version": float64(2)}}, resp.Data)
+	})
+
+	t.Run("Delete Unauthorized", func(t *testing.T) {
+		router, m := setupRouter(t, Limits{})
+		m.articles.EXPECT().DeleteArticle(gomock.Any(), int64(5)).Return(errorx.New(errorx.CodeUnauthorized, errors.New("no user in context")))
+
+		resp := query(t, router, `mutation { deleteArticle(id: "5") }`, nil)
+		assert.Nil(t, resp.Data)
+		require.Len(t, resp.Errors, 1)
+		assert.Equal(t, "Unauthorized", resp.Errors[0].Message)
+		assert.Equal(t, []interface{}{"deleteArticle"}, resp.Errors[0].Path)
+		assert.Equal(t, float64(errorx.CodeUnauthorized), resp.Errors[0].Extensions["code"])
+	})
+
+	t.Run("Not Over GET", func(t *testing.T) {
+		router, _ := setupRouter(t, Limits{})
+		req := httptest.NewRequest(http.MethodGet, "/graphql?query="+url.QueryEscape(`mutation { deleteArticle(id: "5") }`), nil)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+	})
+}
+
+func TestHandler_Limits(t *testing.T) {
+	testCases := []struct {
+		name      string
+		query     string
+		variables map[string]interface{}
+		rejected  string
+	}{
+		{"Within Limits", `{ article(id: "1") { id } }`, nil, ""},
+		// article > comments > author > username is 4 levels deep.
+		{"Too Deep", `{ article(id: "1") { comments { author { username } } } }`, nil, "depth 4"},
+		{"Too Deep Through Fragment", `{ article(id: "1") { ...F } } fragment F on Article { comments { author { id } } }`, nil, "depth 4"},
+		// 1 + (1 + 10*1) per article, 10 articles assumed: 1 + 10*(1+1+10) = 121.
+		{"Too Complex", `{ articles { id comments { id } } }`, nil, "complexity 121"},
+		{"Complexity From Variables", `query($n: Int) { articles { comments(first: $n) { id } } }`, map[string]interface{}{"n": 5}, "complexity 61"},
+		{"Introspection Is Free", `{ __schema { types { name fields { name type { name } } } } }`, nil, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			router, m := setupRouter(t, Limits{MaxDepth: 3, MaxComplexity: 50})
+			if tc.rejected == "" {
+				m.articles.EXPECT().GetArticleByID(gomock.Any(), int64(1)).Return(&domain.Article{ID: 1}, nil).AnyTimes()
+			}
+
+			resp := query(t, router, tc.query, tc.variables)
+			if tc.rejected == "" {
+				assert.Empty(t, resp.Errors)
+				return
+			}
+			assert.Nil(t, resp.Data)
+			require.NotEmpty(t, resp.Errors)
+			assert.Contains(t, resp.Errors[0].Message, tc.rejected)
+			assert.Equal(t, float64(errorx.CodeInvalidParams), resp.Errors[0].Extensions["code"])
+		})
+	}
+}
+
+func TestHandler_BadRequests(t *testing.T) {
+	testCases := []struct {
+		name         string
+		body         string
+		expectedCode int
+		graphqlError string
+	}{
+		{"Not JSON", `query`, http.StatusBadRequest, ""},
+		{"No Query", `{"variables": {}}`, http.StatusBadRequest, ""},
+		{"Syntax Error", `{"query": "{ articles "}`, http.StatusOK, "Syntax Error"},
+		{"Unknown Field", `{"query": "{ nope }"}`, http.StatusOK, `Cannot query field "nope"`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			router, _ := setupRouter(t, Limits{})
+			rr := post(router, tc.body)
+			assert.Equal(t, tc.expectedCode, rr.Code)
+			if tc.graphqlError != "" {
+				var resp response
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				require.Len(t, resp.Errors, 1)
+				assert.Contains(t, resp.Errors[0].Message, tc.graphqlError)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package graphql
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// defaultListSize is the assumed length of a list field without a first
+// argument when estimating the complexity of a query.
+const defaultListSize = 10
+
+// Limits bound the queries accepted. Zero disables a limit.
+type Limits struct {
+	// MaxDepth is the deepest field nesting, counting the root fields as 1.
+	MaxDepth int
+	// MaxComplexity bounds the estimated number of fields resolved: every
+	// field costs 1, and the cost of a list field's selections is multiplied
+	// by its first argument, or by defaultListSize without one.
+	MaxComplexity int
+}
+
+// cost is the depth and complexity of a selection.
+type cost struct {
+	depth      int
+	complexity int
+}
+
+// measurer estimates the cost of an operation before it is executed.
+// Introspection fields are free so tools can always fetch the schema.
+type measurer struct {
+	schema    *graphql.Schema
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+	// visiting guards against fragment cycles, which validation rejects
+	// anyway.
+	visiting map[string]bool
+}
+
+// measure returns the cost of op in doc.
+func measure(schema *graphql.Schema, doc *ast.Document, op *ast.OperationDefinition, variables map[string]interface{}) cost {
+	m := &measurer{
+		schema:    schema,
+		fragments: map[string]*ast.FragmentDefinition{},
+		variables: variables,
+		visiting:  map[string]bool{},
+	}
+	for _, def := range doc.Definitions {
+		if frag, ok := def.(*ast.FragmentDefinition); ok {
+			m.fragments[frag.Name.Value] = frag
+		}
+	}
+
+	root := schema.QueryType()
+	if op.Operation == ast.OperationTypeMutation {
+		root = schema.MutationType()
+	}
+	if root == nil {
+		return cost{}
+	}
+	return m.selectionSet(root, op.SelectionSet)
+}
+
+func (m *measurer) selectionSet(parent *graphql.Object, set *ast.SelectionSet) cost {
+	var total cost
+	if set == nil {
+		return total
+	}
+	for _, selection := range set.Selections {
+		var c cost
+		switch sel := selection.(type) {
+		case *ast.Field:
+			c = m.field(parent, sel)
+		case *ast.InlineFragment:
+			c = m.selectionSet(m.typeCondition(parent, sel.TypeCondition), sel.SelectionSet)
+		case *ast.FragmentSpread:
+			name := sel.Name.Value
+			frag, ok := m.fragments[name]
+			if !ok || m.visiting[name] {
+				continue
+			}
+			m.visiting[name] = true
+			c = m.selectionSet(m.typeCondition(parent, frag.TypeCondition), frag.SelectionSet)
+			delete(m.visiting, name)
+		}
+		total.depth = max(total.depth, c.depth)
+		total.complexity += c.complexity
+	}
+	return total
+}
+
+func (m *measurer) field(parent *graphql.Object, field *ast.Field) cost {
+	name := field.Name.Value
+	if strings.HasPrefix(name, "__") {
+		return cost{}
+	}
+	def, ok := parent.Fields()[name]
+	if !ok {
+		return cost{}
+	}
+
+	c := cost{depth: 1, complexity: 1}
+	obj, list := objectType(def.Type)
+	if obj == nil {
+		return c
+	}
+	children := m.selectionSet(obj, field.SelectionSet)
+	if list {
+		children.complexity *= m.listSize(field)
+	}
+	c.depth += children.depth
+	c.complexity += children.complexity
+	return c
+}
+
+// listSize is the first argument of a list field, or defaultListSize.
+func (m *measurer) listSize(field *ast.Field) int {
+	for _, arg := range field.Arguments {
+		if arg.Name.Value != "first" {
+			continue
+		}
+		switch v := arg.Value.(type) {
+		case *ast.IntValue:
+			if n, err := strconv.Atoi(v.Value); err == nil && n >= 0 {
+				return n
+			}
+		case *ast.Variable:
+			switch n := m.variables[v.Name.Value].(type) {
+			case float64:
+				if n >= 0 {
+					return int(n)
+				}
+			case int:
+				if n >= 0 {
+					return n
+				}
+			}
+		}
+	}
+	return defaultListSize
+}
+
+// typeCondition is the object type a fragment applies to; fragments on
+// interfaces or unknown types keep the parent type.
+func (m *measurer) typeCondition(parent *graphql.Object, cond *ast.Named) *graphql.Object {
+	if cond == nil {
+		return parent
+	}
+	if obj, ok := m.schema.Type(cond.Name.Value).(*graphql.Object); ok {
+		return obj
+	}
+	return parent
+}
+
+// objectType unwraps non-null and list modifiers, reporting whether t is a
+// list of objects.
+func objectType(t graphql.Output) (obj *graphql.Object, list bool) {
+	for {
+		switch typ := t.(type) {
+		case *graphql.NonNull:
+			t = typ.OfType
+		case *graphql.List:
+			list = true
+			t = typ.OfType
+		case *graphql.Object:
+			return typ, list
+		default:
+			return nil, false
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package graphql
+
+import (
+	"context"
+	"sync"
+
+	"github.com/FormalYou/clean-architecture-blog/domain"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/usecase"
+)
+
+// batchLoader collects the keys requested while one level of a query is
+// resolved and fetches them with a single call once the first of them is
+// needed. The executor resolves a level's thunks only after every field of
+// the level has been visited, so e.g. the authors of all articles in a list
+// are loaded together instead of one query per article.
+type batchLoader[K comparable, V any] struct {
+	fetch func(ctx context.Context, keys []K) (map[K]V, error)
+
+	mu      sync.Mutex
+	pending []K
+	queued  map[K]bool
+	results map[K]V
+	errs    map[K]error
+}
+
+func newBatchLoader[K comparable, V any](fetch func(ctx context.Context, keys []K) (map[K]V, error)) *batchLoader[K, V] {
+	return &batchLoader[K, V]{
+		fetch:   fetch,
+		queued:  map[K]bool{},
+		results: map[K]V{},
+		errs:    map[K]error{},
+	}
+}
+
+// load queues key and returns a thunk resolving to its value. The second
+// result of the thunk's value is false when the key was not found.
+func (l *batchLoader[K, V]) load(ctx context.Context, key K) func() (V, bool, error) {
+	l.mu.Lock()
+	if !l.queued[key] {
+		l.queued[key] = true
+		l.pending = append(l.pending, key)
+	}
+	l.mu.Unlock()
+
+	return func() (V, bool, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if len(l.pending) > 0 {
+			keys := l.pending
+			l.pending = nil
+			found, err := l.fetch(ctx, keys)
+			for _, k := range keys {
+				if err != nil {
+					l.errs[k] = err
+				} else if v, ok := found[k]; ok {
+					l.results[k] = v
+				}
+			}
+		}
+		if err := l.errs[key]; err != nil {
+			var zero V
+			return zero, false, err
+		}
+		v, ok := l.results[key]
+		return v, ok, nil
+	}
+}
+
+// loaders are the batch loaders of one request; results are shared by every
+// field of the request that asks for the same key.
+type loaders struct {
+	users *batchLoader[int64, *domain.User]
+
+	commentUsecase usecase.CommentUsecaseInterface
+	mu             sync.Mutex
+	commentPages   map[int]*batchLoader[int64, []*domain.Comment] // by page size
+}
+
+func newLoaders(users usecase.UserUsecaseInterface, comments usecase.CommentUsecaseInterface) *loaders {
+	return &loaders{
+		users:          newBatchLoader(users.GetUsersByIDs),
+		commentUsecase: comments,
+		commentPages:   map[int]*batchLoader[int64, []*domain.Comment]{},
+	}
+}
+
+// comments returns the loader of the first comments of articles. Pages of
+// different sizes are loaded separately, each limited by the database.
+func (l *loaders) comments(first int) *batchLoader[int64, []*domain.Comment] {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	loader, ok := l.commentPages[first]
+	if !ok {
+		loader = newBatchLoader(func(ctx context.Context, articleIDs []int64) (map[int64][]*domain.Comment, error) {
+			return l.commentUsecase.GetCommentsByArticleIDs(ctx, articleIDs, first)
+		})
+		l.commentPages[first] = loader
+	}
+	return loader
+}
+
+type loadersKey struct{}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
@@ -0,0 +1,386 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/graphql-go/graphql"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/FormalYou/clean-architecture-blog/domain"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/usecase"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/usecase/articleerr"
+	"github.com/FormalYou/clean-architecture-blog/internal/errorx"
+	zaplog "github.com/FormalYou/clean-architecture-blog/internal/infrastructure/log"
+)
+
+// Page sizes of Article.comments and Query.articles. The defaults match the
+// list size assumed when estimating complexity.
+const (
+	defaultCommentsPage = defaultListSize
+	maxCommentsPage     = 100
+	defaultArticlesPage = defaultListSize
+	maxArticlesPage     = 100
+)
+
+// resolver resolves the fields of the schema through the usecases.
+type resolver struct {
+	articles usecase.ArticleUsecaseInterface
+	logger   *zap.Logger
+}
+
+// newSchema builds the schema:
+//
+//	type Query {
+//	  article(id: ID!): Article
+//	  articles(first: Int = 10, offset: Int = 0): [Article!]!
+//	  user(id: ID!): User
+//	}
+//	type Mutation {
+//	  createArticle(input: CreateArticleInput!): Article!
+//	  updateArticle(id: ID!, input: UpdateArticleInput!): Article!
+//	  deleteArticle(id: ID!): ID!
+//	}
+//
+// Authors and comments are fetched through the request's batch loaders.
+func newSchema(r *resolver) (graphql.Schema, error) {
+	userType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "User",
+		Fields: graphql.Fields{
+			"id":       {Type: graphql.NewNonNull(graphql.ID), Resolve: user(func(u *domain.User) interface{} { return strconv.FormatInt(u.ID, 10) })},
+			"username": {Type: graphql.NewNonNull(graphql.String), Resolve: user(func(u *domain.User) interface{} { return u.Username })},
+			"nickname": {Type: graphql.NewNonNull(graphql.String), Resolve: user(func(u *domain.User) interface{} { return u.Profile.Nickname })},
+			"avatar":   {Type: graphql.NewNonNull(graphql.String), Resolve: user(func(u *domain.User) interface{} { return u.Profile.Avatar })},
+		},
+	})
+
+	tagType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Tag",
+		Fields: graphql.Fields{
+			"id":   {Type: graphql.NewNonNull(graphql.ID), Resolve: tag(func(t domain.Tag) interface{} { return strconv.FormatInt(t.ID, 10) })},
+			"name": {Type: graphql.NewNonNull(graphql.String), Resolve: tag(func(t domain.Tag) interface{} { return t.Name })},
+		},
+	})
+
+	commentType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Comment",
+		Fields: graphql.Fields{
+			"id":        {Type: graphql.NewNonNull(graphql.ID), Resolve: comment(func(c *domain.Comment) interface{} { return strconv.FormatInt(c.ID, 10) })},
+			"content":   {Type: graphql.NewNonNull(graphql.String), Resolve: comment(func(c *domain.Comment) interface{} { return c.Content })},
+			"createdAt": {Type: graphql.NewNonNull(graphql.DateTime), Resolve: comment(func(c *domain.Comment) interface{} { return c.CreatedAt })},
+			"author": {
+				Type: userType,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return r.loadUser(p.Context, p.Source.(*domain.Comment).UserID), nil
+				},
+			},
+		},
+	})
+
+	articleType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Article",
+		Fields: graphql.Fields{
+			"id":        {Type: graphql.NewNonNull(graphql.ID), Resolve: article(func(a *domain.Article) interface{} { return strconv.FormatInt(a.ID, 10) })},
+			"title":     {Type: graphql.NewNonNull(graphql.String), Resolve: article(func(a *domain.Article) interface{} { return a.Title })},
+			"content":   {Type: graphql.NewNonNull(graphql.String), Resolve: article(func(a *domain.Article) interface{} { return a.Content })},
+			"version":   {Type: graphql.NewNonNull(graphql.Int), Resolve: article(func(a *domain.Article) interface{} { return a.Version })},
+			"createdAt": {Type: graphql.NewNonNull(graphql.DateTime), Resolve: article(func(a *domain.Article) interface{} { return a.CreatedAt })},
+			"updatedAt": {Type: graphql.NewNonNull(graphql.DateTime), Resolve: article(func(a *domain.Article) interface{} { return a.UpdatedAt })},
+			"tags":      {Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(tagType))), Resolve: article(func(a *domain.Article) interface{} { return a.Tags })},
+			"author": {
+				Type:        userType,
+				Description: "Null when the author's account no longer exists.",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return r.loadUser(p.Context, p.Source.(*domain.Article).AuthorID), nil
+				},
+			},
+			"comments": {
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(commentType))),
+				Description: fmt.Sprintf("The first comments, oldest first; first is at most %d.", maxCommentsPage),
+				Args: graphql.FieldConfigArgument{
+					"first": {Type: graphql.Int, DefaultValue: defaultCommentsPage},
+				},
+				Resolve: r.comments,
+			},
+		},
+	})
+
+	idArg := graphql.FieldConfigArgument{"id": {Type: graphql.NewNonNull(graphql.ID)}}
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"article": {Type: articleType, Args: idArg, Resolve: r.article},
+			"articles": {
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(articleType))),
+				Description: fmt.Sprintf("The first articles after skipping offset, oldest first; first is at most %d.", maxArticlesPage),
+				Args: graphql.FieldConfigArgument{
+					"first":  {Type: graphql.Int, DefaultValue: defaultArticlesPage},
+					"offset": {Type: graphql.Int, DefaultValue: 0},
+				},
+				Resolve: r.allArticles,
+			},
+			"user": {
+				Type: userType,
+				Args: idArg,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					id, err := parseID(p.Args["id"], "id")
+					if err != nil {
+						return nil, r.fail(p.Context, err)
+					}
+					return r.loadUser(p.Context, id), nil
+				},
+			},
+		},
+	})
+
+	createInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "CreateArticleInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"title":   {Type: graphql.NewNonNull(graphql.String)},
+			"content": {Type: graphql.NewNonNull(graphql.String)},
+			"tags":    {Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+		},
+	})
+	updateInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "UpdateArticleInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"title":   {Type: graphql.NewNonNull(graphql.String)},
+			"content": {Type: graphql.NewNonNull(graphql.String)},
+		},
+	})
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createArticle": {
+				Type:    graphql.NewNonNull(articleType),
+				Args:    graphql.FieldConfigArgument{"input": {Type: graphql.NewNonNull(createInput)}},
+				Resolve: r.createArticle,
+			},
+			"updateArticle": {
+				Type: graphql.NewNonNull(articleType),
+				Args: graphql.FieldConfigArgument{
+					"id":    {Type: graphql.NewNonNull(graphql.ID)},
+					"input": {Type: graphql.NewNonNull(updateInput)},
+				},
+				Resolve: r.updateArticle,
+			},
+			"deleteArticle": {
+				Type:        graphql.NewNonNull(graphql.ID),
+				Description: "Deletes an article and returns its ID.",
+				Args:        idArg,
+				Resolve:     r.deleteArticle,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query, Mutation: mutation})
+}
+
+// Field resolvers reading from the source object.
+
+func article(field func(*domain.Article) interface{}) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) { return field(p.Source.(*domain.Article)), nil }
+}
+
+func user(field func(*domain.User) interface{}) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) { return field(p.Source.(*domain.User)), nil }
+}
+
+func tag(field func(domain.Tag) interface{}) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) { return field(p.Source.(domain.Tag)), nil }
+}
+
+func comment(field func(*domain.Comment) interface{}) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) { return field(p.Source.(*domain.Comment)), nil }
+}
+
+func (r *resolver) article(p graphql.ResolveParams) (interface{}, error) {
+	id, err := parseID(p.Args["id"], "id")
+	if err != nil {
+		return nil, r.fail(p.Context, err)
+	}
+	a, err := r.articles.GetArticleByID(p.Context, id)
+	if err != nil {
+		var detailErr *errorx.DetailError
+		if errors.As(err, &detailErr) && detailErr.Code == articleerr.CodeArticleNotFound {
+			return nil, nil
+		}
+		return nil, r.fail(p.Context, err)
+	}
+	return a, nil
+}
+
+func (r *resolver) allArticles(p graphql.ResolveParams) (interface{}, error) {
+	first, _ := p.Args["first"].(int)
+	if first < 0 || first > maxArticlesPage {
+		return nil, r.fail(p.Context, invalidArg("first", fmt.Sprintf("first must be between 0 and %d", maxArticlesPage)))
+	}
+	offset, _ := p.Args["offset"].(int)
+	if offset < 0 {
+		return nil, r.fail(p.Context, invalidArg("offset", "offset must not be negative"))
+	}
+	if first == 0 {
+		return []*domain.Article{}, nil
+	}
+	articles, err := r.articles.ListArticles(p.Context, first, offset)
+	if err != nil {
+		return nil, r.fail(p.Context, err)
+	}
+	return articles, nil
+}
+
+// loadUser returns a thunk resolving to the user, or to null when the user
+// does not exist.
+func (r *resolver) loadUser(ctx context.Context, id int64) func() (interface{}, error) {
+	load := loadersFrom(ctx).users.load(ctx, id)
+	return func() (interface{}, error) {
+		u, ok, err := load()
+		if err != nil {
+			return nil, r.fail(ctx, err)
+		}
+		if !ok {
+			return nil, nil
+		}
+		return u, nil
+	}
+}
+
+func (r *resolver) comments(p graphql.ResolveParams) (interface{}, error) {
+	first, _ := p.Args["first"].(int)
+	if first < 0 || first > maxCommentsPage {
+		return nil, r.fail(p.Context, invalidArg("first", fmt.Sprintf("first must be between 0 and %d", maxCommentsPage)))
+	}
+	if first == 0 {
+		return []*domain.Comment{}, nil
+	}
+	load := loadersFrom(p.Context).comments(first).load(p.Context, p.Source.(*domain.Article).ID)
+	return func() (interface{}, error) {
+		comments, _, err := load()
+		if err != nil {
+			return nil, r.fail(p.Context, err)
+		}
+		return comments, nil
+	}, nil
+}
+
+func (r *resolver) createArticle(p graphql.ResolveParams) (interface{}, error) {
+	input := p.Args["input"].(map[string]interface{})
+	a := &domain.Article{
+		Title:   input["title"].(string),
+		Content: input["content"].(string),
+	}
+	tags, _ := input["tags"].([]interface{})
+	a.Tags = make([]domain.Tag, len(tags))
+	for i, name := range tags {
+		a.Tags[i] = domain.Tag{Name: name.(string)}
+	}
+
+	if err := r.articles.CreateArticle(p.Context, a); err != nil {
+		return nil, r.fail(p.Context, err)
+	}
+	return a, nil
+}
+
+func (r *resolver) updateArticle(p graphql.ResolveParams) (interface{}, error) {
+	id, err := parseID(p.Args["id"], "id")
+	if err != nil {
+		return nil, r.fail(p.Context, err)
+	}
+	input := p.Args["input"].(map[string]interface{})
+	err = r.articles.UpdateArticle(p.Context, &domain.Article{
+		ID:      id,
+		Title:   input["title"].(string),
+		Content: input["content"].(string),
+	})
+	if err != nil {
+		return nil, r.fail(p.Context, err)
+	}
+
+	// Read the article back for its new version and update time.
+	a, err := r.articles.GetArticleByID(p.Context, id)
+	if err != nil {
+		return nil, r.fail(p.Context, err)
+	}
+	return a, nil
+}
+
+func (r *resolver) deleteArticle(p graphql.ResolveParams) (interface{}, error) {
+	id, err := parseID(p.Args["id"], "id")
+	if err != nil {
+		return nil, r.fail(p.Context, err)
+	}
+	if err := r.articles.DeleteArticle(p.Context, id); err != nil {
+		return nil, r.fail(p.Context, err)
+	}
+	return strconv.FormatInt(id, 10), nil
+}
+
+// parseID parses an ID argument.
+func parseID(v interface{}, field string) (int64, error) {
+	s, _ := v.(string)
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, invalidArg(field, fmt.Sprintf("%s must be a positive integer", field))
+	}
+	return id, nil
+}
+
+func invalidArg(field, message string) *errorx.DetailError {
+	return errorx.New(errorx.CodeInvalidParams, errors.New(message)).WithDetails(errorx.Detail{
+		Field:   field,
+		Reason:  domain.ReasonInvalidFormat,
+		Message: message,
+	})
+}
+
+// fail logs err like the HTTP error handler does and turns it into a
+// GraphQL error carrying the error code and details as extensions.
+func (r *resolver) fail(ctx context.Context, err error) error {
+	var detailErr *errorx.DetailError
+	if !errors.As(err, &detailErr) {
+		detailErr = errorx.New(errorx.CodeInternalServerError, err)
+	}
+
+	fields := append([]zap.Field{zap.Int("code", detailErr.Code), zap.String("message", detailErr.Message)}, zaplog.TraceFields(ctx)...)
+	if detailErr.LogLevel == zapcore.WarnLevel {
+		r.logger.Warn(detailErr.Error(), fields...)
+	} else {
+		r.logger.Error(detailErr.Error(), fields...)
+	}
+	return &resolverError{detailErr.Localize(localeFrom(ctx))}
+}
+
+// resolverError is an errorx error in a GraphQL response: the message is the
+// user-facing one, and the code and details become extensions.
+type resolverError struct {
+	err *errorx.DetailError
+}
+
+func (e *resolverError) Error() string {
+	return e.err.Message
+}
+
+// Extensions implements gqlerrors.ExtendedError.
+func (e *resolverError) Extensions() map[string]interface{} {
+	ext := map[string]interface{}{"code": e.err.Code}
+	if len(e.err.Details) > 0 {
+		ext["details"] = e.err.Details
+	}
+	return ext
+}
+
+type localeKey struct{}
+
+func withLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+func localeFrom(ctx context.Context) string {
+	locale, _ := ctx.Value(localeKey{}).(string)
+	return locale
+}
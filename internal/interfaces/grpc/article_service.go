@@ -0,0 +1,127 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	blogv1 "github.com/FormalYou/clean-architecture-blog/api/proto/blog/v1"
+	"github.com/FormalYou/clean-architecture-blog/domain"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/usecase"
+	"github.com/FormalYou/clean-architecture-blog/internal/errorx"
+)
+
+// ArticleService implements blogv1.ArticleServiceServer on the article
+// usecase. Errors are errorx errors, mapped to statuses by the interceptors.
+type ArticleService struct {
+	blogv1.UnimplementedArticleServiceServer
+	articles usecase.ArticleUsecaseInterface
+}
+
+// NewArticleService creates an ArticleService.
+func NewArticleService(articles usecase.ArticleUsecaseInterface) *ArticleService {
+	return &ArticleService{articles: articles}
+}
+
+func (s *ArticleService) GetArticle(ctx context.Context, req *blogv1.GetArticleRequest) (*blogv1.Article, error) {
+	if err := checkID(req.GetId(), "id"); err != nil {
+		return nil, err
+	}
+	article, err := s.articles.GetArticleByID(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return toArticle(article), nil
+}
+
+func (s *ArticleService) ListArticles(_ *blogv1.ListArticlesRequest, stream grpc.ServerStreamingServer[blogv1.Article]) error {
+	articles, err := s.articles.GetAllArticles(stream.Context())
+	if err != nil {
+		return err
+	}
+	for _, article := range articles {
+		if err := stream.Send(toArticle(article)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *ArticleService) CreateArticle(ctx context.Context, req *blogv1.CreateArticleRequest) (*blogv1.Article, error) {
+	article := &domain.Article{
+		Title:   req.GetTitle(),
+		Content: req.GetContent(),
+		Tags:    make([]domain.Tag, len(req.GetTags())),
+	}
+	for i, name := range req.GetTags() {
+		article.Tags[i] = domain.Tag{Name: name}
+	}
+
+	if err := s.articles.CreateArticle(ctx, article); err != nil {
+		return nil, err
+	}
+	return toArticle(article), nil
+}
+
+func (s *ArticleService) UpdateArticle(ctx context.Context, req *blogv1.UpdateArticleRequest) (*blogv1.Article, error) {
+	if err := checkID(req.GetId(), "id"); err != nil {
+		return nil, err
+	}
+	err := s.articles.UpdateArticle(ctx, &domain.Article{
+		ID:      req.GetId(),
+		Title:   req.GetTitle(),
+		Content: req.GetContent(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Read the article back for its new version and update time.
+	article, err := s.articles.GetArticleByID(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return toArticle(article), nil
+}
+
+func (s *ArticleService) DeleteArticle(ctx context.Context, req *blogv1.DeleteArticleRequest) (*blogv1.DeleteArticleResponse, error) {
+	if err := checkID(req.GetId(), "id"); err != nil {
+		return nil, err
+	}
+	if err := s.articles.DeleteArticle(ctx, req.GetId()); err != nil {
+		return nil, err
+	}
+	return &blogv1.DeleteArticleResponse{}, nil
+}
+
+func toArticle(a *domain.Article) *blogv1.Article {
+	article := &blogv1.Article{
+		Id:        a.ID,
+		Title:     a.Title,
+		Content:   a.Content,
+		AuthorId:  a.AuthorID,
+		Version:   a.Version,
+		CreatedAt: timestamppb.New(a.CreatedAt),
+		UpdatedAt: timestamppb.New(a.UpdatedAt),
+	}
+	for _, tag := range a.Tags {
+		article.Tags = append(article.Tags, &blogv1.Tag{Id: tag.ID, Name: tag.Name})
+	}
+	return article
+}
+
+// checkID rejects IDs that cannot exist.
+func checkID(id int64, field string) error {
+	if id > 0 {
+		return nil
+	}
+	message := fmt.Sprintf("%s must be a positive integer", field)
+	return errorx.New(errorx.CodeInvalidParams, errors.New(message)).WithDetails(errorx.Detail{
+		Field:   field,
+		Reason:  domain.ReasonInvalidFormat,
+		Message: message,
+	})
+}
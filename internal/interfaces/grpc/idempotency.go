@@ -0,0 +1,133 @@
+package grpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+
+	blogv1 "github.com/FormalYou/clean-architecture-blog/api/proto/blog/v1"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/repository"
+	"github.com/FormalYou/clean-architecture-blog/internal/errorx"
+	"github.com/FormalYou/clean-architecture-blog/internal/interfaces/shared"
+)
+
+// IdempotencyKeyMetadata carries the client's key for a retryable call.
+const IdempotencyKeyMetadata = "idempotency-key"
+
+// maxIdempotencyKeyLength bounds the key so it cannot bloat the store.
+const maxIdempotencyKeyLength = 255
+
+// idempotentMethods are the methods that honour an idempotency key, the same
+// ones as over HTTP, with a constructor for the response each replays.
+var idempotentMethods = map[string]func() proto.Message{
+	blogv1.UserService_Register_FullMethodName:         func() proto.Message { return new(blogv1.RegisterResponse) },
+	blogv1.ArticleService_CreateArticle_FullMethodName: func() proto.Message { return new(blogv1.Article) },
+}
+
+// UnaryIdempotencyInterceptor makes Register and CreateArticle calls carrying
+// idempotency-key metadata safe to retry, with the same store and rules as
+// the HTTP Idempotency middleware: the key is scoped to the authenticated
+// user or the peer address, the first successful response is replayed,
+// marked with idempotent-replayed header metadata, for every retry with
+// the same request, a retry while the first call is still running gets
+// CodeRequestInProgress and reusing the key for a different request gets
+// CodeIdempotencyMismatch. Failed calls are not stored. It runs after
+// authentication so keys are scoped to the user.
+func UnaryIdempotencyInterceptor(store repository.IdempotencyStore, opts shared.IdempotencyOptions, logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		newResponse, ok := idempotentMethods[info.FullMethod]
+		keys := metadata.ValueFromIncomingContext(ctx, IdempotencyKeyMetadata)
+		if !ok || len(keys) == 0 || keys[0] == "" {
+			return handler(ctx, req)
+		}
+		key := keys[0]
+		if len(key) > maxIdempotencyKeyLength {
+			return nil, errorx.New(errorx.CodeInvalidParams,
+				fmt.Errorf("%s must be at most %d characters", IdempotencyKeyMetadata, maxIdempotencyKeyLength))
+		}
+		body, err := proto.MarshalOptions{Deterministic: true}.Marshal(req.(proto.Message))
+		if err != nil {
+			return nil, errorx.New(errorx.CodeInvalidParams, err)
+		}
+
+		key = "grpc:" + shared.IdempotencyScope(ctx, peerIP(ctx)) + ":" + key
+		fingerprint := callFingerprint(info.FullMethod, body)
+		existing, claim, err := store.Begin(ctx, key, fingerprint, opts.LockTTL)
+		if err != nil {
+			logger.Warn("idempotency store unavailable, handling call without it", zap.Error(err))
+			return handler(ctx, req)
+		}
+		if claim == "" {
+			return replay(ctx, existing, fingerprint, newResponse())
+		}
+
+		// The key is released unless a response gets stored, including when
+		// the handler panics.
+		completed := false
+		detached := context.WithoutCancel(ctx)
+		defer func() {
+			if !completed {
+				if err := store.Release(detached, key, claim); err != nil {
+					logger.Warn("could not release idempotency key", zap.Error(err))
+				}
+			}
+		}()
+
+		resp, err = handler(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		respBody, err := proto.Marshal(resp.(proto.Message))
+		if err != nil {
+			logger.Warn("could not encode idempotent response", zap.Error(err))
+			return resp, nil
+		}
+		err = store.Complete(detached, key, claim, fingerprint, repository.IdempotentResponse{
+			Status:      int(codes.OK),
+			ContentType: "application/grpc+proto",
+			Body:        respBody,
+		}, opts.TTL)
+		if errors.Is(err, repository.ErrIdempotencyClaimLost) {
+			logger.Warn("idempotency key expired while handling call, response not stored", zap.Duration("lock_ttl", opts.LockTTL))
+			return resp, nil
+		}
+		if err != nil {
+			logger.Warn("could not store idempotent response", zap.Error(err))
+			return resp, nil
+		}
+		completed = true
+		return resp, nil
+	}
+}
+
+// replay answers a call whose key was claimed before, decoding the stored
+// response into resp.
+func replay(ctx context.Context, existing *repository.IdempotencyRecord, fingerprint string, resp proto.Message) (interface{}, error) {
+	switch {
+	case existing.Fingerprint != fingerprint:
+		return nil, errorx.New(errorx.CodeIdempotencyMismatch, errors.New("idempotency key reused with a different request"))
+	case existing.Response == nil:
+		return nil, errorx.New(errorx.CodeRequestInProgress, errors.New("idempotency key is still in use"))
+	}
+	if err := proto.Unmarshal(existing.Response.Body, resp); err != nil {
+		return nil, err
+	}
+	_ = grpc.SetHeader(ctx, metadata.Pairs("idempotent-replayed", "true"))
+	return resp, nil
+}
+
+// callFingerprint identifies the method and request a key was first used with.
+func callFingerprint(method string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
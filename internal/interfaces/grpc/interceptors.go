@@ -0,0 +1,252 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+
+	"github.com/FormalYou/clean-architecture-blog/domain"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/contracts"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/usecase/usererr"
+	"github.com/FormalYou/clean-architecture-blog/internal/errorx"
+	zaplog "github.com/FormalYou/clean-architecture-blog/internal/infrastructure/log"
+	"github.com/FormalYou/clean-architecture-blog/internal/interfaces/shared"
+)
+
+// ErrorDomain is the domain of the errdetails.ErrorInfo attached to every
+// error status; its reason is the errorx code.
+const ErrorDomain = "blog"
+
+// grpcCodes maps errorx codes whose gRPC code is more specific than the one
+// derived from their HTTP status.
+var grpcCodes = map[int]codes.Code{
+	usererr.CodeUserAlreadyExists: codes.AlreadyExists,
+}
+
+// grpcCode is the gRPC status code of err: the override in grpcCodes, or
+// the code matching err's HTTP status.
+func grpcCode(err *errorx.DetailError) codes.Code {
+	if code, ok := grpcCodes[err.Code]; ok {
+		return code
+	}
+	switch err.HTTPStatus {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusConflict:
+		return codes.Aborted
+	case http.StatusPreconditionFailed:
+		return codes.FailedPrecondition
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	}
+	if err.HTTPStatus >= 400 && err.HTTPStatus < 500 {
+		return codes.FailedPrecondition
+	}
+	return codes.Internal
+}
+
+// toStatus renders err as a status in the given language. The status
+// carries an errdetails.ErrorInfo naming the errorx code and, for errors
+// with details, an errdetails.BadRequest listing them.
+func toStatus(err *errorx.DetailError, locale string) *status.Status {
+	err = err.Localize(locale)
+	st := status.New(grpcCode(err), err.Message)
+
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{
+		Reason: strconv.Itoa(err.Code),
+		Domain: ErrorDomain,
+	}}
+	if len(err.Details) > 0 {
+		badRequest := &errdetails.BadRequest{}
+		for _, d := range err.Details {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       d.Field,
+				Reason:      d.Reason,
+				Description: d.Message,
+			})
+		}
+		details = append(details, badRequest)
+	}
+	if withDetails, detailsErr := st.WithDetails(details...); detailsErr == nil {
+		st = withDetails
+	}
+	return st
+}
+
+// locale negotiates the language of error messages from the call's
+// accept-language metadata.
+func locale(ctx context.Context) string {
+	return errorx.NegotiateLocale(strings.Join(metadata.ValueFromIncomingContext(ctx, "accept-language"), ","))
+}
+
+// convertError logs a failed call like the HTTP error handler does and turns
+// its error into a status. Statuses and context errors pass through; any
+// other error is reported as CodeInternalServerError.
+func convertError(ctx context.Context, logger *zap.Logger, method string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var detailErr *errorx.DetailError
+	if !errors.As(err, &detailErr) {
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return status.FromContextError(err).Err()
+		}
+		detailErr = errorx.New(errorx.CodeInternalServerError, err)
+	}
+
+	fields := append([]zap.Field{
+		zap.Int("code", detailErr.Code),
+		zap.String("message", detailErr.Message),
+		zap.String("method", method),
+	}, zaplog.TraceFields(ctx)...)
+	if detailErr.LogLevel == zapcore.WarnLevel {
+		logger.Warn(detailErr.Error(), fields...)
+	} else {
+		logger.Error(detailErr.Error(), fields...)
+	}
+	return toStatus(detailErr, locale(ctx)).Err()
+}
+
+// recovered turns a recovered panic into an internal error, logging the
+// stack.
+func recovered(logger *zap.Logger, method string, r interface{}) error {
+	logger.Error("panic recovered", zap.String("method", method), zap.Any("panic", r), zap.ByteString("stack", debug.Stack()))
+	return errorx.New(errorx.CodeInternalServerError, fmt.Errorf("panic: %v", r))
+}
+
+// UnaryErrorInterceptor maps errorx errors returned by unary handlers, and
+// panics, to gRPC statuses. It must be the outermost interceptor so errors
+// of the other interceptors are mapped too.
+func UnaryErrorInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				resp, err = nil, recovered(logger, info.FullMethod, r)
+			}
+			err = convertError(ctx, logger, info.FullMethod, err)
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// StreamErrorInterceptor is UnaryErrorInterceptor for streaming handlers.
+func StreamErrorInterceptor(logger *zap.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(logger, info.FullMethod, r)
+			}
+			err = convertError(ss.Context(), logger, info.FullMethod, err)
+		}()
+		return handler(srv, ss)
+	}
+}
+
+// authenticate validates the bearer token in the authorization metadata and
+// stores the user ID in the context where contracts.AuthService reads it,
+// as the HTTP AuthMiddleware does. Calls without a token are let through
+// anonymously unless required; an invalid token is always rejected.
+func authenticate(ctx context.Context, authSvc contracts.AuthService, required bool, logger *zap.Logger) (context.Context, error) {
+	if len(metadata.ValueFromIncomingContext(ctx, "authorization")) == 0 {
+		if required {
+			logger.Warn("authorization metadata is missing")
+			return nil, unauthenticated(domain.ReasonRequired, "authorization is required")
+		}
+		return ctx, nil
+	}
+
+	token, ok := bearerToken(ctx)
+	if !ok {
+		logger.Warn("invalid token format")
+		return nil, unauthenticated(domain.ReasonInvalidFormat, "authorization must be a Bearer token")
+	}
+	userID, err := authSvc.ValidateToken(token)
+	if err != nil {
+		logger.Warn("invalid token", zap.Error(err))
+		return nil, unauthenticated(errorx.ReasonInvalidToken, "authorization carries an invalid or expired token")
+	}
+	return shared.WithUserID(ctx, userID), nil
+}
+
+// bearerToken returns the token of the authorization metadata; ok is false
+// when there is none or it does not use the Bearer scheme.
+func bearerToken(ctx context.Context) (token string, ok bool) {
+	values := metadata.ValueFromIncomingContext(ctx, "authorization")
+	if len(values) == 0 {
+		return "", false
+	}
+	token, ok = strings.CutPrefix(values[0], "Bearer ")
+	if !ok {
+		return "", false
+	}
+	return token, true
+}
+
+// unauthenticated is CodeUnauthorized with the specific reason as a detail
+// on the authorization metadata, so it is translated like the message.
+func unauthenticated(reason, message string) *errorx.DetailError {
+	return errorx.New(errorx.CodeUnauthorized, errors.New(message)).
+		WithDetails(errorx.Detail{Field: "authorization", Reason: reason, Message: message})
+}
+
+// UnaryAuthInterceptor authenticates unary calls with the same JWTs as the
+// HTTP API. The methods in protected, by full method name, need a token.
+func UnaryAuthInterceptor(authSvc contracts.AuthService, protected map[string]bool, logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authenticate(ctx, authSvc, protected[info.FullMethod], logger)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamAuthInterceptor is UnaryAuthInterceptor for streaming calls.
+func StreamAuthInterceptor(authSvc contracts.AuthService, protected map[string]bool, logger *zap.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), authSvc, protected[info.FullMethod], logger)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// serverStream replaces the context of a stream.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,103 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"strconv"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	blogv1 "github.com/FormalYou/clean-architecture-blog/api/proto/blog/v1"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/contracts"
+	"github.com/FormalYou/clean-architecture-blog/internal/interfaces/shared"
+)
+
+// methodGroups puts every method in the rate limit group of the HTTP routes
+// it mirrors, so both APIs share one budget per caller.
+var methodGroups = map[string]string{
+	blogv1.UserService_Register_FullMethodName:         "auth",
+	blogv1.UserService_Login_FullMethodName:            "auth",
+	blogv1.UserService_BatchGetUsers_FullMethodName:    "read",
+	blogv1.ArticleService_GetArticle_FullMethodName:    "read",
+	blogv1.ArticleService_ListArticles_FullMethodName:  "read",
+	blogv1.ArticleService_CreateArticle_FullMethodName: "write",
+	blogv1.ArticleService_UpdateArticle_FullMethodName: "write",
+	blogv1.ArticleService_DeleteArticle_FullMethodName: "write",
+}
+
+// UnaryRateLimitInterceptor applies the rate limit policies of the HTTP
+// route groups to unary calls, counting callers by peer address, user or
+// token as each policy says. Limited calls get ratelimit-limit and
+// ratelimit-remaining header metadata; rejected ones fail with
+// CodeTooManyRequests (ResourceExhausted) and a retry-after header. It
+// runs after authentication so user policies see the caller. If the
+// limiter fails the call is let through, as over HTTP.
+func UnaryRateLimitInterceptor(limiter contracts.RateLimiter, settings *shared.RateLimitSettings, authSvc contracts.AuthService, logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		setHeader := func(md metadata.MD) error { return grpc.SetHeader(ctx, md) }
+		if err := rateLimit(ctx, limiter, settings, authSvc, info.FullMethod, setHeader, logger); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamRateLimitInterceptor is UnaryRateLimitInterceptor for streaming
+// calls; a stream counts as one call.
+func StreamRateLimitInterceptor(limiter contracts.RateLimiter, settings *shared.RateLimitSettings, authSvc contracts.AuthService, logger *zap.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := rateLimit(ss.Context(), limiter, settings, authSvc, info.FullMethod, ss.SetHeader, logger); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// rateLimit counts a call of method against its group's policy.
+func rateLimit(ctx context.Context, limiter contracts.RateLimiter, settings *shared.RateLimitSettings, authSvc contracts.AuthService, method string, setHeader func(metadata.MD) error, logger *zap.Logger) error {
+	group, ok := methodGroups[method]
+	if !ok {
+		return nil
+	}
+
+	token, _ := bearerToken(ctx)
+	who := shared.IdentifyCaller(ctx, token, authSvc)
+	result, subject, limited, err := settings.Check(ctx, limiter, group, who, peerIP(ctx))
+	if err != nil {
+		logger.Warn("rate limiter unavailable, allowing call", zap.String("group", group), zap.Error(err))
+		return nil
+	}
+	if !limited {
+		return nil
+	}
+
+	md := metadata.Pairs(
+		"ratelimit-limit", strconv.Itoa(result.Limit),
+		"ratelimit-remaining", strconv.Itoa(result.Remaining),
+	)
+	if result.Allowed {
+		_ = setHeader(md)
+		return nil
+	}
+	retryAfter := shared.RetryAfter(result)
+	md.Set("retry-after", retryAfter)
+	_ = setHeader(md)
+	return shared.RateLimitExceeded(group, subject, retryAfter)
+}
+
+// peerIP is the IP address the call comes from. Calls are not expected to
+// pass a proxy, so forwarding metadata is not trusted.
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
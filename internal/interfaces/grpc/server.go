@@ -0,0 +1,67 @@
+// Package grpc serves the article and user services over gRPC for internal
+// clients, on top of the same usecases and tokens as the HTTP API.
+package grpc
+
+import (
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+
+	blogv1 "github.com/FormalYou/clean-architecture-blog/api/proto/blog/v1"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/contracts"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/repository"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/usecase"
+	"github.com/FormalYou/clean-architecture-blog/internal/infrastructure/tracing"
+	"github.com/FormalYou/clean-architecture-blog/internal/interfaces/shared"
+)
+
+// protectedMethods need a bearer token, like the authorized HTTP routes.
+var protectedMethods = map[string]bool{
+	blogv1.ArticleService_CreateArticle_FullMethodName: true,
+	blogv1.ArticleService_UpdateArticle_FullMethodName: true,
+	blogv1.ArticleService_DeleteArticle_FullMethodName: true,
+}
+
+// Protections are what the gRPC API shares with the HTTP API to keep callers
+// from abusing it. A nil RateLimiter or Idempotency turns the matching
+// interceptor off.
+type Protections struct {
+	RateLimiter        contracts.RateLimiter
+	RateLimits         *shared.RateLimitSettings
+	Idempotency        repository.IdempotencyStore
+	IdempotencyOptions shared.IdempotencyOptions
+}
+
+// NewServer creates a gRPC server serving ArticleService and UserService.
+// Every call is traced; errors are mapped to statuses outside
+// authentication, rate limiting and idempotency, so their rejections are
+// reported like any other error. Transport security comes from opts, e.g.
+// grpc.Creds.
+func NewServer(articles usecase.ArticleUsecaseInterface, users usecase.UserUsecaseInterface, authSvc contracts.AuthService, protections Protections, logger *zap.Logger, opts ...grpc.ServerOption) *grpc.Server {
+	logger = logger.Named("grpc")
+	unary := []grpc.UnaryServerInterceptor{
+		tracing.GRPCUnaryInterceptor(),
+		UnaryErrorInterceptor(logger),
+		UnaryAuthInterceptor(authSvc, protectedMethods, logger),
+	}
+	stream := []grpc.StreamServerInterceptor{
+		tracing.GRPCStreamInterceptor(),
+		StreamErrorInterceptor(logger),
+		StreamAuthInterceptor(authSvc, protectedMethods, logger),
+	}
+	if protections.RateLimiter != nil {
+		unary = append(unary, UnaryRateLimitInterceptor(protections.RateLimiter, protections.RateLimits, authSvc, logger))
+		stream = append(stream, StreamRateLimitInterceptor(protections.RateLimiter, protections.RateLimits, authSvc, logger))
+	}
+	if protections.Idempotency != nil {
+		unary = append(unary, UnaryIdempotencyInterceptor(protections.Idempotency, protections.IdempotencyOptions, logger))
+	}
+	opts = append([]grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	}, opts...)
+
+	srv := grpc.NewServer(opts...)
+	blogv1.RegisterArticleServiceServer(srv, NewArticleService(articles))
+	blogv1.RegisterUserServiceServer(srv, NewUserService(users))
+	return srv
+}
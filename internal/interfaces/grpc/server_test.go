@@ -0,0 +1,475 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	blogv1 "github.com/FormalYou/clean-architecture-blog/api/proto/blog/v1"
+	"github.com/FormalYou/clean-architecture-blog/domain"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/contracts"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/usecase/articleerr"
+	mock_usecase "github.com/FormalYou/clean-architecture-blog/internal/application/usecase/mocks"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/usecase/usererr"
+	"github.com/FormalYou/clean-architecture-blog/internal/errorx"
+	"github.com/FormalYou/clean-architecture-blog/internal/infrastructure/auth"
+	"github.com/FormalYou/clean-architecture-blog/internal/infrastructure/cache"
+	"github.com/FormalYou/clean-architecture-blog/internal/infrastructure/ratelimit"
+	"github.com/FormalYou/clean-architecture-blog/internal/interfaces/shared"
+)
+
+const testSecret = "a-sufficiently-long-secret"
+
+type testServer struct {
+	articles      *mock_usecase.MockArticleUsecaseInterface
+	users         *mock_usecase.MockUserUsecaseInterface
+	authSvc       contracts.AuthService
+	articleClient blogv1.ArticleServiceClient
+	userClient    blogv1.UserServiceClient
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+	return newProtectedTestServer(t, Protections{})
+}
+
+// newProtectedTestServer is newTestServer with rate limiting or idempotency.
+func newProtectedTestServer(t *testing.T, protections Protections) *testServer {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	s := &testServer{
+		articles: mock_usecase.NewMockArticleUsecaseInterface(ctrl),
+		users:    mock_usecase.NewMockUserUsecaseInterface(ctrl),
+		authSvc:  auth.NewJWTAuthService(testSecret),
+	}
+
+	lis := bufconn.Listen(1 << 20)
+	srv := NewServer(s.articles, s.users, s.authSvc, protections, zap.NewNop())
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	s.articleClient = blogv1.NewArticleServiceClient(conn)
+	s.userClient = blogv1.NewUserServiceClient(conn)
+	return s
+}
+
+// withToken returns a context sending a bearer token for userID.
+func (s *testServer) withToken(t *testing.T, userID int64) context.Context {
+	t.Helper()
+	token, err := s.authSvc.GenerateToken(userID)
+	require.NoError(t, err)
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+// errorInfo returns the errorx code and field violations of a status error.
+func errorInfo(t *testing.T, err error) (code int, violations []*errdetails.BadRequest_FieldViolation) {
+	t.Helper()
+	st, ok := status.FromError(err)
+	require.True(t, ok, "not a status: %v", err)
+	for _, d := range st.Details() {
+		switch d := d.(type) {
+		case *errdetails.ErrorInfo:
+			assert.Equal(t, ErrorDomain, d.GetDomain())
+			code, _ = strconv.Atoi(d.GetReason())
+		case *errdetails.BadRequest:
+			violations = d.GetFieldViolations()
+		}
+	}
+	return code, violations
+}
+
+func TestArticleService(t *testing.T) {
+	updated := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	article := &domain.Article{ID: 1, Title: "One", Content: "Body", AuthorID: 7, Version: 2, UpdatedAt: updated, Tags: []domain.Tag{{ID: 3, Name: "go"}}}
+
+	t.Run("Get", func(t *testing.T) {
+		s := newTestServer(t)
+		s.articles.EXPECT().GetArticleByID(gomock.Any(), int64(1)).Return(article, nil)
+
+		resp, err := s.articleClient.GetArticle(context.Background(), &blogv1.GetArticleRequest{Id: 1})
+		require.NoError(t, err)
+		assert.Equal(t, "One", resp.GetTitle())
+		assert.Equal(t, int64(2), resp.GetVersion())
+		assert.Equal(t, updated, resp.GetUpdatedAt().AsTime())
+		require.Len(t, resp.GetTags(), 1)
+		assert.Equal(t, "go", resp.GetTags()[0].GetName())
+	})
+
+	t.Run("Get Not Found", func(t *testing.T) {
+		s := newTestServer(t)
+		s.articles.EXPECT().GetArticleByID(gomock.Any(), int64(2)).Return(nil, errorx.New(articleerr.CodeArticleNotFound, errors.New("not found")))
+
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "accept-language", "zh-CN")
+		_, err := s.articleClient.GetArticle(ctx, &blogv1.GetArticleRequest{Id: 2})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+		assert.Equal(t, "文章不存在", status.Convert(err).Message())
+		code, _ := errorInfo(t, err)
+		assert.Equal(t, articleerr.CodeArticleNotFound, code)
+	})
+
+	t.Run("Get Invalid ID", func(t *testing.T) {
+		s := newTestServer(t)
+
+		_, err := s.articleClient.GetArticle(context.Background(), &blogv1.GetArticleRequest{})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		code, violations := errorInfo(t, err)
+		assert.Equal(t, errorx.CodeInvalidParams, code)
+		require.Len(t, violations, 1)
+		assert.Equal(t, "id", violations[0].GetField())
+		assert.Equal(t, domain.ReasonInvalidFormat, violations[0].GetReason())
+	})
+
+	t.Run("List Streams Articles", func(t *testing.T) {
+		s := newTestServer(t)
+		s.articles.EXPECT().GetAllArticles(gomock.Any()).Return([]*domain.Article{article, {ID: 2, Title: "Two"}}, nil)
+
+		stream, err := s.articleClient.ListArticles(context.Background(), &blogv1.ListArticlesRequest{})
+		require.NoError(t, err)
+		var titles []string
+		for {
+			a, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			require.NoError(t, err)
+			titles = append(titles, a.GetTitle())
+		}
+		assert.Equal(t, []string{"One", "Two"}, titles)
+	})
+
+	t.Run("List Error", func(t *testing.T) {
+		s := newTestServer(t)
+		s.articles.EXPECT().GetAllArticles(gomock.Any()).Return(nil, errorx.New(errorx.CodeTimeout, errors.New("query timeout")))
+
+		stream, err := s.articleClient.ListArticles(context.Background(), &blogv1.ListArticlesRequest{})
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	})
+
+	t.Run("Create As Caller", func(t *testing.T) {
+		s := newTestServer(t)
+		s.articles.EXPECT().CreateArticle(gomock.Any(), &domain.Article{Title: "New", Content: "Body", Tags: []domain.Tag{{Name: "go"}}}).
+			DoAndReturn(func(ctx context.Context, a *domain.Article) error {
+				// The usecase finds the caller where the HTTP middleware puts it.
+				userID, err := s.authSvc.GetUserIDFromContext(ctx)
+				require.NoError(t, err)
+				a.ID, a.AuthorID, a.Version = 5, userID, 1
+				return nil
+			})
+
+		resp, err := s.articleClient.CreateArticle(s.withToken(t, 7), &blogv1.CreateArticleRequest{Title: "New", Content: "Body", Tags: []string{"go"}})
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), resp.GetId())
+		assert.Equal(t, int64(7), resp.GetAuthorId())
+	})
+
+	t.Run("Update Reads Back", func(t *testing.T) {
+		s := newTestServer(t)
+		gomock.InOrder(
+			s.articles.EXPECT().UpdateArticle(gomock.Any(), &domain.Article{ID: 1, Title: "One", Content: "Body"}).Return(nil),
+			s.articles.EXPECT().GetArticleByID(gomock.Any(), int64(1)).Return(article, nil),
+		)
+
+		resp, err := s.articleClient.UpdateArticle(s.withToken(t, 7), &blogv1.UpdateArticleRequest{Id: 1, Title: "One", Content: "Body"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), resp.GetVersion())
+	})
+
+	t.Run("Delete Not Author", func(t *testing.T) {
+		s := newTestServer(t)
+		s.articles.EXPECT().DeleteArticle(gomock.Any(), int64(1)).Return(errorx.New(errorx.CodeUnauthorized, errors.New("not the author")))
+
+		_, err := s.articleClient.DeleteArticle(s.withToken(t, 8), &blogv1.DeleteArticleRequest{Id: 1})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("Handler Panic", func(t *testing.T) {
+		s := newTestServer(t)
+		s.articles.EXPECT().GetArticleByID(gomock.Any(), int64(1)).DoAndReturn(func(context.Context, int64) (*domain.Article, error) {
+			panic("boom")
+		})
+
+		_, err := s.articleClient.GetArticle(context.Background(), &blogv1.GetArticleRequest{Id: 1})
+		assert.Equal(t, codes.Internal, status.Code(err))
+		assert.Equal(t, "Internal Server Error", status.Convert(err).Message())
+	})
+}
+
+func TestAuthInterceptors(t *testing.T) {
+	withAuthorization := func(value string) context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), "authorization", value)
+	}
+
+	testCases := []struct {
+		name         string
+		ctx          context.Context
+		call         func(s *testServer, ctx context.Context) error
+		expectedCode codes.Code
+	}{
+		{
+			name: "Write Without Token",
+			ctx:  context.Background(),
+			call: func(s *testServer, ctx context.Context) error {
+				_, err := s.articleClient.DeleteArticle(ctx, &blogv1.DeleteArticleRequest{Id: 1})
+				return err
+			},
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name: "Write With Invalid Token",
+			ctx:  withAuthorization("Bearer not-a-token"),
+			call: func(s *testServer, ctx context.Context) error {
+				_, err := s.articleClient.CreateArticle(ctx, &blogv1.CreateArticleRequest{Title: "t", Content: "c"})
+				return err
+			},
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name: "Write Without Bearer Scheme",
+			ctx:  withAuthorization("Token abc"),
+			call: func(s *testServer, ctx context.Context) error {
+				_, err := s.articleClient.UpdateArticle(ctx, &blogv1.UpdateArticleRequest{Id: 1})
+				return err
+			},
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name: "Read With Invalid Token",
+			ctx:  withAuthorization("Bearer not-a-token"),
+			call: func(s *testServer, ctx context.Context) error {
+				_, err := s.articleClient.GetArticle(ctx, &blogv1.GetArticleRequest{Id: 1})
+				return err
+			},
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name: "Stream With Invalid Token",
+			ctx:  withAuthorization("Bearer not-a-token"),
+			call: func(s *testServer, ctx context.Context) error {
+				stream, err := s.articleClient.ListArticles(ctx, &blogv1.ListArticlesRequest{})
+				if err != nil {
+					return err
+				}
+				_, err = stream.Recv()
+				return err
+			},
+			expectedCode: codes.Unauthenticated,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newTestServer(t)
+			err := tc.call(s, tc.ctx)
+			assert.Equal(t, tc.expectedCode, status.Code(err))
+			code, _ := errorInfo(t, err)
+			assert.Equal(t, errorx.CodeUnauthorized, code)
+		})
+	}
+}
+
+func TestAuthInterceptors_LocalizeReason(t *testing.T) {
+	s := newTestServer(t)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "accept-language", "zh-CN", "authorization", "Bearer not-a-token")
+
+	_, err := s.articleClient.GetArticle(ctx, &blogv1.GetArticleRequest{Id: 1})
+	assert.Equal(t, "未授权", status.Convert(err).Message())
+	_, violations := errorInfo(t, err)
+	require.Len(t, violations, 1)
+	assert.Equal(t, errorx.ReasonInvalidToken, violations[0].GetReason())
+	assert.Equal(t, "authorization 中的令牌无效或已过期", violations[0].GetDescription())
+}
+
+func TestUserService(t *testing.T) {
+	t.Run("Register", func(t *testing.T) {
+		s := newTestServer(t)
+		s.users.EXPECT().Register(gomock.Any(), &domain.User{Username: "alice", Email: "alice@example.com", PasswordHash: "password123"}).
+			DoAndReturn(func(_ context.Context, u *domain.User) error {
+				u.ID = 7
+				return nil
+			})
+
+		resp, err := s.userClient.Register(context.Background(), &blogv1.RegisterRequest{Username: "alice", Email: "alice@example.com", Password: "password123"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(7), resp.GetId())
+	})
+
+	t.Run("Register Taken", func(t *testing.T) {
+		s := newTestServer(t)
+		s.users.EXPECT().Register(gomock.Any(), gomock.Any()).Return(errorx.New(usererr.CodeUserAlreadyExists, nil))
+
+		_, err := s.userClient.Register(context.Background(), &blogv1.RegisterRequest{Username: "alice", Email: "alice@example.com", Password: "password123"})
+		assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	})
+
+	t.Run("Register Invalid", func(t *testing.T) {
+		s := newTestServer(t)
+		// Validation happens in the usecase, as for HTTP.
+		s.users.EXPECT().Register(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, u *domain.User) error {
+			return errorx.New(errorx.CodeInvalidParams, u.Validate())
+		})
+
+		_, err := s.userClient.Register(context.Background(), &blogv1.RegisterRequest{Username: "alice", Email: "not-an-email", Password: "password123"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		_, violations := errorInfo(t, err)
+		require.Len(t, violations, 1)
+		assert.Equal(t, "email", violations[0].GetField())
+	})
+
+	t.Run("Login", func(t *testing.T) {
+		s := newTestServer(t)
+		s.users.EXPECT().Login(gomock.Any(), "alice@example.com", "wrong").Return("", errorx.New(usererr.CodeInvalidCredentials, nil))
+
+		_, err := s.userClient.Login(context.Background(), &blogv1.LoginRequest{Email: "alice@example.com", Password: "wrong"})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("Batch Get In Request Order", func(t *testing.T) {
+		s := newTestServer(t)
+		s.users.EXPECT().GetUsersByIDs(gomock.Any(), []int64{9, 7, 8, 9}).Return(map[int64]*domain.User{
+			7: {ID: 7, Username: "alice", Profile: domain.UserProfile{Nickname: "Alice"}},
+			9: {ID: 9, Username: "carol"},
+		}, nil)
+
+		resp, err := s.userClient.BatchGetUsers(context.Background(), &blogv1.BatchGetUsersRequest{Ids: []int64{9, 7, 8, 9}})
+		require.NoError(t, err)
+		require.Len(t, resp.GetUsers(), 2)
+		assert.Equal(t, "carol", resp.GetUsers()[0].GetUsername())
+		assert.Equal(t, "Alice", resp.GetUsers()[1].GetNickname())
+	})
+
+	t.Run("Batch Get Too Many", func(t *testing.T) {
+		s := newTestServer(t)
+
+		_, err := s.userClient.BatchGetUsers(context.Background(), &blogv1.BatchGetUsersRequest{Ids: make([]int64, maxBatchGetUsers+1)})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestGRPCCode(t *testing.T) {
+	testCases := []struct {
+		code     int
+		expected codes.Code
+	}{
+		{errorx.CodeInvalidParams, codes.InvalidArgument},
+		{errorx.CodeUnauthorized, codes.Unauthenticated},
+		{articleerr.CodeArticleNotFound, codes.NotFound},
+		{usererr.CodeUserAlreadyExists, codes.AlreadyExists},
+		{errorx.CodeRequestInProgress, codes.Aborted},
+		{errorx.CodeTooManyRequests, codes.ResourceExhausted},
+		{errorx.CodeTimeout, codes.DeadlineExceeded},
+		{errorx.CodeInternalServerError, codes.Internal},
+	}
+	for _, tc := range testCases {
+		t.Run(strconv.Itoa(tc.code), func(t *testing.T) {
+			assert.Equal(t, tc.expected, grpcCode(errorx.New(tc.code, nil)))
+		})
+	}
+}
+
+func TestRateLimitInterceptors(t *testing.T) {
+	settings := shared.NewRateLimitSettings(shared.RateLimitRules{Policies: map[string]shared.RateLimitPolicy{
+		"auth": {Limit: 1, Window: time.Minute, KeyBy: shared.RateLimitByIP},
+		"read": {Limit: 1, Window: time.Minute, KeyBy: shared.RateLimitByUser},
+	}})
+	s := newProtectedTestServer(t, Protections{RateLimiter: ratelimit.NewMemoryLimiter(), RateLimits: settings})
+
+	t.Run("Login Shares The Auth Policy", func(t *testing.T) {
+		s.users.EXPECT().Login(gomock.Any(), "alice@example.com", "secret").Return("token", nil)
+		var header metadata.MD
+		_, err := s.userClient.Login(context.Background(), &blogv1.LoginRequest{Email: "alice@example.com", Password: "secret"}, grpc.Header(&header))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"0"}, header.Get("ratelimit-remaining"))
+
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "accept-language", "zh-CN")
+		_, err = s.userClient.Register(ctx, &blogv1.RegisterRequest{Username: "alice", Email: "alice@example.com", Password: "password123"}, grpc.Header(&header))
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+		code, _ := errorInfo(t, err)
+		assert.Equal(t, errorx.CodeTooManyRequests, code)
+		require.Len(t, header.Get("retry-after"), 1)
+		assert.Contains(t, status.Convert(err).Message(), header.Get("retry-after")[0])
+	})
+
+	t.Run("Read Policy Counts Users Apart", func(t *testing.T) {
+		s.articles.EXPECT().GetArticleByID(gomock.Any(), int64(1)).Return(&domain.Article{ID: 1}, nil).Times(2)
+		_, err := s.articleClient.GetArticle(s.withToken(t, 7), &blogv1.GetArticleRequest{Id: 1})
+		require.NoError(t, err)
+		_, err = s.articleClient.GetArticle(s.withToken(t, 8), &blogv1.GetArticleRequest{Id: 1})
+		require.NoError(t, err)
+
+		stream, err := s.articleClient.ListArticles(s.withToken(t, 7), &blogv1.ListArticlesRequest{})
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	})
+
+	t.Run("Groups Without A Policy Pass", func(t *testing.T) {
+		s.articles.EXPECT().DeleteArticle(gomock.Any(), int64(1)).Return(nil).Times(2)
+		for i := 0; i < 2; i++ {
+			_, err := s.articleClient.DeleteArticle(s.withToken(t, 7), &blogv1.DeleteArticleRequest{Id: 1})
+			require.NoError(t, err)
+		}
+	})
+}
+
+func TestIdempotencyInterceptor(t *testing.T) {
+	withKey := func(ctx context.Context, key string) context.Context {
+		return metadata.AppendToOutgoingContext(ctx, IdempotencyKeyMetadata, key)
+	}
+	s := newProtectedTestServer(t, Protections{
+		Idempotency:        cache.NewMemoryIdempotencyStore(),
+		IdempotencyOptions: shared.IdempotencyOptions{TTL: time.Hour, LockTTL: time.Minute},
+	})
+	req := &blogv1.RegisterRequest{Username: "alice", Email: "alice@example.com", Password: "password123"}
+
+	s.users.EXPECT().Register(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, u *domain.User) error {
+		u.ID = 7
+		return nil
+	})
+	resp, err := s.userClient.Register(withKey(context.Background(), "k"), req)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), resp.GetId())
+
+	var header metadata.MD
+	resp, err = s.userClient.Register(withKey(context.Background(), "k"), req, grpc.Header(&header))
+	require.NoError(t, err, "replayed without calling the usecase again")
+	assert.Equal(t, int64(7), resp.GetId())
+	assert.Equal(t, []string{"true"}, header.Get("idempotent-replayed"))
+
+	_, err = s.userClient.Register(withKey(context.Background(), "k"), &blogv1.RegisterRequest{Username: "bob", Email: "bob@example.com", Password: "password123"})
+	code, _ := errorInfo(t, err)
+	assert.Equal(t, errorx.CodeIdempotencyMismatch, code)
+
+	// Keys are scoped to the caller, so a user's key does not replay the
+	// anonymous registration.
+	s.articles.EXPECT().CreateArticle(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, a *domain.Article) error {
+		a.ID = 5
+		return nil
+	})
+	article, err := s.articleClient.CreateArticle(withKey(s.withToken(t, 7), "k"), &blogv1.CreateArticleRequest{Title: "New", Content: "Body"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), article.GetId())
+}
@@ -0,0 +1,92 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	blogv1 "github.com/FormalYou/clean-architecture-blog/api/proto/blog/v1"
+	"github.com/FormalYou/clean-architecture-blog/domain"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/usecase"
+	"github.com/FormalYou/clean-architecture-blog/internal/errorx"
+)
+
+// maxBatchGetUsers bounds the IDs of one BatchGetUsers call.
+const maxBatchGetUsers = 100
+
+// UserService implements blogv1.UserServiceServer on the user usecase.
+type UserService struct {
+	blogv1.UnimplementedUserServiceServer
+	users usecase.UserUsecaseInterface
+}
+
+// NewUserService creates a UserService.
+func NewUserService(users usecase.UserUsecaseInterface) *UserService {
+	return &UserService{users: users}
+}
+
+func (s *UserService) Register(ctx context.Context, req *blogv1.RegisterRequest) (*blogv1.RegisterResponse, error) {
+	user := &domain.User{
+		Username:     req.GetUsername(),
+		Email:        req.GetEmail(),
+		PasswordHash: req.GetPassword(),
+	}
+	if req.GetPassword() == "" {
+		return nil, errorx.New(errorx.CodeInvalidParams, errors.New("password is required")).WithDetails(errorx.Detail{
+			Field:   "password",
+			Reason:  domain.ReasonRequired,
+			Message: "password is required",
+		})
+	}
+
+	if err := s.users.Register(ctx, user); err != nil {
+		return nil, err
+	}
+	return &blogv1.RegisterResponse{Id: user.ID}, nil
+}
+
+func (s *UserService) Login(ctx context.Context, req *blogv1.LoginRequest) (*blogv1.LoginResponse, error) {
+	token, err := s.users.Login(ctx, req.GetEmail(), req.GetPassword())
+	if err != nil {
+		return nil, err
+	}
+	return &blogv1.LoginResponse{Token: token}, nil
+}
+
+func (s *UserService) BatchGetUsers(ctx context.Context, req *blogv1.BatchGetUsersRequest) (*blogv1.BatchGetUsersResponse, error) {
+	ids := req.GetIds()
+	if len(ids) > maxBatchGetUsers {
+		message := fmt.Sprintf("at most %d ids are allowed", maxBatchGetUsers)
+		return nil, errorx.New(errorx.CodeInvalidParams, errors.New(message)).WithDetails(errorx.Detail{
+			Field:   "ids",
+			Reason:  "max",
+			Message: message,
+		})
+	}
+	for i, id := range ids {
+		if err := checkID(id, fmt.Sprintf("ids[%d]", i)); err != nil {
+			return nil, err
+		}
+	}
+
+	found, err := s.users.GetUsersByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	resp := &blogv1.BatchGetUsersResponse{}
+	seen := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		user, ok := found[id]
+		if !ok || seen[id] {
+			continue
+		}
+		seen[id] = true
+		resp.Users = append(resp.Users, &blogv1.User{
+			Id:       user.ID,
+			Username: user.Username,
+			Nickname: user.Profile.Nickname,
+			Avatar:   user.Profile.Avatar,
+		})
+	}
+	return resp, nil
+}
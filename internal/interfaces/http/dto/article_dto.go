@@ -15,12 +15,28 @@ type UpdateArticleRequest struct {
 	Content string `json:"content" binding:"required"`
 }
 
-// ArticleResponse DTO for an article
+// ArticleResponse DTO for an article. Author and Tags are only present when
+// requested with ?include, see ArticleIncludes.
 type ArticleResponse struct {
-	ID        uint      `json:"id"`
-	Title     string    `json:"title"`
-	Content   string    `json:"content"`
-	Author    string    `json:"author"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-}
\ No newline at end of file
+	ID        int64           `json:"id"`
+	Title     string          `json:"title"`
+	Content   string          `json:"content"`
+	AuthorID  int64           `json:"author_id"`
+	Author    *AuthorResponse `json:"author,omitempty"`
+	Tags      []TagResponse   `json:"tags,omitzero"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// AuthorResponse DTO for the author of an article
+type AuthorResponse struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+	Nickname string `json:"nickname"`
+}
+
+// TagResponse DTO for a tag
+type TagResponse struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
@@ -0,0 +1,96 @@
+package dto
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/FormalYou/clean-architecture-blog/domain"
+)
+
+// Related resources an article response can embed, named in the include
+// query parameter, e.g. ?include=author,tags.
+const (
+	IncludeAuthor = "author"
+	IncludeTags   = "tags"
+)
+
+// ArticleIncludes selects the related resources embedded in article responses.
+type ArticleIncludes struct {
+	Author bool
+	Tags   bool
+}
+
+// DefaultArticleIncludes applies when a request has no include parameter.
+var DefaultArticleIncludes = ArticleIncludes{Author: true, Tags: true}
+
+// ParseArticleIncludes parses a comma-separated include parameter. Without
+// the parameter (present false) DefaultArticleIncludes applies, while an empty
+// value embeds nothing.
+func ParseArticleIncludes(raw string, present bool) (ArticleIncludes, error) {
+	if !present {
+		return DefaultArticleIncludes, nil
+	}
+	var inc ArticleIncludes
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		switch name {
+		case "":
+		case IncludeAuthor:
+			inc.Author = true
+		case IncludeTags:
+			inc.Tags = true
+		default:
+			return ArticleIncludes{}, fmt.Errorf("include %q is not one of %s, %s", name, IncludeAuthor, IncludeTags)
+		}
+	}
+	return inc, nil
+}
+
+// AuthorIDs lists the distinct authors of articles, so they can be resolved
+// in one batch.
+func AuthorIDs(articles ...*domain.Article) []int64 {
+	var ids []int64
+	seen := make(map[int64]bool, len(articles))
+	for _, a := range articles {
+		if !seen[a.AuthorID] {
+			seen[a.AuthorID] = true
+			ids = append(ids, a.AuthorID)
+		}
+	}
+	return ids
+}
+
+// NewArticleResponse maps an article to its response. authors holds the
+// resolved users by ID; an author missing from it, e.g. a deleted account,
+// is left out.
+func NewArticleResponse(a *domain.Article, authors map[int64]*domain.User, inc ArticleIncludes) ArticleResponse {
+	resp := ArticleResponse{
+		ID:        a.ID,
+		Title:     a.Title,
+		Content:   a.Content,
+		AuthorID:  a.AuthorID,
+		CreatedAt: a.CreatedAt,
+		UpdatedAt: a.UpdatedAt,
+	}
+	if inc.Author {
+		if user, ok := authors[a.AuthorID]; ok {
+			resp.Author = &AuthorResponse{ID: user.ID, Username: user.Username, Nickname: user.Profile.Nickname}
+		}
+	}
+	if inc.Tags {
+		resp.Tags = make([]TagResponse, len(a.Tags))
+		for i, tag := range a.Tags {
+			resp.Tags[i] = TagResponse{ID: tag.ID, Name: tag.Name}
+		}
+	}
+	return resp
+}
+
+// NewArticleResponses maps a list of articles, see NewArticleResponse.
+func NewArticleResponses(articles []*domain.Article, authors map[int64]*domain.User, inc ArticleIncludes) []ArticleResponse {
+	resp := make([]ArticleResponse, len(articles))
+	for i, a := range articles {
+		resp[i] = NewArticleResponse(a, authors, inc)
+	}
+	return resp
+}
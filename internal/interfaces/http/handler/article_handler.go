@@ -3,6 +3,7 @@ package handler
 import (
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -10,25 +11,39 @@ import (
 	"github.com/FormalYou/clean-architecture-blog/domain"
 	"github.com/FormalYou/clean-architecture-blog/internal/application/usecase"
 	"github.com/FormalYou/clean-architecture-blog/internal/errorx"
+	"github.com/FormalYou/clean-architecture-blog/internal/infrastructure/log"
 	"github.com/FormalYou/clean-architecture-blog/internal/interfaces/http/dto"
 )
 
 // ArticleHandler handles HTTP requests for articles
 type ArticleHandler struct {
 	usecase usecase.ArticleUsecaseInterface
+	users   usecase.UserUsecaseInterface
+	cache   CachePolicy
 	logger  *zap.Logger
 }
 
-// NewArticleHandler creates a new ArticleHandler
-func NewArticleHandler(usecase usecase.ArticleUsecaseInterface, logger *zap.Logger) *ArticleHandler {
-	return &ArticleHandler{
+// NewArticleHandler creates a new ArticleHandler. users resolves the authors
+// embedded in article responses.
+func NewArticleHandler(usecase usecase.ArticleUsecaseInterface, users usecase.UserUsecaseInterface, logger *zap.Logger, opts ...ArticleHandlerOption) *ArticleHandler {
+	h := &ArticleHandler{
 		usecase: usecase,
+		users:   users,
 		logger:  logger.Named("ArticleHandler"),
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 // Create handles the creation of a new article
 func (h *ArticleHandler) Create(c *gin.Context) {
+	inc, ok := h.includes(c)
+	if !ok {
+		return
+	}
+
 	var req dto.CreateArticleRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.Error(errorx.New(errorx.CodeInvalidParams, err))
@@ -49,38 +64,86 @@ func (h *ArticleHandler) Create(c *gin.Context) {
 		return
 	}
 
-	h.logger.Info("article created successfully", zap.Int64("article_id", article.ID))
-	c.JSON(http.StatusCreated, article)
+	authors, err := h.authors(c, inc, article)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	log.WithTrace(c.Request.Context(), h.logger).Info("article created successfully", zap.Int64("article_id", article.ID))
+	c.JSON(http.StatusCreated, dto.NewArticleResponse(article, authors, inc))
 }
 
-// GetByID handles retrieving a single article by its ID
+// GetByID handles retrieving a single article by its ID. It answers a
+// matching If-None-Match or If-Modified-Since with 304 Not Modified. With
+// the author embedded there is no Last-Modified: users carry no update
+// time, so a profile change would alter the body without advancing it.
 func (h *ArticleHandler) GetByID(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
 		c.Error(errorx.New(errorx.CodeInvalidParams, err))
 		return
 	}
+	inc, ok := h.includes(c)
+	if !ok {
+		return
+	}
 
 	article, err := h.usecase.GetArticleByID(c.Request.Context(), id)
 	if err != nil {
 		c.Error(err)
 		return
 	}
+	authors, err := h.authors(c, inc, article)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	etag := articleETag([]*domain.Article{article}, authors, inc)
+	lastModified := article.UpdatedAt
+	if inc.Author {
+		lastModified = time.Time{}
+	}
+	h.writeCacheHeaders(c, h.cache.Article, etag, lastModified, surrogateKeys([]*domain.Article{article}, authors))
+	if notModified(c.Request, etag, lastModified) {
+		c.Status(http.StatusNotModified)
+		return
+	}
 
-	h.logger.Info("article retrieved successfully", zap.Int64("article_id", article.ID))
-	c.JSON(http.StatusOK, article)
+	log.WithTrace(c.Request.Context(), h.logger).Info("article retrieved successfully", zap.Int64("article_id", article.ID))
+	c.JSON(http.StatusOK, dto.NewArticleResponse(article, authors, inc))
 }
 
-// GetAll handles retrieving all articles
+// GetAll handles retrieving all articles. The list has an ETag but no
+// Last-Modified: deleting an article changes it without advancing any
+// article's update time.
 func (h *ArticleHandler) GetAll(c *gin.Context) {
+	inc, ok := h.includes(c)
+	if !ok {
+		return
+	}
+
 	articles, err := h.usecase.GetAllArticles(c.Request.Context())
 	if err != nil {
 		c.Error(err)
 		return
 	}
+	authors, err := h.authors(c, inc, articles...)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	etag := articleETag(articles, authors, inc)
+	h.writeCacheHeaders(c, h.cache.List, etag, time.Time{}, append([]string{articlesSurrogateKey}, surrogateKeys(articles, authors)...))
+	if notModified(c.Request, etag, time.Time{}) {
+		c.Status(http.StatusNotModified)
+		return
+	}
 
-	h.logger.Info("retrieved all articles", zap.Int("count", len(articles)))
-	c.JSON(http.StatusOK, articles)
+	log.WithTrace(c.Request.Context(), h.logger).Info("retrieved all articles", zap.Int("count", len(articles)))
+	c.JSON(http.StatusOK, dto.NewArticleResponses(articles, authors, inc))
 }
 
 // Update handles updating an existing article
@@ -108,7 +171,7 @@ func (h *ArticleHandler) Update(c *gin.Context) {
 		return
 	}
 
-	h.logger.Info("article updated successfully", zap.Int64("article_id", id))
+	log.WithTrace(c.Request.Context(), h.logger).Info("article updated successfully", zap.Int64("article_id", id))
 	c.JSON(http.StatusOK, gin.H{"status": "article updated"})
 }
 
@@ -125,6 +188,31 @@ func (h *ArticleHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	h.logger.Info("article deleted successfully", zap.Int64("article_id", id))
+	log.WithTrace(c.Request.Context(), h.logger).Info("article deleted successfully", zap.Int64("article_id", id))
 	c.JSON(http.StatusOK, gin.H{"status": "article deleted"})
 }
+
+// includes parses the include query parameter, reporting an invalid value
+// as CodeInvalidParams.
+func (h *ArticleHandler) includes(c *gin.Context) (dto.ArticleIncludes, bool) {
+	raw, present := c.GetQuery("include")
+	inc, err := dto.ParseArticleIncludes(raw, present)
+	if err != nil {
+		c.Error(errorx.New(errorx.CodeInvalidParams, err).WithDetails(errorx.Detail{
+			Field:   "include",
+			Reason:  domain.ReasonInvalidFormat,
+			Message: err.Error(),
+		}))
+		return inc, false
+	}
+	return inc, true
+}
+
+// authors resolves the authors of articles in one batch, or returns nil
+// when the response does not embed them.
+func (h *ArticleHandler) authors(c *gin.Context, inc dto.ArticleIncludes, articles ...*domain.Article) (map[int64]*domain.User, error) {
+	if !inc.Author || len(articles) == 0 {
+		return nil, nil
+	}
+	return h.users.GetUsersByIDs(c.Request.Context(), dto.AuthorIDs(articles...))
+}
@@ -7,7 +7,6 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
-	"github.com/FormalYou/clean-architecture-blog/domain"
 	"github.com/FormalYou/clean-architecture-blog/internal/interfaces/http/dto"
 )
 
@@ -28,7 +27,12 @@ func TestArticleHandler_Integration_Create(t *testing.T) {
 	w = PerformRequest(TestRouter, "POST", "/articles", ToJSON(createReq), token)
 
 	assert.Equal(t, http.StatusCreated, w.Code)
-	var articleResp domain.Article
+	var articleResp dto.ArticleResponse
 	json.Unmarshal(w.Body.Bytes(), &articleResp)
 	assert.Equal(t, "Integration Test", articleResp.Title)
+	if assert.NotNil(t, articleResp.Author) {
+		assert.Equal(t, "testuser", articleResp.Author.Username)
+	}
+	assert.Len(t, articleResp.Tags, 2)
+	assert.False(t, articleResp.CreatedAt.IsZero())
 }
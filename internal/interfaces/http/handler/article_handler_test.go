@@ -2,6 +2,7 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -16,11 +17,17 @@ import (
 
 	"github.com/FormalYou/clean-architecture-blog/domain"
 	"github.com/FormalYou/clean-architecture-blog/internal/application/repository"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/usecase/articleerr"
 	mock_usecase "github.com/FormalYou/clean-architecture-blog/internal/application/usecase/mocks"
 	"github.com/FormalYou/clean-architecture-blog/internal/errorx"
 	"github.com/FormalYou/clean-architecture-blog/internal/interfaces/http/handler/middleware"
 )
 
+// zeroTime is how an unset CreatedAt or UpdatedAt is rendered.
+const zeroTime = "0001-01-01T00:00:00Z"
+
+var testAuthor = &domain.User{ID: 7, Username: "alice", Profile: domain.UserProfile{Nickname: "Alice"}}
+
 func setupArticleRouter(articleHandler *ArticleHandler) *gin.Engine {
 	router := gin.Default()
 	router.Use(func(c *gin.Context) {
@@ -42,9 +49,10 @@ func TestArticleHandler_Create(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockArticleUsecase := mock_usecase.NewMockArticleUsecaseInterface(ctrl)
+	mockUserUsecase := mock_usecase.NewMockUserUsecaseInterface(ctrl)
 	logger := zap.NewNop()
 
-	handler := NewArticleHandler(mockArticleUsecase, logger)
+	handler := NewArticleHandler(mockArticleUsecase, mockUserUsecase, logger)
 	router := setupArticleRouter(handler)
 
 	testCases := []struct {
@@ -62,17 +70,61 @@ func TestArticleHandler_Create(t *testing.T) {
 				"tags":    []string{"go", "test"},
 			},
 			setupMocks: func() {
-				mockArticleUsecase.EXPECT().CreateArticle(gomock.Any(), gomock.Any()).Return(nil)
+				mockArticleUsecase.EXPECT().CreateArticle(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, a *domain.Article) error {
+					a.ID, a.AuthorID = 1, testAuthor.ID
+					return nil
+				})
+				mockUserUsecase.EXPECT().GetUsersByIDs(gomock.Any(), []int64{testAuthor.ID}).Return(map[int64]*domain.User{testAuthor.ID: testAuthor}, nil)
 			},
 			expectedStatus: http.StatusCreated,
-			expectedBody:   gin.H{"ID": float64(0), "Title": "Test Article", "Content": "This is a test content.", "AuthorID": float64(0), "Tags": []interface{}{map[string]interface{}{"ID": float64(0), "Name": "go"}, map[string]interface{}{"ID": float64(0), "Name": "test"}}},
+			expectedBody: gin.H{"id": float64(1), "title": "Test Article", "content": "This is a test content.", "author_id": float64(7),
+				"author":     map[string]interface{}{"id": float64(7), "username": "alice", "nickname": "Alice"},
+				"tags":       []interface{}{map[string]interface{}{"id": float64(0), "name": "go"}, map[string]interface{}{"id": float64(0), "name": "test"}},
+				"created_at": zeroTime, "updated_at": zeroTime},
 		},
 		{
 			name:           "Invalid JSON",
 			requestBody:    gin.H{"title": "Test Article"},
 			setupMocks:     func() {},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   gin.H{"code": float64(errorx.CodeInvalidParams), "message": "Invalid Parameters"},
+			expectedBody: gin.H{"code": float64(errorx.CodeInvalidParams), "message": "Invalid Parameters", "details": []interface{}{
+				map[string]interface{}{"field": "content", "reason": "required", "message": "content is required"},
+			}},
+		},
+		{
+			name:           "Every Missing Field Reported",
+			requestBody:    gin.H{},
+			setupMocks:     func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: gin.H{"code": float64(errorx.CodeInvalidParams), "message": "Invalid Parameters", "details": []interface{}{
+				map[string]interface{}{"field": "title", "reason": "required", "message": "title is required"},
+				map[string]interface{}{"field": "content", "reason": "required", "message": "content is required"},
+			}},
+		},
+		{
+			name:           "Wrong Field Type",
+			requestBody:    gin.H{"title": "Test Article", "content": "c", "tags": "go"},
+			setupMocks:     func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: gin.H{"code": float64(errorx.CodeInvalidParams), "message": "Invalid Parameters", "details": []interface{}{
+				map[string]interface{}{"field": "tags", "reason": "invalid_type", "message": "tags must be of type []string"},
+			}},
+		},
+		{
+			name: "Domain Validation Error",
+			requestBody: gin.H{
+				"title":   "Test Article",
+				"content": "This is a test content.",
+			},
+			setupMocks: func() {
+				mockArticleUsecase.EXPECT().CreateArticle(gomock.Any(), gomock.Any()).Return(errorx.New(errorx.CodeInvalidParams, (&domain.Article{}).Validate()))
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: gin.H{"code": float64(errorx.CodeInvalidParams), "message": "Invalid Parameters", "details": []interface{}{
+				map[string]interface{}{"field": "title", "reason": "required", "message": "title is required"},
+				map[string]interface{}{"field": "content", "reason": "required", "message": "content is required"},
+				map[string]interface{}{"field": "author_id", "reason": "required", "message": "author is required"},
+			}},
 		},
 		{
 			name: "Usecase Error",
@@ -114,16 +166,18 @@ func TestArticleHandler_GetByID(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockArticleUsecase := mock_usecase.NewMockArticleUsecaseInterface(ctrl)
+	mockUserUsecase := mock_usecase.NewMockUserUsecaseInterface(ctrl)
 	logger := zap.NewNop()
 
-	handler := NewArticleHandler(mockArticleUsecase, logger)
+	handler := NewArticleHandler(mockArticleUsecase, mockUserUsecase, logger)
 	router := setupArticleRouter(handler)
 
-	expectedArticle := &domain.Article{ID: 1, Title: "Test Article", Content: "Test Content"}
+	expectedArticle := &domain.Article{ID: 1, Title: "Test Article", Content: "Test Content", AuthorID: testAuthor.ID, Tags: []domain.Tag{{ID: 3, Name: "go"}}}
 
 	testCases := []struct {
 		name           string
 		articleID      string
+		query          string
 		setupMocks     func()
 		expectedStatus int
 		expectedBody   gin.H
@@ -131,11 +185,58 @@ func TestArticleHandler_GetByID(t *testing.T) {
 		{
 			name:      "Success",
 			articleID: "1",
+			setupMocks: func() {
+				mockArticleUsecase.EXPECT().GetArticleByID(gomock.Any(), int64(1)).Return(expectedArticle, nil)
+				mockUserUsecase.EXPECT().GetUsersByIDs(gomock.Any(), []int64{testAuthor.ID}).Return(map[int64]*domain.User{testAuthor.ID: testAuthor}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: gin.H{"id": float64(1), "title": "Test Article", "content": "Test Content", "author_id": float64(7),
+				"author":     map[string]interface{}{"id": float64(7), "username": "alice", "nickname": "Alice"},
+				"tags":       []interface{}{map[string]interface{}{"id": float64(3), "name": "go"}},
+				"created_at": zeroTime, "updated_at": zeroTime},
+		},
+		{
+			name:      "Include Tags Only",
+			articleID: "1",
+			query:     "?include=tags",
 			setupMocks: func() {
 				mockArticleUsecase.EXPECT().GetArticleByID(gomock.Any(), int64(1)).Return(expectedArticle, nil)
 			},
 			expectedStatus: http.StatusOK,
-			expectedBody:   gin.H{"ID": float64(1), "Title": "Test Article", "Content": "Test Content", "AuthorID": float64(0), "Tags": interface{}(nil)},
+			expectedBody: gin.H{"id": float64(1), "title": "Test Article", "content": "Test Content", "author_id": float64(7),
+				"tags":       []interface{}{map[string]interface{}{"id": float64(3), "name": "go"}},
+				"created_at": zeroTime, "updated_at": zeroTime},
+		},
+		{
+			name:      "Include Nothing",
+			articleID: "1",
+			query:     "?include=",
+			setupMocks: func() {
+				mockArticleUsecase.EXPECT().GetArticleByID(gomock.Any(), int64(1)).Return(expectedArticle, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: gin.H{"id": float64(1), "title": "Test Article", "content": "Test Content", "author_id": float64(7),
+				"created_at": zeroTime, "updated_at": zeroTime},
+		},
+		{
+			name:           "Invalid Include",
+			articleID:      "1",
+			query:          "?include=author,comments",
+			setupMocks:     func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: gin.H{"code": float64(errorx.CodeInvalidParams), "message": "Invalid Parameters", "details": []interface{}{
+				map[string]interface{}{"field": "include", "reason": "invalid_format", "message": `include "comments" is not one of author, tags`},
+			}},
+		},
+		{
+			name:      "Author Lookup Error",
+			articleID: "1",
+			setupMocks: func() {
+				mockArticleUsecase.EXPECT().GetArticleByID(gomock.Any(), int64(1)).Return(expectedArticle, nil)
+				mockUserUsecase.EXPECT().GetUsersByIDs(gomock.Any(), gomock.Any()).Return(nil, errorx.New(errorx.CodeInternalServerError, errors.New("db error")))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   gin.H{"code": float64(errorx.CodeInternalServerError), "message": "Internal Server Error"},
 		},
 		{
 			name:           "Invalid ID",
@@ -148,17 +249,17 @@ func TestArticleHandler_GetByID(t *testing.T) {
 			name:      "Article Not Found",
 			articleID: "2",
 			setupMocks: func() {
-				mockArticleUsecase.EXPECT().GetArticleByID(gomock.Any(), int64(2)).Return(nil, errorx.New(errorx.CodeArticleNotFound, repository.ErrNotFound))
+				mockArticleUsecase.EXPECT().GetArticleByID(gomock.Any(), int64(2)).Return(nil, errorx.New(articleerr.CodeArticleNotFound, repository.ErrNotFound))
 			},
 			expectedStatus: http.StatusNotFound,
-			expectedBody:   gin.H{"code": float64(errorx.CodeArticleNotFound), "message": "Article not found"},
+			expectedBody:   gin.H{"code": float64(articleerr.CodeArticleNotFound), "message": "Article not found"},
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.setupMocks()
-			req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/articles/%s", tc.articleID), nil)
+			req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/articles/%s%s", tc.articleID, tc.query), nil)
 			rr := httptest.NewRecorder()
 			router.ServeHTTP(rr, req)
 
@@ -176,14 +277,16 @@ func TestArticleHandler_GetAll(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockArticleUsecase := mock_usecase.NewMockArticleUsecaseInterface(ctrl)
+	mockUserUsecase := mock_usecase.NewMockUserUsecaseInterface(ctrl)
 	logger := zap.NewNop()
 
-	handler := NewArticleHandler(mockArticleUsecase, logger)
+	handler := NewArticleHandler(mockArticleUsecase, mockUserUsecase, logger)
 	router := setupArticleRouter(handler)
 
 	expectedArticles := []*domain.Article{
-		{ID: 1, Title: "Article 1"},
-		{ID: 2, Title: "Article 2"},
+		{ID: 1, Title: "Article 1", AuthorID: testAuthor.ID},
+		{ID: 2, Title: "Article 2", AuthorID: testAuthor.ID},
+		{ID: 3, Title: "Article 3", AuthorID: 8},
 	}
 
 	testCases := []struct {
@@ -196,9 +299,23 @@ func TestArticleHandler_GetAll(t *testing.T) {
 			name: "Success",
 			setupMocks: func() {
 				mockArticleUsecase.EXPECT().GetAllArticles(gomock.Any()).Return(expectedArticles, nil)
+				// One lookup for every distinct author; user 8 no longer exists.
+				mockUserUsecase.EXPECT().GetUsersByIDs(gomock.Any(), []int64{testAuthor.ID, 8}).Return(map[int64]*domain.User{testAuthor.ID: testAuthor}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: []gin.H{
+				{"id": float64(1), "title": "Article 1", "content": "", "author_id": float64(7), "author": map[string]interface{}{"id": float64(7), "username": "alice", "nickname": "Alice"}, "tags": []interface{}{}, "created_at": zeroTime, "updated_at": zeroTime},
+				{"id": float64(2), "title": "Article 2", "content": "", "author_id": float64(7), "author": map[string]interface{}{"id": float64(7), "username": "alice", "nickname": "Alice"}, "tags": []interface{}{}, "created_at": zeroTime, "updated_at": zeroTime},
+				{"id": float64(3), "title": "Article 3", "content": "", "author_id": float64(8), "tags": []interface{}{}, "created_at": zeroTime, "updated_at": zeroTime},
+			},
+		},
+		{
+			name: "Empty List",
+			setupMocks: func() {
+				mockArticleUsecase.EXPECT().GetAllArticles(gomock.Any()).Return([]*domain.Article{}, nil)
 			},
 			expectedStatus: http.StatusOK,
-			expectedBody:   []gin.H{{"ID": float64(1), "Title": "Article 1", "AuthorID": float64(0), "Content": "", "Tags": interface{}(nil)}, {"ID": float64(2), "Title": "Article 2", "AuthorID": float64(0), "Content": "", "Tags": interface{}(nil)}},
+			expectedBody:   []gin.H{},
 		},
 		{
 			name: "Usecase Error",
@@ -241,9 +358,10 @@ func TestArticleHandler_Update(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockArticleUsecase := mock_usecase.NewMockArticleUsecaseInterface(ctrl)
+	mockUserUsecase := mock_usecase.NewMockUserUsecaseInterface(ctrl)
 	logger := zap.NewNop()
 
-	handler := NewArticleHandler(mockArticleUsecase, logger)
+	handler := NewArticleHandler(mockArticleUsecase, mockUserUsecase, logger)
 	router := setupArticleRouter(handler)
 
 	testCases := []struct {
@@ -318,9 +436,10 @@ func TestArticleHandler_Delete(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockArticleUsecase := mock_usecase.NewMockArticleUsecaseInterface(ctrl)
+	mockUserUsecase := mock_usecase.NewMockUserUsecaseInterface(ctrl)
 	logger := zap.NewNop()
 
-	handler := NewArticleHandler(mockArticleUsecase, logger)
+	handler := NewArticleHandler(mockArticleUsecase, mockUserUsecase, logger)
 	router := setupArticleRouter(handler)
 
 	testCases := []struct {
@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/FormalYou/clean-architecture-blog/internal/application/contracts"
+)
+
+// CacheHandler exposes article cache statistics for operators.
+type CacheHandler struct {
+	cache contracts.CacheStatsProvider
+}
+
+// NewCacheHandler creates a new CacheHandler.
+func NewCacheHandler(cache contracts.CacheStatsProvider) *CacheHandler {
+	return &CacheHandler{cache: cache}
+}
+
+// Stats reports the hit ratio of the local and the Redis tier.
+func (h *CacheHandler) Stats(c *gin.Context) {
+	c.JSON(http.StatusOK, h.cache.Stats())
+}
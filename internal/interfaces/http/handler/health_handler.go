@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/FormalYou/clean-architecture-blog/internal/infrastructure/health"
+)
+
+// HealthHandler serves the liveness and readiness probes.
+type HealthHandler struct {
+	health *health.Service
+}
+
+// NewHealthHandler creates a new HealthHandler.
+func NewHealthHandler(health *health.Service) *HealthHandler {
+	return &HealthHandler{health: health}
+}
+
+// Liveness reports that the process is up and able to serve HTTP.
+func (h *HealthHandler) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": health.StatusOK})
+}
+
+// Readiness runs the dependency checks and reports whether the process
+// should receive traffic.
+func (h *HealthHandler) Readiness(c *gin.Context) {
+	report := h.health.Readiness(c.Request.Context())
+	status := http.StatusOK
+	if !report.Ready() {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
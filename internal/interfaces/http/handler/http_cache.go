@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/FormalYou/clean-architecture-blog/domain"
+	"github.com/FormalYou/clean-architecture-blog/internal/interfaces/http/dto"
+)
+
+// CachePolicy sets the caching headers of the public article reads. An empty
+// value leaves its header out.
+type CachePolicy struct {
+	// Article and List are the Cache-Control values of a single article and
+	// of the article list.
+	Article string
+	List    string
+	// SurrogateKeyHeader names the header listing the surrogate keys of a
+	// response, e.g. Surrogate-Key or Cache-Tag, so a CDN can purge every
+	// cached response that embeds an article.
+	SurrogateKeyHeader string
+}
+
+// Surrogate keys: the list carries articlesSurrogateKey, and every response
+// carries the keys of the articles and authors it embeds. Purging
+// article-<id> after an edit thus drops the article and every list showing it.
+const articlesSurrogateKey = "articles"
+
+func articleSurrogateKey(id int64) string { return fmt.Sprintf("article-%d", id) }
+
+func userSurrogateKey(id int64) string { return fmt.Sprintf("user-%d", id) }
+
+// ArticleHandlerOption configures an ArticleHandler.
+type ArticleHandlerOption func(*ArticleHandler)
+
+// WithCachePolicy sets the caching headers of article reads.
+func WithCachePolicy(policy CachePolicy) ArticleHandlerOption {
+	return func(h *ArticleHandler) {
+		h.cache = policy
+	}
+}
+
+// articleETag is a strong validator of the response embedding articles: it
+// changes with an article's version or update time, with the included
+// resources, and with the tags and authors embedded.
+func articleETag(articles []*domain.Article, authors map[int64]*domain.User, inc dto.ArticleIncludes) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "include author=%t tags=%t\n", inc.Author, inc.Tags)
+	for _, a := range articles {
+		fmt.Fprintf(h, "article %d v%d %d\n", a.ID, a.Version, a.UpdatedAt.UnixNano())
+		if inc.Tags {
+			for _, tag := range a.Tags {
+				fmt.Fprintf(h, "tag %d %q\n", tag.ID, tag.Name)
+			}
+		}
+		if user, ok := authors[a.AuthorID]; ok && inc.Author {
+			fmt.Fprintf(h, "author %d %q %q\n", user.ID, user.Username, user.Profile.Nickname)
+		}
+	}
+	return `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+// surrogateKeys lists the articles and authors embedded in a response.
+func surrogateKeys(articles []*domain.Article, authors map[int64]*domain.User) []string {
+	keys := make([]string, 0, len(articles)+len(authors))
+	for _, a := range articles {
+		keys = append(keys, articleSurrogateKey(a.ID))
+	}
+	for _, id := range dto.AuthorIDs(articles...) {
+		if _, ok := authors[id]; ok {
+			keys = append(keys, userSurrogateKey(id))
+		}
+	}
+	return keys
+}
+
+// writeCacheHeaders sets the validators and caching headers of a successful
+// read. A zero lastModified omits Last-Modified.
+func (h *ArticleHandler) writeCacheHeaders(c *gin.Context, cacheControl, etag string, lastModified time.Time, keys []string) {
+	c.Header("ETag", etag)
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+	if cacheControl != "" {
+		c.Header("Cache-Control", cacheControl)
+	}
+	if h.cache.SurrogateKeyHeader != "" && len(keys) > 0 {
+		c.Header(h.cache.SurrogateKeyHeader, strings.Join(keys, " "))
+	}
+}
+
+// notModified evaluates the conditional headers of a GET request against
+// the current representation (RFC 9110, section 13.2.2): If-None-Match when
+// present, otherwise If-Modified-Since.
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if values := r.Header.Values("If-None-Match"); len(values) > 0 {
+		return etagMatches(strings.Join(values, ","), etag)
+	}
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" && !lastModified.IsZero() {
+		since, err := http.ParseTime(ims)
+		// HTTP dates have a resolution of one second.
+		return err == nil && !lastModified.Truncate(time.Second).After(since)
+	}
+	return false
+}
+
+// etagMatches reports whether an If-None-Match list contains etag, using the
+// weak comparison GET requests call for.
+func etagMatches(list, etag string) bool {
+	for _, candidate := range strings.Split(list, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
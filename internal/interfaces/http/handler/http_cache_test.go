@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/FormalYou/clean-architecture-blog/domain"
+	mock_usecase "github.com/FormalYou/clean-architecture-blog/internal/application/usecase/mocks"
+	"github.com/FormalYou/clean-architecture-blog/internal/interfaces/http/dto"
+)
+
+func TestNotModified(t *testing.T) {
+	const etag = `"abc"`
+	lastModified := time.Date(2026, 10, 19, 8, 0, 0, 500, time.UTC)
+
+	testCases := []struct {
+		name     string
+		headers  map[string]string
+		expected bool
+	}{
+		{"No Conditions", nil, false},
+		{"ETag Matches", map[string]string{"If-None-Match": `"abc"`}, true},
+		{"ETag In List", map[string]string{"If-None-Match": `"old", "abc"`}, true},
+		{"Weak ETag Matches", map[string]string{"If-None-Match": `W/"abc"`}, true},
+		{"Any ETag", map[string]string{"If-None-Match": "*"}, true},
+		{"ETag Differs", map[string]string{"If-None-Match": `"old"`}, false},
+		{"Not Modified Since", map[string]string{"If-Modified-Since": "Mon, 19 Oct 2026 08:00:00 GMT"}, true},
+		{"Modified Since", map[string]string{"If-Modified-Since": "Mon, 19 Oct 2026 07:59:59 GMT"}, false},
+		{"Invalid Date", map[string]string{"If-Modified-Since": "yesterday"}, false},
+		{"If-None-Match Takes Precedence", map[string]string{"If-None-Match": `"old"`, "If-Modified-Since": "Mon, 19 Oct 2026 08:00:00 GMT"}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/articles/1", nil)
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+			assert.Equal(t, tc.expected, notModified(req, etag, lastModified))
+		})
+	}
+
+	t.Run("No Last-Modified", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/articles", nil)
+		req.Header.Set("If-Modified-Since", "Mon, 19 Oct 2026 08:00:00 GMT")
+		assert.False(t, notModified(req, etag, time.Time{}))
+	})
+}
+
+func TestArticleETag(t *testing.T) {
+	type input struct {
+		article *domain.Article
+		authors map[int64]*domain.User
+		inc     dto.ArticleIncludes
+	}
+	newInput := func() *input {
+		return &input{
+			article: &domain.Article{ID: 1, AuthorID: 7, Version: 2, UpdatedAt: time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC), Tags: []domain.Tag{{ID: 3, Name: "go"}}},
+			authors: map[int64]*domain.User{7: {ID: 7, Username: "alice", Profile: domain.UserProfile{Nickname: "Alice"}}},
+			inc:     dto.DefaultArticleIncludes,
+		}
+	}
+	etag := func(in *input) string {
+		return articleETag([]*domain.Article{in.article}, in.authors, in.inc)
+	}
+
+	base := etag(newInput())
+	assert.Equal(t, base, etag(newInput()), "stable")
+	assert.Regexp(t, `^"[0-9a-f]{32}"$`, base)
+
+	testCases := []struct {
+		name   string
+		change func(in *input)
+	}{
+		{"Version", func(in *input) { in.article.Version++ }},
+		{"Update Time", func(in *input) { in.article.UpdatedAt = in.article.UpdatedAt.Add(time.Millisecond) }},
+		{"Tag Renamed", func(in *input) { in.article.Tags[0].Name = "golang" }},
+		{"Author Renamed", func(in *input) { in.authors[7].Profile.Nickname = "Al" }},
+		{"Author Gone", func(in *input) { delete(in.authors, 7) }},
+		{"Includes", func(in *input) { in.inc.Tags = false }},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			in := newInput()
+			tc.change(in)
+			assert.NotEqual(t, base, etag(in))
+		})
+	}
+}
+
+func TestArticleHandler_CacheHeaders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockArticleUsecase := mock_usecase.NewMockArticleUsecaseInterface(ctrl)
+	mockUserUsecase := mock_usecase.NewMockUserUsecaseInterface(ctrl)
+	handler := NewArticleHandler(mockArticleUsecase, mockUserUsecase, zap.NewNop(), WithCachePolicy(CachePolicy{
+		Article:            "public, max-age=60",
+		List:               "public, max-age=10",
+		SurrogateKeyHeader: "Surrogate-Key",
+	}))
+	router := setupArticleRouter(handler)
+
+	updated := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	articles := []*domain.Article{
+		{ID: 1, Title: "Article 1", AuthorID: testAuthor.ID, Version: 1, UpdatedAt: updated},
+		{ID: 2, Title: "Article 2", AuthorID: testAuthor.ID, Version: 4, UpdatedAt: updated},
+	}
+	authors := map[int64]*domain.User{testAuthor.ID: testAuthor}
+
+	get := func(path string, headers map[string]string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("Article", func(t *testing.T) {
+		mockArticleUsecase.EXPECT().GetArticleByID(gomock.Any(), int64(1)).Return(articles[0], nil).Times(2)
+		mockUserUsecase.EXPECT().GetUsersByIDs(gomock.Any(), []int64{testAuthor.ID}).Return(authors, nil).Times(2)
+
+		rr := get("/articles/1", nil)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		etag := rr.Header().Get("ETag")
+		assert.NotEmpty(t, etag)
+		assert.Empty(t, rr.Header().Get("Last-Modified"), "the embedded author has no update time")
+		assert.Equal(t, "public, max-age=60", rr.Header().Get("Cache-Control"))
+		assert.Equal(t, "article-1 user-7", rr.Header().Get("Surrogate-Key"))
+
+		rr = get("/articles/1", map[string]string{"If-None-Match": etag})
+		assert.Equal(t, http.StatusNotModified, rr.Code)
+		assert.Empty(t, rr.Body.String())
+		assert.Equal(t, etag, rr.Header().Get("ETag"))
+		assert.Equal(t, "public, max-age=60", rr.Header().Get("Cache-Control"))
+	})
+
+	t.Run("Article With Author Ignores If-Modified-Since", func(t *testing.T) {
+		mockArticleUsecase.EXPECT().GetArticleByID(gomock.Any(), int64(1)).Return(articles[0], nil)
+		mockUserUsecase.EXPECT().GetUsersByIDs(gomock.Any(), []int64{testAuthor.ID}).Return(authors, nil)
+
+		rr := get("/articles/1", map[string]string{"If-Modified-Since": "Mon, 19 Oct 2026 08:00:00 GMT"})
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("Article Without Author", func(t *testing.T) {
+		// Without the author embedded no user lookup is needed to answer.
+		mockArticleUsecase.EXPECT().GetArticleByID(gomock.Any(), int64(1)).Return(articles[0], nil)
+
+		rr := get("/articles/1?include=tags", map[string]string{"If-Modified-Since": "Mon, 19 Oct 2026 08:00:00 GMT"})
+		assert.Equal(t, http.StatusNotModified, rr.Code)
+		assert.Equal(t, "Mon, 19 Oct 2026 08:00:00 GMT", rr.Header().Get("Last-Modified"))
+		assert.Equal(t, "article-1", rr.Header().Get("Surrogate-Key"))
+	})
+
+	t.Run("List", func(t *testing.T) {
+		mockArticleUsecase.EXPECT().GetAllArticles(gomock.Any()).Return(articles, nil).Times(2)
+		mockUserUsecase.EXPECT().GetUsersByIDs(gomock.Any(), []int64{testAuthor.ID}).Return(authors, nil).Times(2)
+
+		rr := get("/articles", nil)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		etag := rr.Header().Get("ETag")
+		assert.NotEmpty(t, etag)
+		assert.Empty(t, rr.Header().Get("Last-Modified"))
+		assert.Equal(t, "public, max-age=10", rr.Header().Get("Cache-Control"))
+		assert.Equal(t, "articles article-1 article-2 user-7", rr.Header().Get("Surrogate-Key"))
+
+		rr = get("/articles", map[string]string{"If-None-Match": etag})
+		assert.Equal(t, http.StatusNotModified, rr.Code)
+	})
+
+	t.Run("No Policy", func(t *testing.T) {
+		mockArticleUsecase.EXPECT().GetArticleByID(gomock.Any(), int64(1)).Return(articles[0], nil)
+
+		rr := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/articles/1?include=", nil)
+		setupArticleRouter(NewArticleHandler(mockArticleUsecase, mockUserUsecase, zap.NewNop())).ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.NotEmpty(t, rr.Header().Get("ETag"))
+		assert.Empty(t, rr.Header().Get("Cache-Control"))
+		assert.Empty(t, rr.Header().Values("Surrogate-Key"))
+	})
+}
@@ -11,8 +11,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/alicebob/miniredis/v2"
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
+	"github.com/spf13/viper"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 
@@ -22,6 +24,7 @@ import (
 	"github.com/FormalYou/clean-architecture-blog/internal/infrastructure/config"
 	"github.com/FormalYou/clean-architecture-blog/internal/infrastructure/log"
 	gorm_db "github.com/FormalYou/clean-architecture-blog/internal/infrastructure/persistence/gorm"
+	"github.com/FormalYou/clean-architecture-blog/internal/infrastructure/persistence/migrations"
 	"github.com/FormalYou/clean-architecture-blog/internal/interfaces/http/handler"
 	"github.com/FormalYou/clean-architecture-blog/internal/interfaces/http/handler/middleware"
 )
@@ -47,16 +50,40 @@ func TestMain(m *testing.M) {
 	testLogger = log.GetLogger()
 
 	dbConfig := gorm_db.DSNConfig{
+		Driver:   testConfig.Database.Driver,
 		User:     testConfig.Database.User,
 		Password: testConfig.Database.Password,
 		Host:     testConfig.Database.Host,
 		Port:     testConfig.Database.Port,
 		DBName:   testConfig.Database.DBName,
+		SSLMode:  testConfig.Database.SSLMode,
 	}
+
+	// By default the suite runs against in-memory SQLite and an in-process
+	// Redis. Set INTEGRATION_EXTERNAL=1 to use the services from the config.
+	var mr *miniredis.Miniredis
+	if os.Getenv("INTEGRATION_EXTERNAL") == "" {
+		dbConfig = gorm_db.DSNConfig{Driver: gorm_db.DriverSQLite, DBName: ":memory:"}
+
+		mr, err = miniredis.Run()
+		if err != nil {
+			panic("failed to start miniredis: " + err.Error())
+		}
+		viper.Set("redis.addr", mr.Addr())
+		viper.Set("redis.password", "")
+	}
+
 	testDB, err = gorm_db.NewDB(dbConfig)
 	if err != nil {
 		panic("failed to connect database: " + err.Error())
 	}
+	migrator, err := migrations.New(testDB, dbConfig.Driver)
+	if err != nil {
+		panic("failed to load migrations: " + err.Error())
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		panic("failed to migrate database: " + err.Error())
+	}
 
 	redisClient, err = cache.NewRedisClient()
 	if err != nil {
@@ -68,6 +95,9 @@ func TestMain(m *testing.M) {
 	code := m.Run()
 
 	CleanUp()
+	if mr != nil {
+		mr.Close()
+	}
 
 	os.Exit(code)
 }
@@ -86,10 +116,10 @@ func SetupRouter(cfg *config.Config, db *gorm.DB, rds *redis.Client, logger *zap
 	loggerAdapter := log.NewZapAdapter(logger)
 	// auditSvc := usecase.NewAuditService(loggerAdapter)
 	userUsecase := usecase.NewUserUsecase(userRepo, authService, time.Duration(cfg.JWT.ExpiresInMinutes)*time.Minute, loggerAdapter /*, auditSvc*/)
-	articleUsecase := usecase.NewArticleUsecase(articleRepo, articleCacheRepo, authService, loggerAdapter)
+	articleUsecase := usecase.NewArticleUsecase(articleRepo, articleCacheRepo, gorm_db.NewTxManager(db, cfg.Database.TxMaxRetries), authService, loggerAdapter)
 
 	userHandler := handler.NewUserHandler(userUsecase, logger)
-	articleHandler := handler.NewArticleHandler(articleUsecase, logger)
+	articleHandler := handler.NewArticleHandler(articleUsecase, userUsecase, logger)
 
 	userRoutes := router.Group("/users")
 	{
@@ -112,7 +142,7 @@ func SetupRouter(cfg *config.Config, db *gorm.DB, rds *redis.Client, logger *zap
 }
 
 func CleanUp() {
-	testDB.Exec("DELETE FROM comments")
+	testDB.Exec("DELETE FROM comment_models")
 	testDB.Exec("DELETE FROM article_tags")
 	testDB.Exec("DELETE FROM tag_models")
 	testDB.Exec("DELETE FROM article_models")
@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/FormalYou/clean-architecture-blog/internal/errorx"
+)
+
+// MetaHandler describes the API itself, such as the error codes it returns.
+type MetaHandler struct{}
+
+// NewMetaHandler creates a new MetaHandler.
+func NewMetaHandler() *MetaHandler {
+	return &MetaHandler{}
+}
+
+// Errors lists every registered error code.
+func (h *MetaHandler) Errors(c *gin.Context) {
+	c.JSON(http.StatusOK, errorx.Catalogue())
+}
+
+// Error describes one error code. Problem documents link here from their type.
+func (h *MetaHandler) Error(c *gin.Context) {
+	code, err := strconv.Atoi(c.Param("code"))
+	if err != nil {
+		c.Error(errorx.New(errorx.CodeInvalidParams, err))
+		return
+	}
+
+	entry, ok := errorx.Describe(code)
+	if !ok {
+		c.Error(errorx.New(errorx.CodeNotFound, fmt.Errorf("error code %d is not registered", code)))
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
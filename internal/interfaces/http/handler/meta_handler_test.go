@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/FormalYou/clean-architecture-blog/internal/application/usecase/articleerr"
+	"github.com/FormalYou/clean-architecture-blog/internal/errorx"
+	"github.com/FormalYou/clean-architecture-blog/internal/interfaces/http/handler/middleware"
+)
+
+func TestMetaHandler_Errors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.ErrorHandler(zap.NewNop()))
+	metaHandler := NewMetaHandler()
+	router.GET("/meta/errors", metaHandler.Errors)
+	router.GET("/meta/errors/:code", metaHandler.Error)
+
+	t.Run("Catalogue", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/meta/errors", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var entries []errorx.Entry
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &entries))
+		assert.Equal(t, errorx.Catalogue(), entries)
+	})
+
+	testCases := []struct {
+		name         string
+		code         string
+		expectedCode int
+		expectedBody int
+	}{
+		{"Registered code", "30001", http.StatusOK, articleerr.CodeArticleNotFound},
+		{"Unregistered code", "99999", http.StatusNotFound, errorx.CodeNotFound},
+		{"Invalid code", "abc", http.StatusBadRequest, errorx.CodeInvalidParams},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/meta/errors/"+tc.code, nil))
+
+			assert.Equal(t, tc.expectedCode, w.Code)
+			var body struct {
+				Code int `json:"code"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, tc.expectedBody, body.Code)
+		})
+	}
+}
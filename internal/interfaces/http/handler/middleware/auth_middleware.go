@@ -1,12 +1,13 @@
 package middleware
 
 import (
-	"context"
-	"net/http"
+	"errors"
 	"strings"
 
+	"github.com/FormalYou/clean-architecture-blog/domain"
 	"github.com/FormalYou/clean-architecture-blog/internal/application/contracts"
-	"github.com/FormalYou/clean-architecture-blog/internal/interfaces/http/dto"
+	"github.com/FormalYou/clean-architecture-blog/internal/errorx"
+	"github.com/FormalYou/clean-architecture-blog/internal/interfaces/shared"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
@@ -17,27 +18,51 @@ func AuthMiddleware(authSvc contracts.AuthService, logger *zap.Logger) gin.Handl
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
 			logger.Warn("authorization header is missing")
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "authorization header is required"})
+			abortUnauthorized(c, domain.ReasonRequired, "Authorization is required")
 			return
 		}
 
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
 			logger.Warn("invalid token format")
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token format"})
+			abortUnauthorized(c, domain.ReasonInvalidFormat, "Authorization must be a Bearer token")
 			return
 		}
 
 		userID, err := authSvc.ValidateToken(parts[1])
 		if err != nil {
 			logger.Warn("invalid token", zap.Error(err))
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+			abortUnauthorized(c, errorx.ReasonInvalidToken, "Authorization carries an invalid or expired token")
 			return
 		}
 
 		logger.Info("user authenticated", zap.Int64("user_id", userID))
-		ctx := context.WithValue(c.Request.Context(), dto.UserIDKey, userID)
+		ctx := shared.WithUserID(c.Request.Context(), userID)
 		c.Request = c.Request.WithContext(ctx)
 		c.Next()
 	}
 }
+
+// OptionalAuthMiddleware authenticates requests carrying an Authorization
+// header like AuthMiddleware and lets requests without one through
+// anonymously, for endpoints serving both such as /graphql. An invalid
+// header is still rejected.
+func OptionalAuthMiddleware(authSvc contracts.AuthService, logger *zap.Logger) gin.HandlerFunc {
+	required := AuthMiddleware(authSvc, logger)
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.Next()
+			return
+		}
+		required(c)
+	}
+}
+
+// abortUnauthorized stops the request with CodeUnauthorized. The specific
+// reason is a detail on the Authorization header, so it is translated like
+// the message.
+func abortUnauthorized(c *gin.Context, reason, message string) {
+	_ = c.Error(errorx.New(errorx.CodeUnauthorized, errors.New(message)).
+		WithDetails(errorx.Detail{Field: "Authorization", Reason: reason, Message: message}))
+	c.Abort()
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/FormalYou/clean-architecture-blog/domain"
+	"github.com/FormalYou/clean-architecture-blog/internal/errorx"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+var jsonFieldNames sync.Once
+
+// useJSONFieldNames makes the binding validator name fields after their
+// json tags, so reported paths match the request body. It has to run before
+// the first request is bound.
+func useJSONFieldNames() {
+	jsonFieldNames.Do(func() {
+		v, ok := binding.Validator.Engine().(*validator.Validate)
+		if !ok {
+			return
+		}
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "" || name == "-" {
+				return field.Name
+			}
+			return name
+		})
+	})
+}
+
+// bindingDetails describes each problem with a request body rejected by
+// ShouldBindJSON, in the same shape as domain validation errors.
+func bindingDetails(err error) []errorx.Detail {
+	var details []errorx.Detail
+
+	var fieldErrs validator.ValidationErrors
+	if errors.As(err, &fieldErrs) {
+		for _, fe := range fieldErrs {
+			// The namespace starts with the request type's name.
+			_, field, _ := strings.Cut(fe.Namespace(), ".")
+			details = append(details, fieldDetail(field, fe.Tag()))
+		}
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		details = append(details, errorx.Detail{
+			Field:   typeErr.Field,
+			Reason:  "invalid_type",
+			Message: fmt.Sprintf("%s must be of type %s", typeErr.Field, typeErr.Type),
+		})
+	}
+	return details
+}
+
+func fieldDetail(field, tag string) errorx.Detail {
+	switch tag {
+	case "required":
+		return errorx.Detail{Field: field, Reason: domain.ReasonRequired, Message: field + " is required"}
+	case "email":
+		return errorx.Detail{Field: field, Reason: domain.ReasonInvalidFormat, Message: field + " must be a valid email address"}
+	default:
+		return errorx.Detail{Field: field, Reason: tag, Message: fmt.Sprintf("%s failed the %q rule", field, tag)}
+	}
+}
@@ -2,15 +2,23 @@ package middleware
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/FormalYou/clean-architecture-blog/internal/errorx"
+	zaplog "github.com/FormalYou/clean-architecture-blog/internal/infrastructure/log"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
 // ErrorHandler is a middleware to handle errors gracefully.
+// Request binding errors are broken down into per-field details. Errors are
+// written as {code,message,details}, or as an RFC 7807 problem document when
+// the Accept header prefers application/problem+json, in the language
+// negotiated from Accept-Language.
 func ErrorHandler(logger *zap.Logger) gin.HandlerFunc {
+	useJSONFieldNames()
 	return func(c *gin.Context) {
 		c.Next()
 
@@ -31,6 +39,7 @@ func ErrorHandler(logger *zap.Logger) gin.HandlerFunc {
 				zap.String("message", detailErr.Message),
 				zap.String("request_uri", c.Request.RequestURI),
 			}
+			logFields = append(logFields, zaplog.TraceFields(c.Request.Context())...)
 
 			switch detailErr.LogLevel {
 			case zapcore.WarnLevel:
@@ -41,19 +50,55 @@ func ErrorHandler(logger *zap.Logger) gin.HandlerFunc {
 				logger.Error(detailErr.Error(), logFields...)
 			}
 
+			if len(detailErr.Details) == 0 {
+				detailErr.Details = bindingDetails(detailErr.Err)
+			}
+
 			// Respond with the user-facing business error
-			c.JSON(detailErr.HTTPStatus, detailErr.BusinessError)
+			render(c, detailErr)
 			return
 		}
 
 		// For all other unexpected errors, log them and return a generic 500 error
-		logger.Error(
-			err.Error(),
-			zap.String("request_uri", c.Request.RequestURI),
-		)
+		logFields := []zap.Field{zap.String("request_uri", c.Request.RequestURI)}
+		logFields = append(logFields, zaplog.TraceFields(c.Request.Context())...)
+		logger.Error(err.Error(), logFields...)
 
 		// Respond with a generic internal server error
-		internalErr := errorx.New(errorx.CodeInternalServerError, nil)
-		c.JSON(internalErr.HTTPStatus, internalErr.BusinessError)
+		render(c, errorx.New(errorx.CodeInternalServerError, nil))
+	}
+}
+
+// render writes err in the format and language negotiated with the client.
+// Nothing is written if the handler already started the response.
+func render(c *gin.Context, err *errorx.DetailError) {
+	if c.Writer.Written() {
+		return
+	}
+	locale := errorx.NegotiateLocale(c.GetHeader("Accept-Language"))
+	err = err.Localize(locale)
+	c.Header("Content-Language", locale)
+	if c.NegotiateFormat(binding.MIMEJSON, errorx.ProblemContentType) != errorx.ProblemContentType {
+		c.JSON(err.HTTPStatus, err.BusinessError)
+		return
+	}
+	c.Header("Content-Type", errorx.ProblemContentType)
+	c.JSON(err.HTTPStatus, err.Problem(c.Request.URL.Path, zaplog.RequestID(c.Request.Context())))
+}
+
+// NoRoute reports requests for unknown paths as CodeNotFound.
+func NoRoute() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_ = c.Error(errorx.New(errorx.CodeNotFound, fmt.Errorf("no route for %s", c.Request.URL.Path)))
+	}
+}
+
+// NoMethod reports requests with a method the path does not support as
+// CodeMethodNotAllowed. The engine must have HandleMethodNotAllowed set.
+func NoMethod() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_ = c.Error(errorx.New(errorx.CodeMethodNotAllowed,
+			fmt.Errorf("method %s is not allowed for %s", c.Request.Method, c.Request.URL.Path)).
+			WithParams(map[string]string{"method": c.Request.Method}))
 	}
 }
@@ -0,0 +1,195 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/FormalYou/clean-architecture-blog/internal/application/usecase/articleerr"
+	"github.com/FormalYou/clean-architecture-blog/internal/errorx"
+	"github.com/FormalYou/clean-architecture-blog/internal/infrastructure/auth"
+)
+
+func setupErrorRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID(), ErrorHandler(zap.NewNop()), Recovery(zap.NewNop()))
+	router.HandleMethodNotAllowed = true
+	router.NoRoute(NoRoute())
+	router.NoMethod(NoMethod())
+
+	router.GET("/articles/:id", func(c *gin.Context) {
+		_ = c.Error(errorx.New(articleerr.CodeArticleNotFound, errors.New("article 7 does not exist")))
+	})
+	router.GET("/boom", func(c *gin.Context) { panic("boom") })
+	router.GET("/db", func(c *gin.Context) { _ = c.Error(errors.New("dial tcp: connection refused")) })
+	router.POST("/private", AuthMiddleware(auth.NewJWTAuthService(testSecret), zap.NewNop()), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	return router
+}
+
+func TestErrorHandler_Formats(t *testing.T) {
+	router := setupErrorRouter()
+
+	testCases := []struct {
+		name            string
+		method, path    string
+		header          map[string]string
+		expectedStatus  int
+		expectedType    string
+		expectedBody    string
+		expectRequestID bool
+	}{
+		{
+			name:   "Business Error As JSON",
+			method: http.MethodGet, path: "/articles/7",
+			expectedStatus: http.StatusNotFound,
+			expectedType:   "application/json; charset=utf-8",
+			expectedBody:   `{"code":30001,"message":"Article not found"}`,
+		},
+		{
+			name:   "Business Error As Problem",
+			method: http.MethodGet, path: "/articles/7",
+			header:         map[string]string{"Accept": "application/problem+json", RequestIDHeader: "req-1"},
+			expectedStatus: http.StatusNotFound,
+			expectedType:   errorx.ProblemContentType,
+			expectedBody: `{"type":"/api/v1/meta/errors/30001","title":"Article not found","status":404,
+				"detail":"article 7 does not exist","instance":"/articles/7","request_id":"req-1","code":30001}`,
+		},
+		{
+			name:   "JSON Preferred Over Problem",
+			method: http.MethodGet, path: "/articles/7",
+			header:         map[string]string{"Accept": "application/json, application/problem+json"},
+			expectedStatus: http.StatusNotFound,
+			expectedType:   "application/json; charset=utf-8",
+			expectedBody:   `{"code":30001,"message":"Article not found"}`,
+		},
+		{
+			name:   "Localized",
+			method: http.MethodGet, path: "/articles/7",
+			header:         map[string]string{"Accept-Language": "zh-CN,zh;q=0.9,en;q=0.8"},
+			expectedStatus: http.StatusNotFound,
+			expectedType:   "application/json; charset=utf-8",
+			expectedBody:   `{"code":30001,"message":"文章不存在"}`,
+		},
+		{
+			name:   "Localized Problem With Parameters",
+			method: http.MethodDelete, path: "/boom",
+			header:          map[string]string{"Accept": "application/problem+json", "Accept-Language": "zh-TW"},
+			expectedStatus:  http.StatusMethodNotAllowed,
+			expectedType:    errorx.ProblemContentType,
+			expectedBody:    `{"type":"/api/v1/meta/errors/10009","title":"不支援 DELETE 方法","status":405,"detail":"method DELETE is not allowed for /boom","instance":"/boom","code":10009}`,
+			expectRequestID: true,
+		},
+		{
+			name:   "Auth Failure",
+			method: http.MethodPost, path: "/private",
+			expectedStatus: http.StatusUnauthorized,
+			expectedType:   "application/json; charset=utf-8",
+			expectedBody:   `{"code":10003,"message":"Unauthorized","details":[{"field":"Authorization","reason":"required","message":"Authorization is required"}]}`,
+		},
+		{
+			name:   "Auth Failure In Chinese",
+			method: http.MethodPost, path: "/private",
+			header:         map[string]string{"Accept-Language": "zh-CN", "Authorization": "Bearer nope"},
+			expectedStatus: http.StatusUnauthorized,
+			expectedType:   "application/json; charset=utf-8",
+			expectedBody:   `{"code":10003,"message":"未授权","details":[{"field":"Authorization","reason":"invalid_token","message":"Authorization 中的令牌无效或已过期"}]}`,
+		},
+		{
+			name:   "Auth Failure As Problem",
+			method: http.MethodPost, path: "/private",
+			header:          map[string]string{"Accept": "application/problem+json", "Authorization": "Bearer nope"},
+			expectedStatus:  http.StatusUnauthorized,
+			expectedType:    errorx.ProblemContentType,
+			expectedBody:    `{"type":"/api/v1/meta/errors/10003","title":"Unauthorized","status":401,"detail":"Authorization carries an invalid or expired token","instance":"/private","code":10003,"details":[{"field":"Authorization","reason":"invalid_token","message":"Authorization carries an invalid or expired token"}]}`,
+			expectRequestID: true,
+		},
+		{
+			name:   "Unknown Route",
+			method: http.MethodGet, path: "/nope",
+			expectedStatus: http.StatusNotFound,
+			expectedType:   "application/json; charset=utf-8",
+			expectedBody:   `{"code":10004,"message":"Resource Not Found"}`,
+		},
+		{
+			name:   "Wrong Method",
+			method: http.MethodDelete, path: "/boom",
+			header:          map[string]string{"Accept": "application/problem+json"},
+			expectedStatus:  http.StatusMethodNotAllowed,
+			expectedType:    errorx.ProblemContentType,
+			expectedBody:    `{"type":"/api/v1/meta/errors/10009","title":"Method DELETE Not Allowed","status":405,"detail":"method DELETE is not allowed for /boom","instance":"/boom","code":10009}`,
+			expectRequestID: true,
+		},
+		{
+			name:   "Panic",
+			method: http.MethodGet, path: "/boom",
+			header:          map[string]string{"Accept": "application/problem+json"},
+			expectedStatus:  http.StatusInternalServerError,
+			expectedType:    errorx.ProblemContentType,
+			expectedBody:    `{"type":"/api/v1/meta/errors/10001","title":"Internal Server Error","status":500,"detail":"Internal Server Error","instance":"/boom","code":10001}`,
+			expectRequestID: true,
+		},
+		{
+			name:   "Unexpected Error Hides Cause",
+			method: http.MethodGet, path: "/db",
+			expectedStatus: http.StatusInternalServerError,
+			expectedType:   "application/json; charset=utf-8",
+			expectedBody:   `{"code":10001,"message":"Internal Server Error"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			for k, v := range tc.header {
+				req.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Equal(t, tc.expectedType, w.Header().Get("Content-Type"))
+			requestID := w.Header().Get(RequestIDHeader)
+			require.NotEmpty(t, requestID)
+
+			body := map[string]interface{}{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			if tc.expectRequestID {
+				assert.Equal(t, requestID, body["request_id"])
+				delete(body, "request_id")
+			}
+			actual, _ := json.Marshal(body)
+			assert.JSONEq(t, tc.expectedBody, string(actual))
+		})
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	router := setupErrorRouter()
+
+	for header, reused := range map[string]bool{
+		"req-42":                  true,
+		"has space":               false,
+		string(make([]byte, 129)): false,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/nope", nil)
+		req.Header.Set(RequestIDHeader, header)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if reused {
+			assert.Equal(t, header, w.Header().Get(RequestIDHeader))
+		} else {
+			assert.Len(t, w.Header().Get(RequestIDHeader), 32)
+		}
+	}
+}
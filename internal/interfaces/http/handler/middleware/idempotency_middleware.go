@@ -0,0 +1,152 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/FormalYou/clean-architecture-blog/internal/application/repository"
+	"github.com/FormalYou/clean-architecture-blog/internal/errorx"
+	"github.com/FormalYou/clean-architecture-blog/internal/interfaces/shared"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// IdempotencyKeyHeader carries the client's key for a retryable request.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// maxIdempotencyKeyLength bounds the key so it cannot bloat the store.
+const maxIdempotencyKeyLength = 255
+
+// Idempotency creates a Gin middleware that makes requests carrying an
+// Idempotency-Key header safe to retry. The key is scoped to the
+// authenticated user, or to the client IP on public routes. The first
+// successful response is stored and replayed, marked with
+// Idempotent-Replayed, for every retry with the same body; a retry while the
+// first request is still running gets CodeRequestInProgress and reusing the
+// key for a different body gets CodeIdempotencyMismatch. Failed requests are
+// not stored, so the client can retry them with the same key. Requests
+// without the header, and all requests while the store is unavailable, are
+// handled normally.
+func Idempotency(store repository.IdempotencyStore, opts shared.IdempotencyOptions, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			_ = c.Error(errorx.New(errorx.CodeInvalidParams,
+				fmt.Errorf("%s must be at most %d characters", IdempotencyKeyHeader, maxIdempotencyKeyLength)))
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			_ = c.Error(errorx.New(errorx.CodeInvalidParams, err))
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		key = shared.IdempotencyScope(c.Request.Context(), c.ClientIP()) + ":" + key
+		fingerprint := requestFingerprint(c.Request.Method, c.FullPath(), body)
+
+		existing, claim, err := store.Begin(ctx, key, fingerprint, opts.LockTTL)
+		if err != nil {
+			logger.Warn("idempotency store unavailable, handling request without it", zap.Error(err))
+			c.Next()
+			return
+		}
+		if claim == "" {
+			replay(c, existing, fingerprint)
+			return
+		}
+
+		// The key is released unless a response gets stored, including when
+		// the handler panics.
+		completed := false
+		detached := context.WithoutCancel(ctx)
+		defer func() {
+			if !completed {
+				if err := store.Release(detached, key, claim); err != nil {
+					logger.Warn("could not release idempotency key", zap.Error(err))
+				}
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+		c.Writer = recorder.ResponseWriter
+
+		status := c.Writer.Status()
+		if len(c.Errors) > 0 || !c.Writer.Written() || status >= http.StatusInternalServerError {
+			return
+		}
+		resp := repository.IdempotentResponse{
+			Status:      status,
+			ContentType: c.Writer.Header().Get("Content-Type"),
+			Body:        recorder.body.Bytes(),
+		}
+		err = store.Complete(detached, key, claim, fingerprint, resp, opts.TTL)
+		if errors.Is(err, repository.ErrIdempotencyClaimLost) {
+			// The lock expired before the handler finished and a retry owns
+			// the key now; its response is the one that gets kept.
+			logger.Warn("idempotency key expired while handling request, response not stored", zap.Duration("lock_ttl", opts.LockTTL))
+			return
+		}
+		if err != nil {
+			logger.Warn("could not store idempotent response", zap.Error(err))
+			return
+		}
+		completed = true
+	}
+}
+
+// replay answers a request whose key was claimed before.
+func replay(c *gin.Context, existing *repository.IdempotencyRecord, fingerprint string) {
+	switch {
+	case existing.Fingerprint != fingerprint:
+		_ = c.Error(errorx.New(errorx.CodeIdempotencyMismatch, errors.New("idempotency key reused with a different request")))
+		c.Abort()
+	case existing.Response == nil:
+		_ = c.Error(errorx.New(errorx.CodeRequestInProgress, errors.New("idempotency key is still in use")))
+		c.Abort()
+	default:
+		c.Header("Idempotent-Replayed", "true")
+		c.Data(existing.Response.Status, existing.Response.ContentType, existing.Response.Body)
+		c.Abort()
+	}
+}
+
+// requestFingerprint identifies the route and body a key was first used with.
+func requestFingerprint(method, route string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + route + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// responseRecorder keeps a copy of the response body while writing it.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,159 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/FormalYou/clean-architecture-blog/internal/application/repository"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/repository/mocks"
+	"github.com/FormalYou/clean-architecture-blog/internal/errorx"
+	"github.com/FormalYou/clean-architecture-blog/internal/infrastructure/auth"
+	"github.com/FormalYou/clean-architecture-blog/internal/infrastructure/cache"
+	"github.com/FormalYou/clean-architecture-blog/internal/interfaces/shared"
+)
+
+var testIdempotencyOptions = shared.IdempotencyOptions{TTL: time.Hour, LockTTL: time.Minute}
+
+func postJSON(router http.Handler, path, key, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	authSvc := auth.NewJWTAuthService(testSecret)
+	var created atomic.Int64
+	release := make(chan struct{})
+
+	router := gin.New()
+	router.Use(ErrorHandler(zap.NewNop()))
+	router.POST("/articles", AuthMiddleware(authSvc, zap.NewNop()), Idempotency(cache.NewMemoryIdempotencyStore(), testIdempotencyOptions, zap.NewNop()), func(c *gin.Context) {
+		var req struct {
+			Title string `json:"title" binding:"required"`
+			Slow  bool   `json:"slow"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			_ = c.Error(errorx.New(errorx.CodeInvalidParams, err))
+			return
+		}
+		if req.Slow {
+			<-release
+		}
+		c.JSON(http.StatusCreated, gin.H{"id": created.Add(1), "title": req.Title})
+	})
+
+	alice, bob := signToken(t, jwt.MapClaims{"sub": 1}), signToken(t, jwt.MapClaims{"sub": 2})
+
+	t.Run("Replays the first response", func(t *testing.T) {
+		first := postJSON(router, "/articles", "k1", alice, `{"title":"a"}`)
+		require.Equal(t, http.StatusCreated, first.Code)
+
+		retry := postJSON(router, "/articles", "k1", alice, `{"title":"a"}`)
+		assert.Equal(t, http.StatusCreated, retry.Code)
+		assert.Equal(t, first.Body.String(), retry.Body.String())
+		assert.Equal(t, "true", retry.Header().Get("Idempotent-Replayed"))
+		assert.Equal(t, first.Header().Get("Content-Type"), retry.Header().Get("Content-Type"))
+		assert.Equal(t, int64(1), created.Load())
+	})
+
+	t.Run("Keys are scoped to the user", func(t *testing.T) {
+		w := postJSON(router, "/articles", "k1", bob, `{"title":"a"}`)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Empty(t, w.Header().Get("Idempotent-Replayed"))
+	})
+
+	t.Run("Rejects a different body", func(t *testing.T) {
+		w := postJSON(router, "/articles", "k1", alice, `{"title":"b"}`)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.JSONEq(t, errorx.New(errorx.CodeIdempotencyMismatch, nil).ToJSON(), w.Body.String())
+	})
+
+	t.Run("Rejects duplicates while the first is running", func(t *testing.T) {
+		done := make(chan *httptest.ResponseRecorder)
+		go func() { done <- postJSON(router, "/articles", "k2", alice, `{"title":"c","slow":true}`) }()
+
+		var w *httptest.ResponseRecorder
+		require.Eventually(t, func() bool {
+			w = postJSON(router, "/articles", "k2", alice, `{"title":"c","slow":true}`)
+			return w.Code == http.StatusConflict
+		}, time.Second, 5*time.Millisecond)
+		assert.JSONEq(t, errorx.New(errorx.CodeRequestInProgress, nil).ToJSON(), w.Body.String())
+
+		close(release)
+		assert.Equal(t, http.StatusCreated, (<-done).Code)
+		assert.Equal(t, http.StatusCreated, postJSON(router, "/articles", "k2", alice, `{"title":"c","slow":true}`).Code)
+	})
+
+	t.Run("Failed requests can be retried", func(t *testing.T) {
+		w := postJSON(router, "/articles", "k3", alice, `{}`)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+
+		before := created.Load()
+		w = postJSON(router, "/articles", "k3", alice, `{"title":"d"}`)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, before+1, created.Load())
+	})
+
+	t.Run("Requests without a key are not deduplicated", func(t *testing.T) {
+		before := created.Load()
+		postJSON(router, "/articles", "", alice, `{"title":"e"}`)
+		postJSON(router, "/articles", "", alice, `{"title":"e"}`)
+		assert.Equal(t, before+2, created.Load())
+	})
+
+	t.Run("Rejects oversized keys", func(t *testing.T) {
+		w := postJSON(router, "/articles", strings.Repeat("k", maxIdempotencyKeyLength+1), alice, `{"title":"f"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestIdempotency_StoreFailureHandlesRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mocks.NewMockIdempotencyStore(ctrl)
+	store.EXPECT().Begin(gomock.Any(), "ip:192.0.2.1:k", gomock.Any(), time.Minute).Return(nil, "", errors.New("redis down"))
+
+	router := gin.New()
+	router.POST("/register", Idempotency(store, testIdempotencyOptions, zap.NewNop()), func(c *gin.Context) {
+		c.Status(http.StatusCreated)
+	})
+
+	w := postJSON(router, "/register", "k", "", `{}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+}
+
+func TestIdempotency_ExpiredClaimKeepsRetryResponse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mocks.NewMockIdempotencyStore(ctrl)
+	store.EXPECT().Begin(gomock.Any(), "ip:192.0.2.1:k", gomock.Any(), time.Minute).Return(nil, "c1", nil)
+	store.EXPECT().Complete(gomock.Any(), "ip:192.0.2.1:k", "c1", gomock.Any(), gomock.Any(), time.Hour).Return(repository.ErrIdempotencyClaimLost)
+	store.EXPECT().Release(gomock.Any(), "ip:192.0.2.1:k", "c1").Return(nil)
+
+	router := gin.New()
+	router.POST("/register", Idempotency(store, testIdempotencyOptions, zap.NewNop()), func(c *gin.Context) {
+		c.JSON(http.StatusCreated, gin.H{"id": 1})
+	})
+
+	w := postJSON(router, "/register", "k", "", `{}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+}
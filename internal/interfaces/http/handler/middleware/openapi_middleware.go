@@ -0,0 +1,278 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/FormalYou/clean-architecture-blog/domain"
+	"github.com/FormalYou/clean-architecture-blog/internal/errorx"
+	zaplog "github.com/FormalYou/clean-architecture-blog/internal/infrastructure/log"
+)
+
+// OpenAPISpec is a parsed OpenAPI document whose operations are looked up by
+// the Gin route that handles a request.
+type OpenAPISpec struct {
+	doc      *openapi3.T
+	basePath string // path of the first server URL, e.g. /api/v1
+}
+
+// NewOpenAPISpec parses and validates an OpenAPI document.
+func NewOpenAPISpec(data []byte) (*OpenAPISpec, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(data)
+	if err != nil {
+		return nil, fmt.Errorf("openapi: %w", err)
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("openapi: %w", err)
+	}
+
+	spec := &OpenAPISpec{doc: doc}
+	if len(doc.Servers) > 0 {
+		u, err := url.Parse(doc.Servers[0].URL)
+		if err != nil {
+			return nil, fmt.Errorf("openapi: server url: %w", err)
+		}
+		spec.basePath = strings.TrimSuffix(u.Path, "/")
+	}
+	return spec, nil
+}
+
+// Routes lists the documented operations as Gin routes, e.g.
+// "GET /api/v1/articles/:id", in lexical order.
+func (s *OpenAPISpec) Routes() []string {
+	var routes []string
+	for path, item := range s.doc.Paths.Map() {
+		ginPath := s.basePath + path
+		for _, param := range pathParams(path) {
+			ginPath = strings.Replace(ginPath, "{"+param+"}", ":"+param, 1)
+		}
+		for method := range item.Operations() {
+			routes = append(routes, method+" "+ginPath)
+		}
+	}
+	sort.Strings(routes)
+	return routes
+}
+
+// route returns the documented operation behind the Gin route fullPath, or
+// nil if the route is not part of the spec.
+func (s *OpenAPISpec) route(method, fullPath string) *routers.Route {
+	path, ok := strings.CutPrefix(fullPath, s.basePath)
+	if !ok {
+		return nil
+	}
+	// Gin joins a group's trailing slash into its root route, e.g. /articles/.
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+	segments := strings.Split(path, "/")
+	for i, seg := range segments {
+		if strings.HasPrefix(seg, ":") || strings.HasPrefix(seg, "*") {
+			segments[i] = "{" + seg[1:] + "}"
+		}
+	}
+	path = strings.Join(segments, "/")
+
+	item := s.doc.Paths.Value(path)
+	if item == nil || item.GetOperation(method) == nil {
+		return nil
+	}
+	return &routers.Route{
+		Spec:      s.doc,
+		Path:      path,
+		PathItem:  item,
+		Method:    method,
+		Operation: item.GetOperation(method),
+	}
+}
+
+// pathParams lists the {name} parameters of an OpenAPI path template.
+func pathParams(path string) []string {
+	var params []string
+	for _, seg := range strings.Split(path, "/") {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			params = append(params, seg[1:len(seg)-1])
+		}
+	}
+	return params
+}
+
+// input prepares the validation of the current request, or returns nil when
+// the route is not documented.
+func (s *OpenAPISpec) input(c *gin.Context) *openapi3filter.RequestValidationInput {
+	route := s.route(c.Request.Method, c.FullPath())
+	if route == nil {
+		return nil
+	}
+	params := make(map[string]string, len(c.Params))
+	for _, p := range c.Params {
+		params[p.Key] = p.Value
+	}
+	return &openapi3filter.RequestValidationInput{
+		Request:    c.Request,
+		PathParams: params,
+		Route:      route,
+		Options: &openapi3filter.Options{
+			MultiError:         true,
+			AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+		},
+	}
+}
+
+// ValidateRequests rejects requests whose parameters or body do not match
+// the spec with CodeInvalidParams, describing every violation in the
+// error's details. Routes missing from the spec are not checked.
+func ValidateRequests(spec *OpenAPISpec) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		input := spec.input(c)
+		if input == nil {
+			c.Next()
+			return
+		}
+		if err := openapi3filter.ValidateRequest(c.Request.Context(), input); err != nil {
+			c.Error(errorx.New(errorx.CodeInvalidParams, err).WithDetails(openAPIDetails(err)...))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// ValidateResponses replaces responses that do not match the spec, including
+// those with an undocumented status, with an internal server error naming
+// the mismatch. It buffers every response of a
+// documented route, so it is meant for tests; it must run before
+// ErrorHandler to see the error responses too.
+func ValidateResponses(spec *OpenAPISpec, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		input := spec.input(c)
+		if input == nil {
+			c.Next()
+			return
+		}
+
+		w := &bufferedWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		err := openapi3filter.ValidateResponse(c.Request.Context(), (&openapi3filter.ResponseValidationInput{
+			RequestValidationInput: input,
+			Status:                 w.Status(),
+			Header:                 w.Header(),
+			Options: &openapi3filter.Options{
+				MultiError:            true,
+				IncludeResponseStatus: true,
+			},
+		}).SetBodyBytes(w.body.Bytes()))
+		if err == nil {
+			w.flush()
+			return
+		}
+
+		logFields := append([]zap.Field{zap.String("request_uri", c.Request.RequestURI)}, zaplog.TraceFields(c.Request.Context())...)
+		logger.Error("response does not match the OpenAPI spec", append(logFields, zap.Error(err))...)
+		render(c, errorx.New(errorx.CodeInternalServerError, err).
+			WithMessage("response does not match the OpenAPI spec: "+err.Error()))
+	}
+}
+
+// bufferedWriter holds back the response body until it has been validated.
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+func (w *bufferedWriter) Written() bool {
+	return w.body.Len() > 0 || w.ResponseWriter.Written()
+}
+
+func (w *bufferedWriter) Size() int {
+	return w.body.Len()
+}
+
+func (w *bufferedWriter) flush() {
+	if w.body.Len() == 0 {
+		w.ResponseWriter.WriteHeaderNow()
+		return
+	}
+	_, _ = w.ResponseWriter.Write(w.body.Bytes())
+}
+
+// openAPIDetails describes each violation reported by the request
+// validator, in the same shape as binding errors.
+func openAPIDetails(err error) []errorx.Detail {
+	var details []errorx.Detail
+	var walk func(err error, field string)
+	walk = func(err error, field string) {
+		switch e := err.(type) {
+		case openapi3.MultiError:
+			for _, inner := range e {
+				walk(inner, field)
+			}
+		case *openapi3filter.RequestError:
+			if e.Parameter != nil {
+				field = e.Parameter.Name
+			}
+			if e.Err != nil {
+				walk(e.Err, field)
+				return
+			}
+			details = append(details, errorx.Detail{Field: field, Reason: domain.ReasonInvalidFormat, Message: e.Reason})
+		case *openapi3.SchemaError:
+			if pointer := e.JSONPointer(); len(pointer) > 0 {
+				field = fieldPath(pointer)
+			}
+			details = append(details, schemaDetail(field, e))
+		default:
+			details = append(details, errorx.Detail{Field: field, Reason: domain.ReasonInvalidFormat, Message: err.Error()})
+		}
+	}
+	walk(err, "")
+	return details
+}
+
+// fieldPath turns a JSON pointer into a dotted field path, e.g. tags[0].
+func fieldPath(pointer []string) string {
+	var b strings.Builder
+	for _, seg := range pointer {
+		if seg != "" && strings.Trim(seg, "0123456789") == "" {
+			b.WriteString("[" + seg + "]")
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteString(seg)
+	}
+	return b.String()
+}
+
+func schemaDetail(field string, err *openapi3.SchemaError) errorx.Detail {
+	switch err.SchemaField {
+	case "required":
+		return errorx.Detail{Field: field, Reason: domain.ReasonRequired, Message: field + " is required"}
+	case "type":
+		return errorx.Detail{Field: field, Reason: "invalid_type", Message: fmt.Sprintf("%s %s", field, err.Reason)}
+	default:
+		return errorx.Detail{Field: field, Reason: domain.ReasonInvalidFormat, Message: fmt.Sprintf("%s %s", field, err.Reason)}
+	}
+}
@@ -0,0 +1,223 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/FormalYou/clean-architecture-blog/internal/application/usecase/articleerr"
+	"github.com/FormalYou/clean-architecture-blog/internal/errorx"
+)
+
+const testSpec = `
+openapi: 3.0.0
+info:
+  title: test
+  version: 1.0.0
+servers:
+  - url: /api/v1
+paths:
+  /articles:
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [title, content]
+              properties:
+                title:
+                  type: string
+                content:
+                  type: string
+                tags:
+                  type: array
+                  items:
+                    type: string
+      responses:
+        '201':
+          description: created
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [id]
+                properties:
+                  id:
+                    type: integer
+        default:
+          $ref: '#/components/responses/Error'
+  /articles/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+      responses:
+        '200':
+          description: article
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [id]
+                properties:
+                  id:
+                    type: integer
+        default:
+          $ref: '#/components/responses/Error'
+components:
+  responses:
+    Error:
+      description: error
+      content:
+        application/json:
+          schema:
+            type: object
+            required: [code, message]
+            properties:
+              code:
+                type: integer
+              message:
+                type: string
+`
+
+func setupOpenAPIRouter(t *testing.T, handler gin.HandlerFunc) *gin.Engine {
+	t.Helper()
+	spec, err := NewOpenAPISpec([]byte(testSpec))
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(ValidateResponses(spec, zap.NewNop()), ErrorHandler(zap.NewNop()), ValidateRequests(spec))
+	router.POST("/api/v1/articles/", handler)
+	router.GET("/api/v1/articles/:id", handler)
+	router.GET("/healthz", handler)
+	return router
+}
+
+func TestOpenAPISpec_Routes(t *testing.T) {
+	spec, err := NewOpenAPISpec([]byte(testSpec))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"GET /api/v1/articles/:id", "POST /api/v1/articles"}, spec.Routes())
+
+	_, err = NewOpenAPISpec([]byte("openapi: 3.0.0\npaths: {}\n"))
+	assert.Error(t, err)
+}
+
+func TestValidateRequests(t *testing.T) {
+	router := setupOpenAPIRouter(t, func(c *gin.Context) {
+		c.JSON(http.StatusCreated, gin.H{"id": 1})
+	})
+
+	testCases := []struct {
+		name            string
+		method, path    string
+		body            string
+		expectedStatus  int
+		expectedDetails []errorx.Detail
+	}{
+		{
+			name:   "Valid Body",
+			method: http.MethodPost, path: "/api/v1/articles/",
+			body:           `{"title":"t","content":"c","tags":["go"]}`,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:   "Every Body Violation",
+			method: http.MethodPost, path: "/api/v1/articles/",
+			body:           `{"content":"c","tags":["go",7]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedDetails: []errorx.Detail{
+				{Field: "title", Reason: "required", Message: "title is required"},
+				{Field: "tags[1]", Reason: "invalid_type", Message: `tags[1] value must be a string`},
+			},
+		},
+		{
+			name:   "Invalid Path Parameter",
+			method: http.MethodGet, path: "/api/v1/articles/abc",
+			expectedStatus: http.StatusBadRequest,
+			expectedDetails: []errorx.Detail{
+				{Field: "id", Reason: "invalid_format", Message: "value abc: an invalid integer: invalid syntax"},
+			},
+		},
+		{
+			name:   "Undocumented Route",
+			method: http.MethodGet, path: "/healthz",
+			expectedStatus: http.StatusCreated,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code, w.Body.String())
+			if tc.expectedDetails != nil {
+				var body errorx.BusinessError
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+				assert.Equal(t, errorx.CodeInvalidParams, body.Code)
+				assert.ElementsMatch(t, tc.expectedDetails, body.Details)
+			}
+		})
+	}
+}
+
+func TestValidateResponses(t *testing.T) {
+	t.Run("Matching Response Is Passed Through", func(t *testing.T) {
+		router := setupOpenAPIRouter(t, func(c *gin.Context) {
+			c.Header("X-Test", "kept")
+			c.JSON(http.StatusOK, gin.H{"id": 7})
+		})
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/articles/7", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "kept", w.Header().Get("X-Test"))
+		assert.JSONEq(t, `{"id":7}`, w.Body.String())
+	})
+
+	t.Run("Mismatching Response Is Replaced", func(t *testing.T) {
+		router := setupOpenAPIRouter(t, func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{"id": "seven"})
+		})
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/articles/7", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		var body errorx.BusinessError
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, errorx.CodeInternalServerError, body.Code)
+		assert.Contains(t, body.Message, "response does not match the OpenAPI spec")
+	})
+
+	t.Run("Error Responses Are Checked", func(t *testing.T) {
+		router := setupOpenAPIRouter(t, func(c *gin.Context) {
+			_ = c.Error(errorx.New(articleerr.CodeArticleNotFound, nil))
+		})
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/articles/7", nil))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		router = setupOpenAPIRouter(t, func(c *gin.Context) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		})
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/articles/7", nil))
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/FormalYou/clean-architecture-blog/internal/application/contracts"
+	"github.com/FormalYou/clean-architecture-blog/internal/interfaces/shared"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// RateLimit creates a Gin middleware enforcing the policy of the named route
+// group. Every limited response carries the RateLimit-Limit,
+// RateLimit-Remaining and RateLimit-Reset headers; a rejected request gets
+// CodeTooManyRequests with Retry-After. Groups without a policy, exempt
+// users and tokens with a trusted scope pass through. If the limiter fails
+// the request is let through rather than turning an outage into errors.
+func RateLimit(group string, limiter contracts.RateLimiter, settings *shared.RateLimitSettings, authSvc contracts.AuthService, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok {
+			token = ""
+		}
+		who := shared.IdentifyCaller(c.Request.Context(), token, authSvc)
+		result, subject, limited, err := settings.Check(c.Request.Context(), limiter, group, who, c.ClientIP())
+		if err != nil {
+			logger.Warn("rate limiter unavailable, allowing request", zap.String("group", group), zap.Error(err))
+			c.Next()
+			return
+		}
+		if !limited {
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(shared.CeilSeconds(result.Reset)))
+		if !result.Allowed {
+			retryAfter := shared.RetryAfter(result)
+			c.Header("Retry-After", retryAfter)
+			_ = c.Error(shared.RateLimitExceeded(group, subject, retryAfter))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/FormalYou/clean-architecture-blog/internal/application/contracts"
+	"github.com/FormalYou/clean-architecture-blog/internal/application/contracts/mocks"
+	"github.com/FormalYou/clean-architecture-blog/internal/infrastructure/auth"
+	"github.com/FormalYou/clean-architecture-blog/internal/infrastructure/ratelimit"
+	"github.com/FormalYou/clean-architecture-blog/internal/interfaces/shared"
+)
+
+const testSecret = "a-sufficiently-long-secret"
+
+func signToken(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	claims["exp"] = time.Now().Add(time.Hour).Unix()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testSecret))
+	require.NoError(t, err)
+	return token
+}
+
+func setupRateLimitRouter(settings *shared.RateLimitSettings) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	authSvc := auth.NewJWTAuthService(testSecret)
+	limiter := ratelimit.NewMemoryLimiter()
+
+	router := gin.New()
+	router.Use(ErrorHandler(zap.NewNop()))
+	router.POST("/login", RateLimit("auth", limiter, settings, authSvc, zap.NewNop()), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	writes := router.Group("/articles", AuthMiddleware(authSvc, zap.NewNop()), RateLimit("write", limiter, settings, authSvc, zap.NewNop()))
+	writes.POST("", func(c *gin.Context) { c.Status(http.StatusCreated) })
+	return router
+}
+
+func perform(router http.Handler, method, path, ip, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.RemoteAddr = ip + ":1234"
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimit(t *testing.T) {
+	settings := shared.NewRateLimitSettings(shared.RateLimitRules{
+		Policies: map[string]shared.RateLimitPolicy{
+			"auth":  {Limit: 2, Window: time.Minute, KeyBy: shared.RateLimitByIP},
+			"write": {Limit: 1, Window: time.Minute, KeyBy: shared.RateLimitByUser},
+		},
+		ExemptUserIDs: []int64{1},
+		TrustedScopes: []string{"internal"},
+	})
+	router := setupRateLimitRouter(settings)
+
+	t.Run("Rejects with headers once the quota is used", func(t *testing.T) {
+		w := perform(router, http.MethodPost, "/login", "10.0.0.1", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "2", w.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, "1", w.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "30", w.Header().Get("RateLimit-Reset"))
+
+		perform(router, http.MethodPost, "/login", "10.0.0.1", "")
+		w = perform(router, http.MethodPost, "/login", "10.0.0.1", "")
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "30", w.Header().Get("Retry-After"))
+		assert.JSONEq(t, `{"code":10006,"message":"Too Many Requests, retry in 30 seconds"}`, w.Body.String())
+
+		w = perform(router, http.MethodPost, "/login", "10.0.0.2", "")
+		assert.Equal(t, http.StatusOK, w.Code, "other addresses have their own quota")
+	})
+
+	t.Run("Counts authenticated writes per user", func(t *testing.T) {
+		alice, bob := signToken(t, jwt.MapClaims{"sub": 2}), signToken(t, jwt.MapClaims{"sub": 3})
+
+		assert.Equal(t, http.StatusCreated, perform(router, http.MethodPost, "/articles", "10.0.0.3", alice).Code)
+		assert.Equal(t, http.StatusTooManyRequests, perform(router, http.MethodPost, "/articles", "10.0.0.4", alice).Code)
+		assert.Equal(t, http.StatusCreated, perform(router, http.MethodPost, "/articles", "10.0.0.3", bob).Code)
+	})
+
+	t.Run("Admins and trusted scopes are exempt", func(t *testing.T) {
+		admin := signToken(t, jwt.MapClaims{"sub": 1})
+		service := signToken(t, jwt.MapClaims{"sub": 4, "scope": "read internal"})
+		for i := 0; i < 3; i++ {
+			w := perform(router, http.MethodPost, "/articles", "10.0.0.5", admin)
+			assert.Equal(t, http.StatusCreated, w.Code)
+			assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+			assert.Equal(t, http.StatusCreated, perform(router, http.MethodPost, "/articles", "10.0.0.5", service).Code)
+			assert.Equal(t, http.StatusOK, perform(router, http.MethodPost, "/login", "10.0.0.6", service).Code)
+		}
+	})
+
+	t.Run("Reloaded rules apply to the next request", func(t *testing.T) {
+		require.Equal(t, http.StatusTooManyRequests, perform(router, http.MethodPost, "/login", "10.0.0.1", "").Code)
+
+		settings.Store(shared.RateLimitRules{})
+		w := perform(router, http.MethodPost, "/login", "10.0.0.1", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+	})
+}
+
+func TestRateLimit_LimiterFailureAllowsRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	limiter := mocks.NewMockRateLimiter(ctrl)
+	limiter.EXPECT().Allow(gomock.Any(), "auth:ip:10.0.0.1", 1, time.Minute).Return(contracts.RateLimit{}, errors.New("redis down"))
+
+	settings := shared.NewRateLimitSettings(shared.RateLimitRules{
+		Policies: map[string]shared.RateLimitPolicy{"auth": {Limit: 1, Window: time.Minute}},
+	})
+	router := gin.New()
+	router.POST("/login", RateLimit("auth", limiter, settings, auth.NewJWTAuthService(testSecret), zap.NewNop()), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := perform(router, http.MethodPost, "/login", "10.0.0.1", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+}
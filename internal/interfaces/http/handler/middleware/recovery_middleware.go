@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/FormalYou/clean-architecture-blog/internal/errorx"
+	zaplog "github.com/FormalYou/clean-architecture-blog/internal/infrastructure/log"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Recovery creates a Gin middleware that turns a panic in a later handler
+// into CodeInternalServerError, so it is rendered like any other error. It
+// must be registered after ErrorHandler.
+func Recovery(logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+			// http.ErrAbortHandler deliberately aborts the response.
+			if err, ok := r.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+				panic(r)
+			}
+			logFields := []zap.Field{zap.Any("panic", r), zap.String("request_uri", c.Request.RequestURI), zap.Stack("stack")}
+			logFields = append(logFields, zaplog.TraceFields(c.Request.Context())...)
+			logger.Error("panic recovered", logFields...)
+
+			_ = c.Error(errorx.New(errorx.CodeInternalServerError, fmt.Errorf("panic: %v", r)))
+			c.Abort()
+		}()
+		c.Next()
+	}
+}
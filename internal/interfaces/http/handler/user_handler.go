@@ -6,6 +6,7 @@ import (
 	"github.com/FormalYou/clean-architecture-blog/domain"
 	"github.com/FormalYou/clean-architecture-blog/internal/application/usecase"
 	"github.com/FormalYou/clean-architecture-blog/internal/errorx"
+	"github.com/FormalYou/clean-architecture-blog/internal/infrastructure/log"
 	"github.com/FormalYou/clean-architecture-blog/internal/interfaces/http/dto"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -39,12 +40,12 @@ func (h *UserHandler) Register(c *gin.Context) {
 		Email:        req.Email,
 	}
 
-	if err := h.userUsecase.Register(user); err != nil {
+	if err := h.userUsecase.Register(c.Request.Context(), user); err != nil {
 		c.Error(err) // Pass the error to the middleware
 		return
 	}
 
-	h.logger.Info("user registered successfully", zap.String("username", req.Username))
+	log.WithTrace(c.Request.Context(), h.logger).Info("user registered successfully", zap.String("username", req.Username))
 	c.JSON(http.StatusCreated, gin.H{"message": "User registered successfully"})
 }
 
@@ -56,12 +57,12 @@ func (h *UserHandler) Login(c *gin.Context) {
 		return
 	}
 
-	token, err := h.userUsecase.Login(req.Email, req.Password)
+	token, err := h.userUsecase.Login(c.Request.Context(), req.Email, req.Password)
 	if err != nil {
 		c.Error(err) // Pass the error to the middleware
 		return
 	}
 
-	h.logger.Info("user logged in successfully", zap.String("email", req.Email))
+	log.WithTrace(c.Request.Context(), h.logger).Info("user logged in successfully", zap.String("email", req.Email))
 	c.JSON(http.StatusOK, dto.LoginResponse{Token: token})
 }
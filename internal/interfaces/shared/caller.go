@@ -0,0 +1,64 @@
+// Package shared holds what the HTTP, gRPC and GraphQL adapters have in
+// common: who a call comes from and the rate limit and idempotency policies
+// applied to it, so no transport depends on another.
+package shared
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/FormalYou/clean-architecture-blog/internal/application/contracts"
+)
+
+// ContextKey is a custom type for context keys to avoid collisions.
+type ContextKey string
+
+// UserIDKey is the key for the authenticated user's ID in context.
+const UserIDKey ContextKey = "userID"
+
+// WithUserID returns a copy of ctx carrying the authenticated user's ID.
+func WithUserID(ctx context.Context, userID int64) context.Context {
+	return context.WithValue(ctx, UserIDKey, userID)
+}
+
+// UserIDFrom returns the authenticated user's ID stored in ctx, if any.
+func UserIDFrom(ctx context.Context) (int64, bool) {
+	userID, ok := ctx.Value(UserIDKey).(int64)
+	return userID, ok
+}
+
+// Caller is who a request comes from, as far as its credentials tell.
+type Caller struct {
+	UserID int64 // 0 when anonymous
+	Token  string
+	Scopes []string
+}
+
+// IdentifyCaller reads the caller from the user ID that authentication
+// stored in ctx or, on public routes, from token when it is valid. Invalid
+// tokens count as anonymous.
+func IdentifyCaller(ctx context.Context, token string, authSvc contracts.AuthService) Caller {
+	who := Caller{Token: token}
+	if userID, ok := UserIDFrom(ctx); ok {
+		who.UserID = userID
+	} else if who.Token != "" {
+		if userID, err := authSvc.ValidateToken(who.Token); err == nil {
+			who.UserID = userID
+		} else {
+			who.Token = ""
+		}
+	}
+	if provider, ok := authSvc.(contracts.ScopeProvider); ok && who.Token != "" {
+		who.Scopes, _ = provider.TokenScopes(who.Token)
+	}
+	return who
+}
+
+// IdempotencyScope keeps one caller's idempotency keys apart from everyone
+// else's: the authenticated user's, or those of clientIP when anonymous.
+func IdempotencyScope(ctx context.Context, clientIP string) string {
+	if userID, ok := UserIDFrom(ctx); ok {
+		return "user:" + strconv.FormatInt(userID, 10)
+	}
+	return "ip:" + clientIP
+}
@@ -0,0 +1,12 @@
+package shared
+
+import "time"
+
+// IdempotencyOptions configures how idempotent requests are remembered.
+type IdempotencyOptions struct {
+	// TTL is how long a completed response is replayed.
+	TTL time.Duration
+	// LockTTL bounds how long an unfinished request holds its key, in case
+	// the instance handling it dies.
+	LockTTL time.Duration
+}
@@ -0,0 +1,120 @@
+package shared
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/FormalYou/clean-architecture-blog/internal/application/contracts"
+	"github.com/FormalYou/clean-architecture-blog/internal/errorx"
+)
+
+// What a rate limit policy counts requests by.
+const (
+	RateLimitByIP    = "ip"
+	RateLimitByUser  = "user"
+	RateLimitByToken = "token"
+)
+
+// RateLimitPolicy allows Limit requests per Window for each caller of a
+// route group. Callers are told apart by KeyBy; anonymous callers of a
+// user or token policy are counted by IP.
+type RateLimitPolicy struct {
+	Limit  int
+	Window time.Duration
+	KeyBy  string
+}
+
+// RateLimitRules are the policies per route group and the callers exempt
+// from all of them.
+type RateLimitRules struct {
+	Policies      map[string]RateLimitPolicy
+	ExemptUserIDs []int64
+	TrustedScopes []string
+}
+
+// RateLimitSettings holds the rules in effect and lets them be replaced
+// while the server runs.
+type RateLimitSettings struct {
+	rules atomic.Pointer[RateLimitRules]
+}
+
+// NewRateLimitSettings creates settings starting with rules.
+func NewRateLimitSettings(rules RateLimitRules) *RateLimitSettings {
+	s := &RateLimitSettings{}
+	s.Store(rules)
+	return s
+}
+
+// Store replaces the rules; requests already being limited are unaffected.
+func (s *RateLimitSettings) Store(rules RateLimitRules) {
+	s.rules.Store(&rules)
+}
+
+// Check counts a call of who, connecting from clientIP, against the policy
+// of the named group. limited is false, and nothing is counted, when the
+// group has no policy or who is exempt. Every transport shares the policies
+// of the HTTP route groups through Check.
+func (s *RateLimitSettings) Check(ctx context.Context, limiter contracts.RateLimiter, group string, who Caller, clientIP string) (result contracts.RateLimit, subject string, limited bool, err error) {
+	rules := s.rules.Load()
+	policy, ok := rules.Policies[group]
+	if !ok || policy.Limit <= 0 || policy.Window <= 0 || rules.exempt(who) {
+		return contracts.RateLimit{}, "", false, nil
+	}
+
+	subject = who.subject(policy.KeyBy, clientIP)
+	result, err = limiter.Allow(ctx, group+":"+subject, policy.Limit, policy.Window)
+	if err != nil {
+		return contracts.RateLimit{}, subject, false, err
+	}
+	return result, subject, true, nil
+}
+
+// RetryAfter is the whole number of seconds a rejected caller should wait.
+func RetryAfter(result contracts.RateLimit) string {
+	return strconv.Itoa(CeilSeconds(result.RetryAfter))
+}
+
+// RateLimitExceeded is the error reported to a caller rejected by the policy
+// of group; retryAfter is from RetryAfter.
+func RateLimitExceeded(group, subject, retryAfter string) *errorx.DetailError {
+	return errorx.New(errorx.CodeTooManyRequests,
+		fmt.Errorf("rate limit of route group %q exceeded by %s", group, subject)).
+		WithParams(map[string]string{"retry_after": retryAfter})
+}
+
+// CeilSeconds rounds d up to whole seconds, as rate limit headers require.
+func CeilSeconds(d time.Duration) int {
+	return int((d + time.Second - 1) / time.Second)
+}
+
+// subject is the bucket the caller is counted in under keyBy.
+func (who Caller) subject(keyBy, clientIP string) string {
+	switch {
+	case keyBy == RateLimitByUser && who.UserID != 0:
+		return "user:" + strconv.FormatInt(who.UserID, 10)
+	case keyBy == RateLimitByToken && who.Token != "":
+		// Only a digest is kept so tokens never end up in Redis or the logs.
+		sum := sha256.Sum256([]byte(who.Token))
+		return "token:" + hex.EncodeToString(sum[:16])
+	default:
+		return "ip:" + clientIP
+	}
+}
+
+func (r *RateLimitRules) exempt(who Caller) bool {
+	if who.UserID != 0 && slices.Contains(r.ExemptUserIDs, who.UserID) {
+		return true
+	}
+	for _, scope := range who.Scopes {
+		if slices.Contains(r.TrustedScopes, scope) {
+			return true
+		}
+	}
+	return false
+}
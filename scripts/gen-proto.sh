@@ -0,0 +1,13 @@
+#!/bin/bash
+# 根据 api/proto 下的 .proto 文件重新生成 gRPC 代码
+# 需要 protoc、protoc-gen-go 和 protoc-gen-go-grpc：
+#   go install google.golang.org/protobuf/cmd/protoc-gen-go@v1.36.6
+#   go install google.golang.org/grpc/cmd/protoc-gen-go-grpc@v1.5.1
+
+set -e
+
+echo "Generating protobuf code..."
+protoc -I api/proto \
+    --go_out=api/proto --go_opt=paths=source_relative \
+    --go-grpc_out=api/proto --go-grpc_opt=paths=source_relative \
+    api/proto/blog/v1/*.proto
@@ -4,5 +4,8 @@
 
 set -e
 
+echo "Applying database migrations..."
+go run ./cmd/server migrate up
+
 echo "Running E2E tests..."
 go test -v -cover -race ./tests/e2e
\ No newline at end of file
@@ -1,6 +1,8 @@
 #!/bin/bash
 # 运行集成测试
 # 根据约定，这些测试位于 internal/interfaces/http/handler/ 目录下
+# 默认使用内存 SQLite 和进程内 Redis，无需外部服务；
+# 设置 INTEGRATION_EXTERNAL=1 则使用 configs/config.yaml 中配置的数据库和 Redis
 
 set -e
 
@@ -14,7 +14,7 @@ import (
 
 func TestArticleWorkflow(t *testing.T) {
 	var token string
-	var articleID uint
+	var articleID int64
 
 	username := fmt.Sprintf("testuser_%d", time.Now().UnixNano())
 	password := "password123"